@@ -0,0 +1,154 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type Kind string
+
+const (
+	KindInvalidArgument Kind = "invalid-argument"
+	KindNotFound        Kind = "not-found"
+	KindConflict        Kind = "conflict"
+	KindForbidden       Kind = "forbidden"
+	KindUnauthenticated Kind = "unauthenticated"
+	KindValidation      Kind = "validation"
+	KindTooManyRequests Kind = "too-many-requests"
+)
+
+// FieldError describes one invalid field of a request body.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Error is a domain error that is safe to show to API clients. Err keeps the
+// underlying cause for logs and errors.Is without exposing it in responses.
+type Error struct {
+	Kind    Kind
+	Message string
+	Field   string
+	Fields  []FieldError
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Status returns the HTTP status code for the error kind.
+func (e *Error) Status() int {
+	switch e.Kind {
+	case KindInvalidArgument:
+		return fiber.StatusBadRequest
+	case KindNotFound:
+		return fiber.StatusNotFound
+	case KindConflict:
+		return fiber.StatusConflict
+	case KindForbidden:
+		return fiber.StatusForbidden
+	case KindUnauthenticated:
+		return fiber.StatusUnauthorized
+	case KindValidation:
+		return fiber.StatusUnprocessableEntity
+	case KindTooManyRequests:
+		return fiber.StatusTooManyRequests
+	}
+	return fiber.StatusInternalServerError
+}
+
+func newError(kind Kind, format string, args ...any) *Error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+func InvalidArgument(format string, args ...any) *Error {
+	return newError(KindInvalidArgument, format, args...)
+}
+
+func NotFound(format string, args ...any) *Error {
+	return newError(KindNotFound, format, args...)
+}
+
+func Conflict(format string, args ...any) *Error {
+	return newError(KindConflict, format, args...)
+}
+
+func Forbidden(format string, args ...any) *Error {
+	return newError(KindForbidden, format, args...)
+}
+
+func Unauthenticated(format string, args ...any) *Error {
+	return newError(KindUnauthenticated, format, args...)
+}
+
+func TooManyRequests(format string, args ...any) *Error {
+	return newError(KindTooManyRequests, format, args...)
+}
+
+// Validation reports a request body that failed field-level validation.
+func Validation(fields []FieldError) *Error {
+	return &Error{Kind: KindValidation, Message: "request validation failed", Fields: fields}
+}
+
+// InvalidID reports a malformed ObjectID hex string for the named entity.
+func InvalidID(entity string, err error) *Error {
+	return &Error{Kind: KindInvalidArgument, Message: "invalid " + entity + " ID", Err: err}
+}
+
+// FromMongo translates driver errors into domain errors: missing documents
+// become NotFound for entity and duplicate keys become Conflict. Any other
+// error is returned unchanged.
+func FromMongo(err error, entity string) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return &Error{Kind: KindNotFound, Message: entity + " not found", Err: err}
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		field := duplicateKeyField(err)
+		message := entity + " already exists"
+		if field != "" {
+			message = entity + " with this " + field + " already exists"
+		}
+		return &Error{Kind: KindConflict, Message: message, Field: field, Err: err}
+	}
+	return err
+}
+
+// duplicateKeyPattern extracts the first key from the server message, e.g.
+// `E11000 duplicate key error collection: db.users index: users_email_unique dup key: { email: "a@b.c" }`.
+var duplicateKeyPattern = regexp.MustCompile(`dup key: \{ ?"?([\w.]+)"?:`)
+
+func duplicateKeyField(err error) string {
+	if m := duplicateKeyPattern.FindStringSubmatch(err.Error()); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// As returns the domain error wrapped in err, if any.
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	ok := errors.As(err, &appErr)
+	return appErr, ok
+}
+
+// Is reports whether err is a domain error of the given kind.
+func Is(err error, kind Kind) bool {
+	appErr, ok := As(err)
+	return ok && appErr.Kind == kind
+}
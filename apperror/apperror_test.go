@@ -0,0 +1,127 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func duplicateKeyError(message string) error {
+	return mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000, Message: message}}}
+}
+
+func TestDuplicateKeyField(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{
+			name:    "unquoted field",
+			message: `E11000 duplicate key error collection: coursy.users index: users_email_unique dup key: { email: "a@b.c" }`,
+			want:    "email",
+		},
+		{
+			name:    "quoted field",
+			message: `E11000 duplicate key error collection: coursy.subjects index: subjects_subjectCode_unique dup key: { "subjectCode": "CS101" }`,
+			want:    "subjectCode",
+		},
+		{
+			name:    "dotted field",
+			message: `E11000 duplicate key error collection: coursy.x index: x_a.b dup key: { a.b: 1 }`,
+			want:    "a.b",
+		},
+		{
+			name:    "collation key",
+			message: `E11000 duplicate key error collection: coursy.users index: users_email_unique collation: { locale: "en", strength: 2 } dup key: { email: "0x2B2F" }`,
+			want:    "email",
+		},
+		{
+			name:    "no key in message",
+			message: `E11000 duplicate key error collection: coursy.users`,
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := duplicateKeyField(duplicateKeyError(tt.message)); got != tt.want {
+				t.Errorf("duplicateKeyField() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromMongo(t *testing.T) {
+	other := errors.New("connection refused")
+
+	tests := []struct {
+		name        string
+		err         error
+		wantKind    Kind
+		wantMessage string
+		wantField   string
+		wantSame    bool
+	}{
+		{name: "nil", err: nil},
+		{
+			name:        "no documents",
+			err:         mongo.ErrNoDocuments,
+			wantKind:    KindNotFound,
+			wantMessage: "user not found",
+		},
+		{
+			name:        "wrapped no documents",
+			err:         fmt.Errorf("find: %w", mongo.ErrNoDocuments),
+			wantKind:    KindNotFound,
+			wantMessage: "user not found",
+		},
+		{
+			name:        "duplicate key with field",
+			err:         duplicateKeyError(`E11000 duplicate key error collection: coursy.users index: users_email_unique dup key: { email: "a@b.c" }`),
+			wantKind:    KindConflict,
+			wantMessage: "user with this email already exists",
+			wantField:   "email",
+		},
+		{
+			name:        "duplicate key without field",
+			err:         duplicateKeyError(`E11000 duplicate key error`),
+			wantKind:    KindConflict,
+			wantMessage: "user already exists",
+		},
+		{name: "other error", err: other, wantSame: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FromMongo(tt.err, "user")
+			switch {
+			case tt.err == nil:
+				if got != nil {
+					t.Fatalf("FromMongo(nil) = %v, want nil", got)
+				}
+				return
+			case tt.wantSame:
+				if got != tt.err {
+					t.Fatalf("FromMongo() = %v, want the error unchanged", got)
+				}
+				return
+			}
+
+			appErr, ok := As(got)
+			if !ok {
+				t.Fatalf("FromMongo() = %v, want an *Error", got)
+			}
+			if appErr.Kind != tt.wantKind || appErr.Message != tt.wantMessage || appErr.Field != tt.wantField {
+				t.Errorf("FromMongo() = {%v %q %q}, want {%v %q %q}",
+					appErr.Kind, appErr.Message, appErr.Field, tt.wantKind, tt.wantMessage, tt.wantField)
+			}
+			if !reflect.DeepEqual(appErr.Err, tt.err) {
+				t.Errorf("FromMongo() does not wrap the driver error")
+			}
+		})
+	}
+}
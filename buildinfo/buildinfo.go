@@ -0,0 +1,25 @@
+package buildinfo
+
+import "runtime"
+
+// These values are injected at build time, for example:
+//
+//	go build -ldflags "-X BackendCoursyclopedia/buildinfo.Commit=$(git rev-parse HEAD) -X BackendCoursyclopedia/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
+
+type Info struct {
+	Commit    string `json:"commit"`
+	BuildTime string `json:"buildTime"`
+	GoVersion string `json:"goVersion"`
+}
+
+func Get() Info {
+	return Info{
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+}
@@ -0,0 +1,45 @@
+// Package catalogio reads and writes the subject catalogue in the file
+// formats exchanged with the registrar and other departments.
+package catalogio
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"path/filepath"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatJSON   Format = "json"
+	FormatNDJSON Format = "ndjson"
+	FormatXLSX   Format = "xlsx"
+)
+
+// ParseFormat accepts a format name as given in a query string or flag.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(s))); f {
+	case FormatCSV, FormatJSON, FormatNDJSON, FormatXLSX:
+		return f, nil
+	}
+	return "", apperror.InvalidArgument("unsupported format %q; use csv, json, ndjson or xlsx", s)
+}
+
+// FormatFromFilename guesses the format from a file extension.
+func FormatFromFilename(name string) (Format, error) {
+	return ParseFormat(strings.TrimPrefix(filepath.Ext(name), "."))
+}
+
+// ContentType returns the MIME type served for f.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "application/json"
+}
@@ -0,0 +1,240 @@
+package catalogio
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/model/subjectmodel"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// columns maps normalised header names to SubjectImportRow fields so that
+// spreadsheets may say "Subject Code", "subject_code" or "code".
+var columns = map[string]string{
+	"subjectcode":        "subjectCode",
+	"code":               "subjectCode",
+	"name":               "name",
+	"subjectname":        "name",
+	"title":              "name",
+	"major":              "major",
+	"majorname":          "major",
+	"majorid":            "majorId",
+	"description":        "subjectDescription",
+	"subjectdescription": "subjectDescription",
+	"campus":             "campus",
+	"credit":             "credit",
+	"credits":            "credit",
+	"professors":         "professors",
+	"prerequisite":       "preRequisite",
+	"prerequisites":      "preRequisite",
+	"corequisite":        "coRequisite",
+	"corequisites":       "coRequisite",
+	"status":             "subjectStatus",
+	"subjectstatus":      "subjectStatus",
+	"availableduration":  "availableDuration",
+}
+
+// ReadSubjects decodes subjects from r. CSV and XLSX files need a header row;
+// unknown columns are ignored. JSON is an array of objects and NDJSON one
+// object per line, both using the field names of SubjectImportRow.
+func ReadSubjects(r io.Reader, format Format) ([]subjectmodel.SubjectImportRow, error) {
+	switch format {
+	case FormatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, apperror.InvalidArgument("invalid CSV: %v", err)
+		}
+		return fromTable(records)
+	case FormatXLSX:
+		f, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, apperror.InvalidArgument("invalid XLSX: %v", err)
+		}
+		defer f.Close()
+		records, err := f.GetRows(f.GetSheetName(0))
+		if err != nil {
+			return nil, apperror.InvalidArgument("invalid XLSX: %v", err)
+		}
+		return fromTable(records)
+	case FormatJSON:
+		var objects []json.RawMessage
+		if err := json.NewDecoder(r).Decode(&objects); err != nil {
+			return nil, apperror.InvalidArgument("invalid JSON: %v", err)
+		}
+		rows := make([]subjectmodel.SubjectImportRow, len(objects))
+		for i, object := range objects {
+			row, err := decodeRow(object)
+			if err != nil {
+				return nil, apperror.InvalidArgument("invalid JSON in item %d: %v", i+1, err)
+			}
+			row.Line = i + 1
+			rows[i] = row
+		}
+		return rows, nil
+	case FormatNDJSON:
+		var rows []subjectmodel.SubjectImportRow
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for line := 1; scanner.Scan(); line++ {
+			if strings.TrimSpace(scanner.Text()) == "" {
+				continue
+			}
+			row, err := decodeRow(scanner.Bytes())
+			if err != nil {
+				return nil, apperror.InvalidArgument("invalid JSON on line %d: %v", line, err)
+			}
+			row.Line = line
+			rows = append(rows, row)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, apperror.InvalidArgument("invalid NDJSON: %v", err)
+		}
+		return rows, nil
+	}
+	return nil, apperror.InvalidArgument("unsupported format %q", format)
+}
+
+func fromTable(records [][]string) ([]subjectmodel.SubjectImportRow, error) {
+	if len(records) == 0 {
+		return nil, apperror.InvalidArgument("file is empty")
+	}
+
+	header := make([]string, len(records[0]))
+	found := map[string]bool{}
+	for i, name := range records[0] {
+		header[i] = columns[normaliseHeader(name)]
+		if header[i] != "" {
+			found[header[i]] = true
+		}
+	}
+	if !found["subjectCode"] {
+		return nil, apperror.InvalidArgument("missing subject code column")
+	}
+
+	rows := make([]subjectmodel.SubjectImportRow, 0, len(records)-1)
+	for i, record := range records[1:] {
+		if isBlank(record) {
+			continue
+		}
+		row := subjectmodel.SubjectImportRow{Line: i + 1, Columns: found}
+		for j, cell := range record {
+			if j >= len(header) || header[j] == "" {
+				continue
+			}
+			if err := setField(&row, header[j], strings.TrimSpace(cell)); err != nil {
+				row.ParseErrors = append(row.ParseErrors, apperror.FieldError{
+					Field: header[j], Rule: "type", Message: err.Error(),
+				})
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// decodeRow decodes one JSON object and records which keys it has.
+func decodeRow(data []byte) (subjectmodel.SubjectImportRow, error) {
+	var row subjectmodel.SubjectImportRow
+	if err := json.Unmarshal(data, &row); err != nil {
+		return row, err
+	}
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return row, err
+	}
+	// encoding/json matches keys case-insensitively, and so must this.
+	row.Columns = make(map[string]bool, len(keys))
+	for key := range keys {
+		for _, field := range columns {
+			if strings.EqualFold(key, field) {
+				row.Columns[field] = true
+			}
+		}
+	}
+	return row, nil
+}
+
+func setField(row *subjectmodel.SubjectImportRow, field, cell string) error {
+	switch field {
+	case "subjectCode":
+		row.SubjectCode = cell
+	case "name":
+		row.Name = cell
+	case "major":
+		row.Major = cell
+	case "majorId":
+		row.MajorID = cell
+	case "subjectDescription":
+		row.SubjectDescription = cell
+	case "campus":
+		row.Campus = cell
+	case "subjectStatus":
+		row.SubjectStatus = strings.ToUpper(cell)
+	case "professors":
+		row.Professors = splitList(cell)
+	case "preRequisite":
+		row.PreRequisite = splitList(cell)
+	case "coRequisite":
+		row.CoRequisite = splitList(cell)
+	case "credit":
+		if cell == "" {
+			return nil
+		}
+		n, err := strconv.Atoi(cell)
+		if err != nil {
+			return errors.New("must be a whole number")
+		}
+		row.Credit = &n
+	case "availableDuration":
+		if cell == "" {
+			return nil
+		}
+		n, err := strconv.Atoi(cell)
+		if err != nil {
+			return errors.New("must be a whole number")
+		}
+		row.AvailableDuration = n
+	}
+	return nil
+}
+
+// splitList splits a cell such as "CSX3001, CSX3002" or "CSX3001;CSX3002".
+func splitList(cell string) []string {
+	parts := strings.FieldsFunc(cell, func(r rune) bool { return r == ',' || r == ';' || r == '|' })
+	list := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			list = append(list, p)
+		}
+	}
+	return list
+}
+
+func normaliseHeader(name string) string {
+	name = strings.TrimPrefix(name, "\ufeff")
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '_', '-', '.':
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(name)))
+}
+
+func isBlank(record []string) bool {
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,203 @@
+package catalogio
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/model/subjectmodel"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func intPtr(n int) *int { return &n }
+
+// xlsxFile builds a workbook whose first sheet holds records.
+func xlsxFile(t *testing.T, records [][]string) *bytes.Buffer {
+	t.Helper()
+	f := excelize.NewFile()
+	defer f.Close()
+	sheet := f.GetSheetName(0)
+	for i, record := range records {
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		row := make([]interface{}, len(record))
+		for j, v := range record {
+			row[j] = v
+		}
+		if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestReadSubjects(t *testing.T) {
+	table := [][]string{
+		{"\ufeffSubject Code", "Title", "major_name", "Credits", "Pre-Requisites", "status", "Notes"},
+		{"CSX3001", "Programming", "Computer Science", "3", "", "available", "ignored"},
+		{"", "", "", "", "", "", ""},
+		{"CSX3002", "OOP", "Computer Science", "three", "CSX3001; CSX3000 |", "", ""},
+	}
+	tableColumns := map[string]bool{
+		"subjectCode": true, "name": true, "major": true, "credit": true, "preRequisite": true, "subjectStatus": true,
+	}
+	tableRows := []subjectmodel.SubjectImportRow{
+		{
+			SubjectCode: "CSX3001", Name: "Programming", Major: "Computer Science", Credit: intPtr(3),
+			PreRequisite: []string{}, SubjectStatus: "AVAILABLE", Line: 1, Columns: tableColumns,
+		},
+		{
+			SubjectCode: "CSX3002", Name: "OOP", Major: "Computer Science",
+			PreRequisite: []string{"CSX3001", "CSX3000"}, Line: 3, Columns: tableColumns,
+			ParseErrors: []apperror.FieldError{{Field: "credit", Rule: "type", Message: "must be a whole number"}},
+		},
+	}
+
+	var csv strings.Builder
+	for _, record := range table {
+		csv.WriteString(strings.Join(record, ",") + "\n")
+	}
+
+	tests := []struct {
+		name   string
+		format Format
+		input  func(t *testing.T) *bytes.Buffer
+		want   []subjectmodel.SubjectImportRow
+	}{
+		{
+			name:   "csv",
+			format: FormatCSV,
+			input:  func(*testing.T) *bytes.Buffer { return bytes.NewBufferString(csv.String()) },
+			want:   tableRows,
+		},
+		{
+			name:   "xlsx",
+			format: FormatXLSX,
+			input:  func(t *testing.T) *bytes.Buffer { return xlsxFile(t, table) },
+			want:   tableRows,
+		},
+		{
+			name:   "json",
+			format: FormatJSON,
+			input: func(*testing.T) *bytes.Buffer {
+				return bytes.NewBufferString(`[{"subjectCode": "CSX3001", "name": "Programming", "credit": 3},
+					{"SUBJECTCODE": "CSX3002", "majorId": "65a000000000000000000001"}]`)
+			},
+			want: []subjectmodel.SubjectImportRow{
+				{
+					SubjectCode: "CSX3001", Name: "Programming", Credit: intPtr(3), Line: 1,
+					Columns: map[string]bool{"subjectCode": true, "name": true, "credit": true},
+				},
+				{
+					SubjectCode: "CSX3002", MajorID: "65a000000000000000000001", Line: 2,
+					Columns: map[string]bool{"subjectCode": true, "majorId": true},
+				},
+			},
+		},
+		{
+			name:   "ndjson skips blank lines",
+			format: FormatNDJSON,
+			input: func(*testing.T) *bytes.Buffer {
+				return bytes.NewBufferString("{\"subjectCode\": \"CSX3001\"}\n\n{\"subjectCode\": \"CSX3002\", \"campus\": \"Hua Mak\"}\n")
+			},
+			want: []subjectmodel.SubjectImportRow{
+				{SubjectCode: "CSX3001", Line: 1, Columns: map[string]bool{"subjectCode": true}},
+				{SubjectCode: "CSX3002", Campus: "Hua Mak", Line: 3, Columns: map[string]bool{"subjectCode": true, "campus": true}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadSubjects(tt.input(t), tt.format)
+			if err != nil {
+				t.Fatalf("ReadSubjects() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadSubjects() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadSubjectsRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		input  string
+	}{
+		{name: "empty csv", format: FormatCSV, input: ""},
+		{name: "csv without subject code column", format: FormatCSV, input: "name,major\nA,B\n"},
+		{name: "json that is not an array", format: FormatJSON, input: `{"subjectCode": "CSX3001"}`},
+		{name: "json item of the wrong type", format: FormatJSON, input: `[{"credit": "three"}]`},
+		{name: "bad ndjson line", format: FormatNDJSON, input: "{\"subjectCode\": \"A\"}\nnot json\n"},
+		{name: "not an xlsx file", format: FormatXLSX, input: "subjectCode\nCSX3001\n"},
+		{name: "unknown format", format: Format("yaml"), input: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadSubjects(strings.NewReader(tt.input), tt.format)
+			if !apperror.Is(err, apperror.KindInvalidArgument) {
+				t.Errorf("ReadSubjects() error = %v, want an invalid argument error", err)
+			}
+		})
+	}
+}
+
+func TestHeaderMapping(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"Subject Code", "subjectCode"},
+		{"subject_code", "subjectCode"},
+		{"code", "subjectCode"},
+		{"\ufeffsubjectCode", "subjectCode"},
+		{"Major Name", "major"},
+		{"Major ID", "majorId"},
+		{"major-id", "majorId"},
+		{"Co.Requisites", "coRequisite"},
+		{"Available Duration", "availableDuration"},
+		{"Notes", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := columns[normaliseHeader(tt.header)]; got != tt.want {
+				t.Errorf("columns[normaliseHeader(%q)] = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasColumn(t *testing.T) {
+	tests := []struct {
+		name    string
+		columns map[string]bool
+		field   string
+		want    bool
+	}{
+		{name: "unknown source has every column", columns: nil, field: "campus", want: true},
+		{name: "column present", columns: map[string]bool{"campus": true}, field: "campus", want: true},
+		{name: "column absent", columns: map[string]bool{"campus": true}, field: "credit", want: false},
+		{name: "empty source", columns: map[string]bool{}, field: "campus", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := subjectmodel.SubjectImportRow{Columns: tt.columns}
+			if got := row.HasColumn(tt.field); got != tt.want {
+				t.Errorf("HasColumn(%q) = %v, want %v", tt.field, got, tt.want)
+			}
+		})
+	}
+}
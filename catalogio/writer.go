@@ -0,0 +1,162 @@
+package catalogio
+
+import (
+	"BackendCoursyclopedia/model/subjectmodel"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// SubjectWriter encodes export rows one at a time. Close must be called to
+// finish the document; nothing is guaranteed to be written before it.
+type SubjectWriter interface {
+	Write(row subjectmodel.SubjectExportRow) error
+	Close() error
+}
+
+// exportHeader is the column order of CSV and XLSX exports. Every column is
+// understood by ReadSubjects except faculty, facultyId and subjectId, which
+// the importer ignores.
+var exportHeader = []string{
+	"faculty", "facultyId", "major", "majorId", "subjectId", "subjectCode", "name",
+	"credit", "campus", "subjectStatus", "availableDuration",
+	"preRequisite", "coRequisite", "professors", "subjectDescription",
+}
+
+// NewSubjectWriter returns a writer encoding rows to w in format.
+func NewSubjectWriter(w io.Writer, format Format) (SubjectWriter, error) {
+	switch format {
+	case FormatJSON:
+		return &jsonWriter{w: w, enc: json.NewEncoder(w)}, nil
+	case FormatNDJSON:
+		return &ndjsonWriter{enc: json.NewEncoder(w)}, nil
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(exportHeader); err != nil {
+			return nil, err
+		}
+		return &csvWriter{w: cw}, nil
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	}
+	_, err := ParseFormat(string(format))
+	return nil, err
+}
+
+// jsonWriter streams a JSON array element by element.
+type jsonWriter struct {
+	w     io.Writer
+	enc   *json.Encoder
+	count int
+}
+
+func (j *jsonWriter) Write(row subjectmodel.SubjectExportRow) error {
+	sep := ",\n"
+	if j.count == 0 {
+		sep = "[\n"
+	}
+	j.count++
+	if _, err := io.WriteString(j.w, sep); err != nil {
+		return err
+	}
+	return j.enc.Encode(row)
+}
+
+func (j *jsonWriter) Close() error {
+	if j.count == 0 {
+		_, err := io.WriteString(j.w, "[]\n")
+		return err
+	}
+	_, err := io.WriteString(j.w, "]\n")
+	return err
+}
+
+type ndjsonWriter struct {
+	enc *json.Encoder
+}
+
+func (n *ndjsonWriter) Write(row subjectmodel.SubjectExportRow) error {
+	return n.enc.Encode(row)
+}
+
+func (n *ndjsonWriter) Close() error {
+	return nil
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) Write(row subjectmodel.SubjectExportRow) error {
+	return c.w.Write(exportRecord(row))
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// xlsxWriter uses excelize's stream writer, which spills rows to a temporary
+// file instead of keeping them in memory. The workbook is a zip archive, so
+// it is only written to w on Close.
+type xlsxWriter struct {
+	w    io.Writer
+	file *excelize.File
+	sw   *excelize.StreamWriter
+	next int
+}
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+	sw, err := file.NewStreamWriter(file.GetSheetName(0))
+	if err != nil {
+		return nil, err
+	}
+	x := &xlsxWriter{w: w, file: file, sw: sw, next: 1}
+	if err := x.setRow(exportHeader); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+func (x *xlsxWriter) Write(row subjectmodel.SubjectExportRow) error {
+	return x.setRow(exportRecord(row))
+}
+
+func (x *xlsxWriter) setRow(record []string) error {
+	cell, err := excelize.CoordinatesToCellName(1, x.next)
+	if err != nil {
+		return err
+	}
+	values := make([]interface{}, len(record))
+	for i, v := range record {
+		values[i] = v
+	}
+	x.next++
+	return x.sw.SetRow(cell, values)
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.file.Close()
+	if err := x.sw.Flush(); err != nil {
+		return err
+	}
+	return x.file.Write(x.w)
+}
+
+func exportRecord(row subjectmodel.SubjectExportRow) []string {
+	credit := ""
+	if row.Credit != nil {
+		credit = strconv.Itoa(*row.Credit)
+	}
+	return []string{
+		row.Faculty, row.FacultyID, row.Major, row.MajorID, row.SubjectID, row.SubjectCode, row.Name,
+		credit, row.Campus, row.SubjectStatus, strconv.Itoa(row.AvailableDuration),
+		strings.Join(row.PreRequisite, "; "), strings.Join(row.CoRequisite, "; "),
+		strings.Join(row.Professors, "; "), row.SubjectDescription,
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"BackendCoursyclopedia/db"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// exportCollection writes every document of a collection as a JSON array of
+// canonical extended JSON documents, which preserves ObjectIDs and dates.
+func (c *cli) exportCollection(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	collection := fs.String("collection", "", "collection name, e.g. subjects")
+	file := fs.String("file", "", "output file (default stdout)")
+	if err := parseFlags(fs, args, "collection"); err != nil {
+		return err
+	}
+
+	out := c.out
+	if *file != "" {
+		f, err := os.Create(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	cursor, err := db.GetCollection(*collection).Find(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	count := 0
+	if _, err := io.WriteString(out, "[\n"); err != nil {
+		return err
+	}
+	for cursor.Next(ctx) {
+		doc, err := bson.MarshalExtJSON(cursor.Current, true, false)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			if _, err := io.WriteString(out, ",\n"); err != nil {
+				return err
+			}
+		}
+		if _, err := out.Write(doc); err != nil {
+			return err
+		}
+		count++
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(out, "\n]\n"); err != nil {
+		return err
+	}
+
+	if *file != "" {
+		return c.print(map[string]interface{}{"collection": *collection, "exported": count, "file": *file}, func(w io.Writer) {
+			fmt.Fprintf(w, "exported %d documents from %s to %s\n", count, *collection, *file)
+		})
+	}
+	return nil
+}
+
+// importCollection upserts every document of a file written by export,
+// matching on _id so the same file can be imported repeatedly.
+func (c *cli) importCollection(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	collection := fs.String("collection", "", "collection name, e.g. subjects")
+	file := fs.String("file", "", "input file written by export")
+	drop := fs.Bool("drop", false, "drop the collection before importing")
+	if err := parseFlags(fs, args, "collection", "file"); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+	var raws []json.RawMessage
+	if err := json.Unmarshal(data, &raws); err != nil {
+		return fmt.Errorf("%s: expected a JSON array: %w", *file, err)
+	}
+
+	coll := db.GetCollection(*collection)
+	if *drop {
+		if err := coll.Drop(ctx); err != nil {
+			return err
+		}
+		// Dropping takes the indexes with it. Recreate them before
+		// importing, so duplicates in the file are refused.
+		if err := db.EnsureCollectionIndexes(ctx, *collection); err != nil {
+			return err
+		}
+	}
+
+	count := 0
+	for i, raw := range raws {
+		var doc bson.D
+		if err := bson.UnmarshalExtJSON(raw, true, &doc); err != nil {
+			return fmt.Errorf("document %d: %w", i, err)
+		}
+		var id interface{}
+		for _, e := range doc {
+			if e.Key == "_id" {
+				id = e.Value
+			}
+		}
+		if id == nil {
+			if _, err := coll.InsertOne(ctx, doc); err != nil {
+				return fmt.Errorf("document %d: %w", i, err)
+			}
+		} else if _, err := coll.ReplaceOne(ctx, bson.M{"_id": id}, doc, options.Replace().SetUpsert(true)); err != nil {
+			return fmt.Errorf("document %d: %w", i, err)
+		}
+		count++
+	}
+
+	return c.print(map[string]interface{}{"collection": *collection, "imported": count}, func(w io.Writer) {
+		fmt.Fprintf(w, "imported %d documents into %s\n", count, *collection)
+	})
+}
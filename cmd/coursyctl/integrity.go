@@ -0,0 +1,66 @@
+package main
+
+import (
+	"BackendCoursyclopedia/model/integritymodel"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+)
+
+func (c *cli) integrityCheck(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("integrity check", flag.ContinueOnError)
+	fix := fs.Bool("fix", false, "prune dangling references and keep multi-parent documents in their oldest parent")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	report, err := c.integrityService.Check(ctx, *fix)
+	if err != nil {
+		return err
+	}
+
+	return c.print(report, func(w io.Writer) {
+		printIntegrityReport(w, report)
+	})
+}
+
+func (c *cli) integrityReport(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("integrity report", flag.ContinueOnError)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	report, err := c.integrityService.GetLatestReport(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.print(report, func(w io.Writer) {
+		printIntegrityReport(w, report)
+	})
+}
+
+func printIntegrityReport(w io.Writer, report *integritymodel.Report) {
+	fmt.Fprintf(w, "checked at %s (fix=%t)\n\n", report.FinishedAt.Format("2006-01-02 15:04:05Z07:00"), report.FixMode)
+	if len(report.Anomalies) == 0 {
+		fmt.Fprintln(w, "no anomalies found")
+		return
+	}
+
+	fmt.Fprintln(w, "KIND\tCOLLECTION\tID\tFIXED\tDETAIL")
+	for _, a := range report.Anomalies {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n", a.Kind, a.Collection, a.DocumentID.Hex(), a.Fixed, a.Detail)
+	}
+
+	kinds := make([]string, 0, len(report.Counts))
+	for kind := range report.Counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	fmt.Fprintln(w)
+	for _, kind := range kinds {
+		fmt.Fprintf(w, "%s\t%d\n", kind, report.Counts[kind])
+	}
+}
@@ -0,0 +1,206 @@
+// Command coursyctl runs operational tasks against the Coursyclopedia
+// database using the same services as the API.
+package main
+
+import (
+	"BackendCoursyclopedia/db"
+	"BackendCoursyclopedia/logger"
+	"BackendCoursyclopedia/mail"
+	"BackendCoursyclopedia/repository/authtokenrepository"
+	"BackendCoursyclopedia/repository/curriculumrepository"
+	"BackendCoursyclopedia/repository/facultyrepository"
+	"BackendCoursyclopedia/repository/integrityrepository"
+	"BackendCoursyclopedia/repository/majorrepository"
+	"BackendCoursyclopedia/repository/notificationrepository"
+	"BackendCoursyclopedia/repository/offeringrepository"
+	"BackendCoursyclopedia/repository/studyplanrepository"
+	"BackendCoursyclopedia/repository/subjectrepository"
+	userrepo "BackendCoursyclopedia/repository/userrepository"
+	"BackendCoursyclopedia/service/facultyservice"
+	"BackendCoursyclopedia/service/integrityservice"
+	"BackendCoursyclopedia/service/majorservice"
+	"BackendCoursyclopedia/service/notificationservice"
+	"BackendCoursyclopedia/service/subjectservice"
+	usersvc "BackendCoursyclopedia/service/userservice"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/joho/godotenv"
+)
+
+const usage = `usage: coursyctl [-o text|json] <command> [flags]
+
+commands:
+  user create          create a user with a role
+  user reset-password  set a new password for a user
+  user suspend         stop a user signing in and revoke their tokens
+  user reactivate      let a suspended user sign in again
+  subject link         move a subject to another major
+  subject import       create or update subjects from a CSV, JSON, NDJSON or XLSX file
+  subject expire       archive subjects whose availability duration has elapsed
+  orphans list         list orphaned and dangling majors and subjects
+  orphans repair       prune dangling references and re-attach orphans
+  integrity check      report dangling references, orphans and multi-parent documents
+  integrity report     show the latest stored integrity report
+  seed                 insert demo faculties, majors and subjects
+  export               export a collection as extended JSON
+  import               import a collection exported with export
+`
+
+// errUsage is returned for malformed command lines.
+var errUsage = errors.New(usage)
+
+type cli struct {
+	out  io.Writer
+	json bool
+
+	userRepository    userrepo.IUserRepository
+	facultyRepository facultyrepository.IFacultyRepository
+	majorRepository   majorrepository.IMajorRepository
+	subjectRepository subjectrepository.ISubjectRepository
+
+	userService      usersvc.IUserService
+	facultyService   facultyservice.IFacultyService
+	majorService     majorservice.IMajorService
+	subjectService   subjectservice.ISubjectService
+	integrityService integrityservice.IIntegrityService
+}
+
+func main() {
+	_ = godotenv.Load()
+	logger.InitWithWriter(os.Stderr)
+
+	global := flag.NewFlagSet("coursyctl", flag.ExitOnError)
+	global.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	output := global.String("o", "text", "output format: text or json")
+	_ = global.Parse(os.Args[1:])
+
+	if *output != "text" && *output != "json" {
+		fmt.Fprintf(os.Stderr, "unknown output format %q\n", *output)
+		os.Exit(2)
+	}
+	if global.NArg() == 0 {
+		global.Usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db.ConnectDB()
+	defer db.DisconnectDB()
+
+	c := newCLI(os.Stdout, *output == "json")
+	if err := c.run(ctx, global.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		if errors.Is(err, errUsage) {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+func newCLI(out io.Writer, jsonOutput bool) *cli {
+	userRepository := userrepo.NewUserRepository(db.DB)
+	facultyRepository := facultyrepository.NewFacultyRepository(db.DB)
+	majorRepository := majorrepository.NewMajorRepository(db.DB)
+	subjectRepository := subjectrepository.NewSubjectRepository(db.DB)
+	// The CLI only queues emails in the outbox; the API's
+	// notifications.deliver job sends them, so no transport is needed here.
+	notificationService := notificationservice.NewNotificationService(
+		notificationrepository.NewNotificationRepository(db.DB), userRepository, &mail.LogTransport{})
+
+	return &cli{
+		out:  out,
+		json: jsonOutput,
+
+		userRepository:    userRepository,
+		facultyRepository: facultyRepository,
+		majorRepository:   majorRepository,
+		subjectRepository: subjectRepository,
+
+		userService:    usersvc.NewUserService(userRepository, authtokenrepository.NewAuthTokenRepository(db.DB), notificationService),
+		facultyService: facultyservice.NewFacultyService(facultyRepository, majorRepository),
+		majorService:   majorservice.NewMajorService(majorRepository, facultyRepository, subjectRepository),
+		subjectService: subjectservice.NewSubjectService(subjectRepository, majorRepository,
+			offeringrepository.NewOfferingRepository(db.DB), curriculumrepository.NewCurriculumRepository(db.DB),
+			studyplanrepository.NewStudyPlanRepository(db.DB), notificationService),
+		integrityService: integrityservice.NewIntegrityService(integrityrepository.NewIntegrityRepository(db.DB),
+			facultyRepository, majorRepository, subjectRepository, userRepository),
+	}
+}
+
+func (c *cli) run(ctx context.Context, args []string) error {
+	cmd, rest := args[0], args[1:]
+	sub := ""
+	if len(rest) > 0 {
+		sub = rest[0]
+	}
+
+	switch {
+	case cmd == "user" && sub == "create":
+		return c.userCreate(ctx, rest[1:])
+	case cmd == "user" && sub == "reset-password":
+		return c.userResetPassword(ctx, rest[1:])
+	case cmd == "user" && sub == "suspend":
+		return c.userSetStatus(ctx, rest[1:], false)
+	case cmd == "user" && sub == "reactivate":
+		return c.userSetStatus(ctx, rest[1:], true)
+	case cmd == "subject" && sub == "link":
+		return c.subjectLink(ctx, rest[1:])
+	case cmd == "subject" && sub == "import":
+		return c.subjectImport(ctx, rest[1:])
+	case cmd == "subject" && sub == "expire":
+		return c.subjectExpire(ctx, rest[1:])
+	case cmd == "orphans" && sub == "list":
+		return c.orphansList(ctx, rest[1:])
+	case cmd == "orphans" && sub == "repair":
+		return c.orphansRepair(ctx, rest[1:])
+	case cmd == "integrity" && sub == "check":
+		return c.integrityCheck(ctx, rest[1:])
+	case cmd == "integrity" && sub == "report":
+		return c.integrityReport(ctx, rest[1:])
+	case cmd == "seed":
+		return c.seed(ctx, rest)
+	case cmd == "export":
+		return c.exportCollection(ctx, rest)
+	case cmd == "import":
+		return c.importCollection(ctx, rest)
+	}
+	return errUsage
+}
+
+// print writes v as indented JSON in JSON mode, otherwise calls text with a
+// tab-aligned writer.
+func (c *cli) print(v interface{}, text func(w io.Writer)) error {
+	if c.json {
+		enc := json.NewEncoder(c.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	w := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', 0)
+	text(w)
+	return w.Flush()
+}
+
+// parseFlags parses args into fs and rejects missing required flags.
+func parseFlags(fs *flag.FlagSet, args []string, required ...string) error {
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%s: %w\n%w", fs.Name(), err, errUsage)
+	}
+	for _, name := range required {
+		if f := fs.Lookup(name); f == nil || f.Value.String() == "" {
+			return fmt.Errorf("%s: --%s is required\n%w", fs.Name(), name, errUsage)
+		}
+	}
+	return nil
+}
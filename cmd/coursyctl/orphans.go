@@ -0,0 +1,189 @@
+package main
+
+import (
+	"BackendCoursyclopedia/model/integritymodel"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type orphanReport struct {
+	OrphanMajors     []string          `json:"orphanMajors"`
+	OrphanSubjects   []string          `json:"orphanSubjects"`
+	DanglingMajors   map[string]string `json:"danglingMajors"`
+	DanglingSubjects map[string]string `json:"danglingSubjects"`
+}
+
+// findOrphans lists majors that no faculty references, subjects that no
+// major references, and references to majors or subjects that no longer
+// exist (keyed by the missing ID, valued by the referencing parent ID).
+func (c *cli) findOrphans(ctx context.Context) (orphanReport, error) {
+	report := orphanReport{
+		OrphanMajors:     []string{},
+		OrphanSubjects:   []string{},
+		DanglingMajors:   map[string]string{},
+		DanglingSubjects: map[string]string{},
+	}
+
+	scan, err := c.integrityService.Scan(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	for _, a := range scan.Anomalies {
+		switch {
+		case a.Kind == integritymodel.KindOrphanMajor:
+			report.OrphanMajors = append(report.OrphanMajors, a.DocumentID.Hex())
+		case a.Kind == integritymodel.KindOrphanSubject:
+			report.OrphanSubjects = append(report.OrphanSubjects, a.DocumentID.Hex())
+		case a.Kind == integritymodel.KindDanglingMajor:
+			report.DanglingMajors[a.RefID.Hex()] = a.DocumentID.Hex()
+		case a.Kind == integritymodel.KindDanglingSubject && a.Collection == "majors":
+			report.DanglingSubjects[a.RefID.Hex()] = a.DocumentID.Hex()
+		}
+	}
+
+	return report, nil
+}
+
+func (c *cli) orphansList(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("orphans list", flag.ContinueOnError)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	report, err := c.findOrphans(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.print(report, func(w io.Writer) {
+		printOrphanReport(w, report)
+	})
+}
+
+func printOrphanReport(w io.Writer, report orphanReport) {
+	fmt.Fprintln(w, "KIND\tID\tREFERENCED BY")
+	for _, id := range report.OrphanMajors {
+		fmt.Fprintf(w, "orphan major\t%s\t-\n", id)
+	}
+	for _, id := range report.OrphanSubjects {
+		fmt.Fprintf(w, "orphan subject\t%s\t-\n", id)
+	}
+	for id, parent := range report.DanglingMajors {
+		fmt.Fprintf(w, "dangling major\t%s\tfaculty %s\n", id, parent)
+	}
+	for id, parent := range report.DanglingSubjects {
+		fmt.Fprintf(w, "dangling subject\t%s\tmajor %s\n", id, parent)
+	}
+}
+
+func (c *cli) orphansRepair(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("orphans repair", flag.ContinueOnError)
+	prune := fs.Bool("prune", true, "remove references to majors and subjects that no longer exist")
+	assignFaculty := fs.String("assign-faculty", "", "attach orphan majors to this faculty ID")
+	assignMajor := fs.String("assign-major", "", "attach orphan subjects to this major ID")
+	dryRun := fs.Bool("dry-run", false, "report what would change without writing")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	report, err := c.findOrphans(ctx)
+	if err != nil {
+		return err
+	}
+
+	var actions []string
+	if *prune {
+		for id := range report.DanglingMajors {
+			actions = append(actions, "remove dangling major "+id)
+			if !*dryRun {
+				objID, err := primitive.ObjectIDFromHex(id)
+				if err != nil {
+					return fmt.Errorf("dangling major %q: %w", id, err)
+				}
+				if err := c.facultyRepository.RemoveMajorFromFaculty(ctx, objID); err != nil {
+					return err
+				}
+			}
+		}
+		for id := range report.DanglingSubjects {
+			actions = append(actions, "remove dangling subject "+id)
+			if !*dryRun {
+				objID, err := primitive.ObjectIDFromHex(id)
+				if err != nil {
+					return fmt.Errorf("dangling subject %q: %w", id, err)
+				}
+				if err := c.majorRepository.RemoveSubjectFromMajors(ctx, objID); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if *assignFaculty != "" {
+		for _, id := range report.OrphanMajors {
+			actions = append(actions, "attach major "+id+" to faculty "+*assignFaculty)
+			if !*dryRun {
+				if err := c.facultyRepository.AddMajorToFaculty(ctx, *assignFaculty, id); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if *assignMajor != "" {
+		for _, id := range report.OrphanSubjects {
+			actions = append(actions, "attach subject "+id+" to major "+*assignMajor)
+			if !*dryRun {
+				if err := c.majorRepository.AddSubjectToMajor(ctx, *assignMajor, id); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	result := map[string]interface{}{"dryRun": *dryRun, "actions": actions}
+	return c.print(result, func(w io.Writer) {
+		if len(actions) == 0 {
+			fmt.Fprintln(w, "nothing to repair")
+		}
+		for _, a := range actions {
+			if *dryRun {
+				fmt.Fprint(w, "would ")
+			}
+			fmt.Fprintln(w, a)
+		}
+	})
+}
+
+func (c *cli) subjectLink(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("subject link", flag.ContinueOnError)
+	subjectID := fs.String("subject", "", "subject ID")
+	majorID := fs.String("major", "", "major ID to link the subject to")
+	if err := parseFlags(fs, args, "subject", "major"); err != nil {
+		return err
+	}
+
+	subject, err := c.subjectRepository.FindSubjectbyID(ctx, *subjectID)
+	if err != nil {
+		return err
+	}
+	if _, err := c.majorRepository.FindmajorbyID(ctx, *majorID); err != nil {
+		return err
+	}
+
+	// Detach from every major first so a subject that was linked to several
+	// majors by a partial failure ends up with exactly one.
+	if err := c.majorRepository.RemoveSubjectFromMajors(ctx, subject.ID); err != nil {
+		return err
+	}
+	if err := c.majorRepository.AddSubjectToMajor(ctx, *majorID, *subjectID); err != nil {
+		return err
+	}
+
+	return c.print(map[string]string{"subject": *subjectID, "major": *majorID}, func(w io.Writer) {
+		fmt.Fprintf(w, "linked subject %s (%s) to major %s\n", subject.SubjectCode, *subjectID, *majorID)
+	})
+}
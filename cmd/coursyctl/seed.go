@@ -0,0 +1,162 @@
+package main
+
+import (
+	"BackendCoursyclopedia/model/subjectmodel"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type seedMajor struct {
+	name     string
+	subjects []subjectmodel.Subject
+}
+
+type seedFaculty struct {
+	name   string
+	majors []seedMajor
+}
+
+var demoData = []seedFaculty{
+	{
+		name: "Vincent Mary School of Science and Technology",
+		majors: []seedMajor{
+			{
+				name: "Computer Science",
+				subjects: []subjectmodel.Subject{
+					{SubjectCode: "CSX3001", Name: "Fundamentals of Computer Programming", Credit: 3, Campus: "Suvarnabhumi"},
+					{SubjectCode: "CSX3002", Name: "Object-Oriented Concepts and Programming", Credit: 3, Campus: "Suvarnabhumi", PreRequisite: []string{"CSX3001"}},
+					{SubjectCode: "CSX3003", Name: "Data Structures and Algorithms", Credit: 3, Campus: "Suvarnabhumi", PreRequisite: []string{"CSX3002"}},
+				},
+			},
+			{
+				name: "Information Technology",
+				subjects: []subjectmodel.Subject{
+					{SubjectCode: "ITX2005", Name: "Database Systems", Credit: 3, Campus: "Suvarnabhumi"},
+					{SubjectCode: "ITX2007", Name: "Web Application Development", Credit: 3, Campus: "Suvarnabhumi", PreRequisite: []string{"ITX2005"}},
+				},
+			},
+		},
+	},
+	{
+		name: "Martin de Tours School of Management and Economics",
+		majors: []seedMajor{
+			{
+				name: "Business Economics",
+				subjects: []subjectmodel.Subject{
+					{SubjectCode: "BG1301", Name: "Principles of Microeconomics", Credit: 3, Campus: "Hua Mak"},
+					{SubjectCode: "BG1302", Name: "Principles of Macroeconomics", Credit: 3, Campus: "Hua Mak", PreRequisite: []string{"BG1301"}},
+				},
+			},
+		},
+	},
+}
+
+type seedCount struct {
+	Created  int `json:"created"`
+	Existing int `json:"existing"`
+}
+
+func (n *seedCount) add(created bool) {
+	if created {
+		n.Created++
+	} else {
+		n.Existing++
+	}
+}
+
+// seed creates the demo catalog. It can be run again: faculties are matched
+// by name, majors by name within their faculty and subjects by code, and
+// only the missing ones are created and linked.
+func (c *cli) seed(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	type seeded struct {
+		Faculties seedCount `json:"faculties"`
+		Majors    seedCount `json:"majors"`
+		Subjects  seedCount `json:"subjects"`
+	}
+	var result seeded
+
+	faculties, err := c.facultyRepository.FindAllFaculties(ctx)
+	if err != nil {
+		return err
+	}
+	facultyIDs := make(map[string]string, len(faculties))
+	facultyMajors := make(map[string][]primitive.ObjectID, len(faculties))
+	for _, f := range faculties {
+		facultyIDs[f.FacultyName] = f.ID.Hex()
+		facultyMajors[f.FacultyName] = f.MajorIDs
+	}
+
+	majors, err := c.majorRepository.FindAllMajors(ctx)
+	if err != nil {
+		return err
+	}
+	majorNames := make(map[primitive.ObjectID]string, len(majors))
+	for _, m := range majors {
+		majorNames[m.ID] = m.MajorName
+	}
+
+	subjects, err := c.subjectRepository.FindAllSubjects(ctx)
+	if err != nil {
+		return err
+	}
+	subjectCodes := make(map[string]bool, len(subjects))
+	for _, s := range subjects {
+		subjectCodes[s.SubjectCode] = true
+	}
+
+	for _, f := range demoData {
+		facultyID, ok := facultyIDs[f.name]
+		if !ok {
+			faculty, err := c.facultyRepository.CreateFaculty(ctx, f.name, nil)
+			if err != nil {
+				return fmt.Errorf("faculty %q: %w", f.name, err)
+			}
+			facultyID = faculty.ID.Hex()
+		}
+		result.Faculties.add(!ok)
+
+		for _, m := range f.majors {
+			majorID := ""
+			for _, id := range facultyMajors[f.name] {
+				if majorNames[id] == m.name {
+					majorID = id.Hex()
+					break
+				}
+			}
+			created := majorID == ""
+			if created {
+				if majorID, err = c.majorRepository.CreateMajor(ctx, m.name); err != nil {
+					return fmt.Errorf("major %q: %w", m.name, err)
+				}
+				if err := c.facultyRepository.AddMajorToFaculty(ctx, facultyID, majorID); err != nil {
+					return err
+				}
+			}
+			result.Majors.add(created)
+
+			for _, s := range m.subjects {
+				exists := subjectCodes[s.SubjectCode]
+				if !exists {
+					if _, err := c.subjectService.CreateSubject(ctx, s, majorID); err != nil {
+						return fmt.Errorf("subject %s: %w", s.SubjectCode, err)
+					}
+				}
+				result.Subjects.add(!exists)
+			}
+		}
+	}
+
+	return c.print(result, func(w io.Writer) {
+		fmt.Fprintf(w, "seeded %d faculties, %d majors, %d subjects\n", result.Faculties.Created, result.Majors.Created, result.Subjects.Created)
+		fmt.Fprintf(w, "already present: %d faculties, %d majors, %d subjects\n", result.Faculties.Existing, result.Majors.Existing, result.Subjects.Existing)
+	})
+}
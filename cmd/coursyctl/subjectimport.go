@@ -0,0 +1,94 @@
+package main
+
+import (
+	"BackendCoursyclopedia/catalogio"
+	"BackendCoursyclopedia/model/subjectmodel"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func (c *cli) subjectImport(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("subject import", flag.ContinueOnError)
+	file := fs.String("file", "", "CSV, JSON, NDJSON or XLSX file to import")
+	formatName := fs.String("format", "", "file format; defaults to the file extension")
+	dryRun := fs.Bool("dry-run", false, "validate and report without writing")
+	allOrNothing := fs.Bool("all-or-nothing", false, "write nothing if any row is invalid")
+	if err := parseFlags(fs, args, "file"); err != nil {
+		return err
+	}
+
+	format, err := catalogio.FormatFromFilename(*file)
+	if *formatName != "" {
+		format, err = catalogio.ParseFormat(*formatName)
+	}
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	rows, err := catalogio.ReadSubjects(f, format)
+	if err != nil {
+		return err
+	}
+
+	report, err := c.subjectService.ImportSubjects(ctx, rows, subjectmodel.SubjectImportOptions{
+		DryRun:       *dryRun,
+		AllOrNothing: *allOrNothing,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = c.print(report, func(w io.Writer) {
+		fmt.Fprintln(w, "LINE\tCODE\tACTION\tERRORS")
+		for _, row := range report.Rows {
+			fmt.Fprintf(w, "%d\t%s\t%s\t", row.Line, row.SubjectCode, row.Action)
+			for i, fe := range row.Errors {
+				if i > 0 {
+					fmt.Fprint(w, "; ")
+				}
+				fmt.Fprintf(w, "%s %s", fe.Field, fe.Message)
+			}
+			if row.Error != "" {
+				fmt.Fprintf(w, "not written: %s", row.Error)
+			}
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "\n%d to create, %d to update, %d invalid, %d failed, applied=%t\n",
+			report.Created, report.Updated, report.Invalid, report.Failed, report.Applied)
+	})
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case !report.DryRun && !report.Applied:
+		return fmt.Errorf("import rejected: %d invalid rows", report.Invalid)
+	case report.Failed > 0:
+		return fmt.Errorf("import incomplete: %d rows could not be written", report.Failed)
+	}
+	return nil
+}
+
+func (c *cli) subjectExpire(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("subject expire", flag.ContinueOnError)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	archived, err := c.subjectService.ArchiveExpiredSubjects(ctx)
+	if err != nil {
+		return err
+	}
+	return c.print(map[string]int{"archived": archived}, func(w io.Writer) {
+		fmt.Fprintf(w, "archived %d expired subject(s)\n", archived)
+	})
+}
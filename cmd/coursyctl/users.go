@@ -0,0 +1,109 @@
+package main
+
+import (
+	"BackendCoursyclopedia/model/usermodel"
+	"BackendCoursyclopedia/validation"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// knownRoles are the role slugs the API checks for.
+var knownRoles = []string{"user", "admin"}
+
+func (c *cli) userCreate(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("user create", flag.ContinueOnError)
+	email := fs.String("email", "", "email address")
+	password := fs.String("password", "", "initial password")
+	role := fs.String("role", "user", "role slug: "+strings.Join(knownRoles, " or "))
+	firstName := fs.String("first-name", "", "first name")
+	lastName := fs.String("last-name", "", "last name")
+	if err := parseFlags(fs, args, "email", "password"); err != nil {
+		return err
+	}
+	if !slices.Contains(knownRoles, *role) {
+		return fmt.Errorf("unknown role %q, want one of %s", *role, strings.Join(knownRoles, ", "))
+	}
+
+	request := usermodel.UserCreateRequest{Email: *email, Password: *password}
+	request.Profile.FirstName = *firstName
+	request.Profile.LastName = *lastName
+	if err := validation.Struct(request); err != nil {
+		return err
+	}
+
+	user := request.ToUser()
+	user.Status = usermodel.StatusActive
+	user.Role = usermodel.Role{
+		Name:        *role,
+		Slug:        *role,
+		Description: "Created with coursyctl",
+		Permissions: []string{},
+	}
+
+	created, err := c.userService.CreateNewUser(ctx, user)
+	if err != nil {
+		return err
+	}
+	created.Password = ""
+
+	return c.print(created, func(w io.Writer) {
+		fmt.Fprintf(w, "created user %s\t%s\trole=%s\n", created.ID.Hex(), created.Email, created.Role.Slug)
+	})
+}
+
+func (c *cli) userResetPassword(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("user reset-password", flag.ContinueOnError)
+	email := fs.String("email", "", "email address")
+	password := fs.String("password", "", "new password")
+	if err := parseFlags(fs, args, "email", "password"); err != nil {
+		return err
+	}
+	if len(*password) < 8 {
+		return fmt.Errorf("password must be at least 8 characters")
+	}
+
+	if err := c.userService.ResetPassword(ctx, *email, *password); err != nil {
+		return err
+	}
+
+	return c.print(map[string]string{"email": *email, "status": "password reset"}, func(w io.Writer) {
+		fmt.Fprintf(w, "password reset for %s\n", *email)
+	})
+}
+
+// userSetStatus suspends a user, or reactivates one. The change is recorded
+// as made by coursyctl.
+func (c *cli) userSetStatus(ctx context.Context, args []string, reactivate bool) error {
+	name := "user suspend"
+	if reactivate {
+		name = "user reactivate"
+	}
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	email := fs.String("email", "", "email address")
+	reason := fs.String("reason", "", "why the status changes")
+	if err := parseFlags(fs, args, "email", "reason"); err != nil {
+		return err
+	}
+
+	user, err := c.userService.GetUserByEmail(ctx, *email)
+	if err != nil {
+		return err
+	}
+	if reactivate {
+		user, err = c.userService.ReactivateUser(ctx, user.ID.Hex(), "coursyctl", *reason)
+	} else {
+		user, err = c.userService.SuspendUser(ctx, user.ID.Hex(), "coursyctl", *reason)
+	}
+	if err != nil {
+		return err
+	}
+	user.Password = ""
+
+	return c.print(user, func(w io.Writer) {
+		fmt.Fprintf(w, "%s\t%s\tstatus=%s\n", user.ID.Hex(), user.Email, user.Status)
+	})
+}
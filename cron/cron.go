@@ -0,0 +1,189 @@
+// Package cron parses cron expressions and computes when they next fire.
+//
+// An expression has five fields: minute, hour, day of month, month and day
+// of week (0 or 7 is Sunday). Each field is `*`, a number, a range `a-b`, a
+// step `*/n` or `a-b/n`, or a comma-separated list of those; months and days
+// also accept their three-letter English names. As in classic cron, when
+// both day fields are restricted a time matches if either one does. The
+// descriptors @yearly, @monthly, @weekly, @daily, @hourly and `@every
+// <duration>` are accepted too.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule reports the first time after t at which it fires.
+type Schedule interface {
+	Next(t time.Time) time.Time
+}
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type field struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var fields = [5]field{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"", "jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+// Parse parses a cron expression or descriptor. Times are evaluated in the
+// location of the time passed to Next.
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if rest, ok := strings.CutPrefix(spec, "@every "); ok {
+		interval, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, fmt.Errorf("cron: %w", err)
+		}
+		if interval < time.Second {
+			return nil, fmt.Errorf("cron: @every needs at least 1s, got %s", interval)
+		}
+		return every(interval), nil
+	}
+	if expr, ok := descriptors[strings.ToLower(spec)]; ok {
+		spec = expr
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("cron: expected %d fields in %q, got %d", len(fields), spec, len(parts))
+	}
+
+	var s expression
+	sets := [5]*uint64{&s.minutes, &s.hours, &s.days, &s.months, &s.weekdays}
+	for i, part := range parts {
+		bits, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, err
+		}
+		*sets[i] = bits
+	}
+	// Sunday may be written as 7.
+	if s.weekdays&(1<<7) != 0 {
+		s.weekdays |= 1
+	}
+	s.anyDay = parts[2] == "*"
+	s.anyWeekday = parts[4] == "*"
+	return s, nil
+}
+
+func parseField(part string, f field) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(part, ",") {
+		lo, hi, step := f.min, f.max, 1
+
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("cron: invalid step %q in %s", stepPart, f.name)
+			}
+			step = n
+		}
+		if rangePart != "*" {
+			from, to, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = f.value(from); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = f.value(to); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				hi = f.max
+			}
+			if hi < lo {
+				return 0, fmt.Errorf("cron: range %q runs backwards in %s", rangePart, f.name)
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func (f field) value(s string) (int, error) {
+	for i, name := range f.names {
+		if name != "" && strings.EqualFold(s, name) {
+			return i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("cron: %q is not a valid %s (%d-%d)", s, f.name, f.min, f.max)
+	}
+	return v, nil
+}
+
+type expression struct {
+	minutes, hours, days, months, weekdays uint64
+	anyDay, anyWeekday                     bool
+}
+
+// maxYears bounds the search so an expression that never fires, such as
+// 30 February, returns the zero time instead of looping forever.
+const maxYears = 5
+
+func (s expression) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxYears, 0, 0)
+
+	for t.Before(limit) {
+		if s.months&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hours&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s expression) dayMatches(t time.Time) bool {
+	day := s.days&(1<<uint(t.Day())) != 0
+	weekday := s.weekdays&(1<<uint(t.Weekday())) != 0
+	if s.anyDay || s.anyWeekday {
+		return day && weekday
+	}
+	return day || weekday
+}
+
+type every time.Duration
+
+func (e every) Next(t time.Time) time.Time {
+	return t.Truncate(time.Second).Add(time.Duration(e))
+}
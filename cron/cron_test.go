@@ -0,0 +1,128 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	// 2026-10-19 is a Monday.
+	from := time.Date(2026, 10, 19, 10, 17, 42, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{spec: "* * * * *", want: time.Date(2026, 10, 19, 10, 18, 0, 0, time.UTC)},
+		{spec: "30 * * * *", want: time.Date(2026, 10, 19, 10, 30, 0, 0, time.UTC)},
+		{spec: "15 * * * *", want: time.Date(2026, 10, 19, 11, 15, 0, 0, time.UTC)},
+		{spec: "0 0 * * *", want: time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)},
+		{spec: "@daily", want: time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)},
+		{spec: "@hourly", want: time.Date(2026, 10, 19, 11, 0, 0, 0, time.UTC)},
+		{spec: "@yearly", want: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "@MONTHLY", want: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+
+		// Steps, ranges and lists.
+		{spec: "*/20 * * * *", want: time.Date(2026, 10, 19, 10, 20, 0, 0, time.UTC)},
+		{spec: "5/20 * * * *", want: time.Date(2026, 10, 19, 10, 25, 0, 0, time.UTC)},
+		{spec: "0 9-17/4 * * *", want: time.Date(2026, 10, 19, 13, 0, 0, 0, time.UTC)},
+		{spec: "0 8,12,18 * * *", want: time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)},
+		{spec: "0-10 11 * * *", want: time.Date(2026, 10, 19, 11, 0, 0, 0, time.UTC)},
+
+		// Names.
+		{spec: "0 0 1 jan *", want: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 1 Nov-Dec *", want: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 * * fri", want: time.Date(2026, 10, 23, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 * * SAT,sun", want: time.Date(2026, 10, 24, 0, 0, 0, 0, time.UTC)},
+
+		// Sunday is 0 or 7.
+		{spec: "0 0 * * 0", want: time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 * * 7", want: time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC)},
+		{spec: "@weekly", want: time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC)},
+
+		// With both day fields restricted either may match; with one of
+		// them `*` only the other counts.
+		{spec: "0 0 1 * fri", want: time.Date(2026, 10, 23, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 21 * sun", want: time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 1 * *", want: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 * 11 *", want: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+
+		// Month lengths and leap years.
+		{spec: "0 0 31 * *", want: time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 31 * *", from: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), want: time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 29 2 *", want: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 30 2 *", want: time.Time{}},
+
+		// A time that already matches fires at the next occurrence.
+		{spec: "0 0 * * *", from: time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC), want: time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC)},
+
+		{spec: "@every 90s", want: time.Date(2026, 10, 19, 10, 19, 12, 0, time.UTC)},
+		{spec: "@every 1h", want: time.Date(2026, 10, 19, 11, 17, 42, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.spec, err)
+			}
+			start := tt.from
+			if start.IsZero() {
+				start = from
+			}
+			if got := schedule.Next(start); !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", start, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextInLocation(t *testing.T) {
+	bangkok, err := time.LoadLocation("Asia/Bangkok")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedule, err := Parse("0 2 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 02:00 in Bangkok is 19:00 UTC the day before.
+	got := schedule.Next(time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC).In(bangkok))
+	want := time.Date(2026, 10, 20, 2, 0, 0, 0, bangkok)
+	if !got.Equal(want) {
+		t.Errorf("Next = %s, want %s", got, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 13 * ",
+		"* * * * 8",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"10-5 * * * *",
+		"* * * foo *",
+		"* * * * monday",
+		"@every",
+		"@every 10ms",
+		"@every soon",
+		"@fortnightly",
+	}
+
+	for _, spec := range tests {
+		t.Run(spec, func(t *testing.T) {
+			if _, err := Parse(spec); err == nil {
+				t.Errorf("Parse(%q) succeeded, want an error", spec)
+			}
+		})
+	}
+}
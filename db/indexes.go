@@ -0,0 +1,208 @@
+package db
+
+import (
+	"BackendCoursyclopedia/logger"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// nonEmptyString limits a unique index to documents where the field is set,
+// so legacy documents with an empty value do not collide with each other.
+func nonEmptyString(field string) bson.M {
+	return bson.M{field: bson.M{"$type": "string", "$gt": ""}}
+}
+
+// CaseInsensitive compares strings ignoring case. Queries must pass it to use
+// an index built with it.
+var CaseInsensitive = &options.Collation{Locale: "en", Strength: 2}
+
+// Indexes declares the indexes every collection must have. Unique indexes back
+// the duplicate checks; the others serve the reverse lookups from a child ID
+// to its parent.
+var Indexes = map[string][]mongo.IndexModel{
+	"users": {
+		{
+			Keys: bson.D{{Key: "email", Value: 1}},
+			Options: options.Index().
+				SetName("users_email_unique").
+				SetUnique(true).
+				SetCollation(CaseInsensitive).
+				SetPartialFilterExpression(nonEmptyString("email")),
+		},
+		{
+			Keys: bson.D{{Key: "calendarTokenHash", Value: 1}},
+			Options: options.Index().
+				SetName("users_calendarTokenHash_unique").
+				SetUnique(true).
+				SetPartialFilterExpression(nonEmptyString("calendarTokenHash")),
+		},
+		{
+			Keys:    bson.D{{Key: "wishlists", Value: 1}},
+			Options: options.Index().SetName("users_wishlists"),
+		},
+	},
+	"authtokens": {
+		{
+			Keys: bson.D{{Key: "hash", Value: 1}},
+			Options: options.Index().
+				SetName("authtokens_hash_unique").
+				SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "purpose", Value: 1}, {Key: "createdAt", Value: 1}},
+			Options: options.Index().SetName("authtokens_userId_purpose_createdAt"),
+		},
+		{
+			// Tokens are deleted as soon as they expire.
+			Keys: bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().
+				SetName("authtokens_expiresAt_ttl").
+				SetExpireAfterSeconds(0),
+		},
+	},
+	"notifications": {
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "nextAttemptAt", Value: 1}},
+			Options: options.Index().SetName("notifications_status_nextAttemptAt"),
+		},
+		{
+			// Sent emails are kept for 30 days; failed ones stay for
+			// inspection.
+			Keys: bson.D{{Key: "sentAt", Value: 1}},
+			Options: options.Index().
+				SetName("notifications_sentAt_ttl").
+				SetExpireAfterSeconds(30 * 24 * 60 * 60).
+				SetPartialFilterExpression(bson.M{"status": "sent"}),
+		},
+	},
+	"subjects": {
+		{
+			Keys: bson.D{{Key: "subjectCode", Value: 1}},
+			Options: options.Index().
+				SetName("subjects_subjectCode_unique").
+				SetUnique(true).
+				SetPartialFilterExpression(nonEmptyString("subjectCode")),
+		},
+	},
+	"faculties": {
+		{
+			Keys: bson.D{{Key: "facultyName", Value: 1}},
+			Options: options.Index().
+				SetName("faculties_facultyName_unique").
+				SetUnique(true).
+				SetPartialFilterExpression(nonEmptyString("facultyName")),
+		},
+		{
+			Keys:    bson.D{{Key: "majorIDs", Value: 1}},
+			Options: options.Index().SetName("faculties_majorIDs"),
+		},
+	},
+	"curricula": {
+		{
+			Keys: bson.D{{Key: "majorId", Value: 1}, {Key: "intakeYear", Value: 1}, {Key: "version", Value: 1}},
+			Options: options.Index().
+				SetName("curricula_majorId_intakeYear_version_unique").
+				SetUnique(true),
+		},
+	},
+	"studyplans": {
+		{
+			Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "updatedAt", Value: -1}},
+			Options: options.Index().SetName("studyplans_userId_updatedAt"),
+		},
+	},
+	"terms": {
+		{
+			Keys: bson.D{{Key: "academicYear", Value: 1}, {Key: "semester", Value: 1}},
+			Options: options.Index().
+				SetName("terms_academicYear_semester_unique").
+				SetUnique(true),
+		},
+	},
+	"offerings": {
+		{
+			Keys: bson.D{{Key: "termId", Value: 1}, {Key: "subjectId", Value: 1}, {Key: "campus", Value: 1}},
+			Options: options.Index().
+				SetName("offerings_termId_subjectId_campus_unique").
+				SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "subjectId", Value: 1}},
+			Options: options.Index().SetName("offerings_subjectId"),
+		},
+	},
+	"sections": {
+		{
+			Keys: bson.D{{Key: "offeringId", Value: 1}, {Key: "number", Value: 1}},
+			Options: options.Index().
+				SetName("sections_offeringId_number_unique").
+				SetUnique(true),
+		},
+	},
+	"jobruns": {
+		{
+			Keys: bson.D{{Key: "dedupKey", Value: 1}},
+			Options: options.Index().
+				SetName("jobruns_dedupKey_unique").
+				SetUnique(true).
+				SetPartialFilterExpression(nonEmptyString("dedupKey")),
+		},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "runAt", Value: 1}},
+			Options: options.Index().SetName("jobruns_status_runAt"),
+		},
+		{
+			Keys:    bson.D{{Key: "job", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("jobruns_job_createdAt"),
+		},
+		{
+			// Successful runs are kept for a week; dead runs stay until
+			// they are retried or discarded.
+			Keys: bson.D{{Key: "finishedAt", Value: 1}},
+			Options: options.Index().
+				SetName("jobruns_finishedAt_ttl").
+				SetExpireAfterSeconds(7 * 24 * 60 * 60).
+				SetPartialFilterExpression(bson.M{"status": "succeeded"}),
+		},
+	},
+	"majors": {
+		{
+			Keys:    bson.D{{Key: "subjectIDs", Value: 1}},
+			Options: options.Index().SetName("majors_subjectIDs"),
+		},
+	},
+}
+
+// EnsureIndexes creates any missing index declared in Indexes. Creating an
+// index that already exists with the same definition is a no-op, so this is
+// safe to run on every start. Failures are collected so one bad collection,
+// for example one that already holds duplicates, does not block the others.
+func EnsureIndexes(ctx context.Context) error {
+	var errs []error
+	for collection := range Indexes {
+		if err := EnsureCollectionIndexes(ctx, collection); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// EnsureCollectionIndexes creates the indexes declared for one collection.
+// Collections without declared indexes are left alone.
+func EnsureCollectionIndexes(ctx context.Context, collection string) error {
+	models, ok := Indexes[collection]
+	if !ok {
+		return nil
+	}
+	names, err := GetCollection(collection).Indexes().CreateMany(ctx, models)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to create indexes", "collection", collection, "error", err)
+		return err
+	}
+	logger.FromContext(ctx).Info("indexes ensured", "collection", collection, "indexes", names)
+	return nil
+}
@@ -1,14 +1,14 @@
 package db
 
 import (
+	"BackendCoursyclopedia/logger"
 	"context"
-	"fmt"
-	"log"
 	"os"
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 )
 
 var DB *mongo.Client
@@ -17,19 +17,21 @@ var Database *mongo.Database
 func ConnectDB() {
 	connectionString := os.Getenv("MONGODB_URI")
 
-	clientOptions := options.Client().ApplyURI(connectionString)
+	clientOptions := options.Client().
+		ApplyURI(connectionString).
+		SetMonitor(otelmongo.NewMonitor())
 
 	client, err := mongo.Connect(context.TODO(), clientOptions)
 	if err != nil {
-		log.Fatal(err)
+		logger.Fatal("failed to connect to MongoDB", "error", err)
 	}
 
 	err = client.Ping(context.TODO(), nil)
 	if err != nil {
-		log.Fatal(err)
+		logger.Fatal("failed to ping MongoDB", "error", err)
 	}
 
-	fmt.Println("Connected to MongoDB!")
+	logger.L().Info("connected to MongoDB")
 	DB = client
 	Database = client.Database("coursyclopediadb")
 }
@@ -47,7 +49,24 @@ func DisconnectDB() {
 	defer cancel()
 
 	if err := DB.Disconnect(ctx); err != nil {
-		log.Fatal(err)
+		logger.Fatal("failed to disconnect from MongoDB", "error", err)
 	}
-	fmt.Println("Connection to MongoDB closed.")
+	logger.L().Info("connection to MongoDB closed")
+}
+
+// WithTransaction runs fn in a transaction on a new session. Every database
+// call in fn must use the context it is given. The transaction is retried on
+// transient errors, so fn may run more than once and must not have side
+// effects outside the database. Transactions need a replica set.
+func WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := DB.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
 }
@@ -2,8 +2,6 @@ package auditloghandler
 
 import (
 	auditlogsvc "BackendCoursyclopedia/service/auditlogservice"
-	"context"
-	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -22,17 +20,12 @@ func NewAuditLogHandler(auditlogservice auditlogsvc.IAuditLogService) IAuditLogH
 	}
 }
 
-func (h *AuditLogHandler) withTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), 30*time.Second)
-}
-
 func (h *AuditLogHandler) GetAuditLogs(c *fiber.Ctx) error {
-	ctx, cancel := h.withTimeout()
-	defer cancel()
+	ctx := c.UserContext()
 
 	auditLogs, err := h.AuditLogService.GetAllAuditLogs(ctx)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -0,0 +1,101 @@
+package curriculumhandler
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/model/curriculummodel"
+	"BackendCoursyclopedia/service/curriculumservice"
+	"BackendCoursyclopedia/validation"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ICurriculumHandler interface {
+	GetCurricula(c *fiber.Ctx) error
+	GetCurriculum(c *fiber.Ctx) error
+	GetApplicableCurriculum(c *fiber.Ctx) error
+	SaveCurriculum(c *fiber.Ctx) error
+}
+
+type CurriculumHandler struct {
+	CurriculumService curriculumservice.ICurriculumService
+}
+
+func NewCurriculumHandler(curriculumService curriculumservice.ICurriculumService) ICurriculumHandler {
+	return &CurriculumHandler{
+		CurriculumService: curriculumService,
+	}
+}
+
+func (h *CurriculumHandler) GetCurricula(c *fiber.Ctx) error {
+	curricula, err := h.CurriculumService.GetCurricula(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Curricula retrieved successfully",
+		"data":    curricula,
+	})
+}
+
+// GetCurriculum returns the curriculum written for exactly this intake year,
+// the latest version unless ?version= is given.
+func (h *CurriculumHandler) GetCurriculum(c *fiber.Ctx) error {
+	intakeYear, err := c.ParamsInt("intakeYear")
+	if err != nil {
+		return apperror.InvalidArgument("intake year must be a number")
+	}
+
+	curriculum, err := h.CurriculumService.GetCurriculum(c.UserContext(), c.Params("id"), intakeYear, c.QueryInt("version", 0))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Curriculum retrieved successfully",
+		"data":    curriculum,
+	})
+}
+
+// GetApplicableCurriculum returns the curriculum that applies to students of
+// ?intakeYear=, defaulting to the current year.
+func (h *CurriculumHandler) GetApplicableCurriculum(c *fiber.Ctx) error {
+	intakeYear := c.QueryInt("intakeYear", time.Now().Year())
+
+	curriculum, err := h.CurriculumService.GetApplicableCurriculum(c.UserContext(), c.Params("id"), intakeYear)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Curriculum retrieved successfully",
+		"data":    curriculum,
+	})
+}
+
+func (h *CurriculumHandler) SaveCurriculum(c *fiber.Ctx) error {
+	intakeYear, err := c.ParamsInt("intakeYear")
+	if err != nil {
+		return apperror.InvalidArgument("intake year must be a number")
+	}
+
+	var request curriculummodel.CurriculumRequest
+	if err := c.BodyParser(&request); err != nil {
+		return apperror.InvalidArgument("invalid request body")
+	}
+	if err := validation.Struct(request); err != nil {
+		return err
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	curriculum, err := h.CurriculumService.SaveCurriculum(c.UserContext(), c.Params("id"), intakeYear, request, userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Curriculum saved successfully",
+		"data":    curriculum,
+	})
+}
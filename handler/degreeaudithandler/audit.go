@@ -0,0 +1,97 @@
+package degreeaudithandler
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/model/usermodel"
+	"BackendCoursyclopedia/service/degreeauditservice"
+	"BackendCoursyclopedia/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type IDegreeAuditHandler interface {
+	GetRecord(c *fiber.Ctx) error
+	SetProgram(c *fiber.Ctx) error
+	SetCompletedSubjects(c *fiber.Ctx) error
+	Audit(c *fiber.Ctx) error
+}
+
+type DegreeAuditHandler struct {
+	DegreeAuditService degreeauditservice.IDegreeAuditService
+}
+
+func NewDegreeAuditHandler(degreeAuditService degreeauditservice.IDegreeAuditService) IDegreeAuditHandler {
+	return &DegreeAuditHandler{
+		DegreeAuditService: degreeAuditService,
+	}
+}
+
+func (h *DegreeAuditHandler) GetRecord(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+
+	record, err := h.DegreeAuditService.GetRecord(c.UserContext(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Academic record retrieved successfully",
+		"data":    record,
+	})
+}
+
+func (h *DegreeAuditHandler) SetProgram(c *fiber.Ctx) error {
+	var request usermodel.ProgramRequest
+	if err := c.BodyParser(&request); err != nil {
+		return apperror.InvalidArgument("invalid request body")
+	}
+	if err := validation.Struct(request); err != nil {
+		return err
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	record, err := h.DegreeAuditService.SetProgram(c.UserContext(), userID, request)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Programme updated successfully",
+		"data":    record,
+	})
+}
+
+func (h *DegreeAuditHandler) SetCompletedSubjects(c *fiber.Ctx) error {
+	var request usermodel.CompletedSubjectsRequest
+	if err := c.BodyParser(&request); err != nil {
+		return apperror.InvalidArgument("invalid request body")
+	}
+	if err := validation.Struct(request); err != nil {
+		return err
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	record, err := h.DegreeAuditService.SetCompletedSubjects(c.UserContext(), userID, request)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Completed subjects updated successfully",
+		"data":    record,
+	})
+}
+
+func (h *DegreeAuditHandler) Audit(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+
+	report, err := h.DegreeAuditService.Audit(c.UserContext(), userID, c.Query("majorId"), c.QueryInt("intakeYear", 0))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Degree audit completed",
+		"data":    report,
+	})
+}
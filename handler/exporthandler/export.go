@@ -0,0 +1,68 @@
+package exporthandler
+
+import (
+	"BackendCoursyclopedia/catalogio"
+	"BackendCoursyclopedia/logger"
+	"BackendCoursyclopedia/service/exportservice"
+	"bufio"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// streamTimeout bounds writing the body. The body is streamed after the
+// handler returns, when the request deadline no longer applies.
+const streamTimeout = 10 * time.Minute
+
+type IExportHandler interface {
+	Export(c *fiber.Ctx) error
+}
+
+type ExportHandler struct {
+	ExportService exportservice.IExportService
+}
+
+func NewExportHandler(exportService exportservice.IExportService) IExportHandler {
+	return &ExportHandler{
+		ExportService: exportService,
+	}
+}
+
+func (h *ExportHandler) Export(c *fiber.Ctx) error {
+	format, err := catalogio.ParseFormat(c.Query("format", string(catalogio.FormatJSON)))
+	if err != nil {
+		return err
+	}
+
+	export, err := h.ExportService.Prepare(c.UserContext(), exportservice.ExportFilter{
+		FacultyID: c.Query("faculty"),
+		MajorID:   c.Query("major"),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Errors after this point can only be logged: the status line has
+	// already been sent, and the client sees a truncated file.
+	ctx := context.WithoutCancel(c.UserContext())
+	c.Set(fiber.HeaderContentType, format.ContentType())
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="catalogue.%s"`, format))
+	c.Context().SetBodyStreamWriter(func(bw *bufio.Writer) {
+		ctx, cancel := context.WithTimeout(ctx, streamTimeout)
+		defer cancel()
+
+		w, err := catalogio.NewSubjectWriter(bw, format)
+		if err == nil {
+			err = export.WriteTo(ctx, w)
+		}
+		if err == nil {
+			err = bw.Flush()
+		}
+		if err != nil {
+			logger.FromContext(ctx).Error("export stream failed", "format", format, "error", err)
+		}
+	})
+	return nil
+}
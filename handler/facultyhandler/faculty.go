@@ -1,15 +1,18 @@
 package facultyhandler
 
 import (
+	"BackendCoursyclopedia/apperror"
 	"BackendCoursyclopedia/model/facultymodel"
+	"BackendCoursyclopedia/validation"
 	"encoding/json"
+	"errors"
 	"io"
+	"mime/multipart"
 
 	facultysvc "BackendCoursyclopedia/service/facultyservice"
-	"context"
-	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
 )
 
 type IFacultyHandler interface {
@@ -31,17 +34,12 @@ func NewFacultyHandler(facultyService facultysvc.IFacultyService) IFacultyHandle
 	}
 }
 
-func (h FacultyHandler) withTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), 30*time.Second)
-}
-
 func (h FacultyHandler) GetFaculties(c *fiber.Ctx) error {
-	ctx, cancel := h.withTimeout()
-	defer cancel()
+	ctx := c.UserContext()
 
 	faculties, err := h.FacultyService.GetAllFaculties(ctx)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -51,13 +49,12 @@ func (h FacultyHandler) GetFaculties(c *fiber.Ctx) error {
 }
 
 func (h *FacultyHandler) GetEachFaculty(c *fiber.Ctx) error {
-	ctx, cancel := h.withTimeout()
-	defer cancel()
+	ctx := c.UserContext()
 
 	facultyID := c.Params("id")
 	faculty, err := h.FacultyService.GetFacultyByID(ctx, facultyID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -67,13 +64,12 @@ func (h *FacultyHandler) GetEachFaculty(c *fiber.Ctx) error {
 }
 
 func (h *FacultyHandler) GetMajorsForeachFaculty(c *fiber.Ctx) error {
-	ctx, cancel := h.withTimeout()
-	defer cancel()
+	ctx := c.UserContext()
 
 	facultyID := c.Params("id")
 	majors, err := h.FacultyService.GetMajorsForFaculty(ctx, facultyID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -83,38 +79,26 @@ func (h *FacultyHandler) GetMajorsForeachFaculty(c *fiber.Ctx) error {
 }
 
 func (h *FacultyHandler) CreateFaculty(c *fiber.Ctx) error {
-	ctx, cancel := h.withTimeout()
-	defer cancel()
+	ctx := c.UserContext()
 
 	file, err := c.FormFile("image")
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Image upload error"})
-	}
-
-	fileData, err := file.Open()
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to process image"})
+		return apperror.InvalidArgument("image upload error")
 	}
-	defer fileData.Close()
 
-	imageBytes, err := io.ReadAll(fileData)
+	imageBytes, err := readImage(file)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to read image data"})
+		return err
 	}
 
-	facultyName := c.FormValue("FacultyName")
-	if facultyName == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Faculty name is required"})
+	request := facultymodel.FacultyCreateRequest{FacultyName: c.FormValue("FacultyName")}
+	if err := validation.Struct(request); err != nil {
+		return err
 	}
 
-	faculty := facultymodel.Faculty{
-		FacultyName: facultyName,
-		Image:       imageBytes,
-	}
-
-	createdFaculty, err := h.FacultyService.CreateFaculty(ctx, faculty, imageBytes)
+	createdFaculty, err := h.FacultyService.CreateFaculty(ctx, request, imageBytes)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
@@ -124,39 +108,34 @@ func (h *FacultyHandler) CreateFaculty(c *fiber.Ctx) error {
 }
 
 func (h *FacultyHandler) UpdateFaculty(c *fiber.Ctx) error {
-	ctx, cancel := h.withTimeout()
-	defer cancel()
+	ctx := c.UserContext()
 
 	facultyID := c.Params("id")
-	var faculty facultymodel.Faculty
-	if err := c.BodyParser(&faculty); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
-	}
 
+	// Without a new image the faculty keeps its current one.
+	var imageBytes []byte
 	file, err := c.FormFile("image")
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Image upload error"})
-	}
-
-	fileData, err := file.Open()
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to process image"})
+	switch {
+	case errors.Is(err, fasthttp.ErrMissingFile):
+	case err != nil:
+		return apperror.InvalidArgument("image upload error")
+	default:
+		if imageBytes, err = readImage(file); err != nil {
+			return err
+		}
 	}
-	defer fileData.Close()
 
-	imageBytes, err := io.ReadAll(fileData)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to read image data"})
+	var request facultymodel.FacultyUpdateRequest
+	if err := json.Unmarshal([]byte(c.FormValue("faculty")), &request); err != nil {
+		return apperror.InvalidArgument("invalid faculty data")
 	}
-
-	facultyData := c.FormValue("faculty")
-	if err := json.Unmarshal([]byte(facultyData), &faculty); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid faculty data"})
+	if err := validation.Struct(request); err != nil {
+		return err
 	}
 
-	updatedFaculty, err := h.FacultyService.UpdateFaculty(ctx, facultyID, faculty, imageBytes)
+	updatedFaculty, err := h.FacultyService.UpdateFaculty(ctx, facultyID, request, imageBytes)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -166,16 +145,29 @@ func (h *FacultyHandler) UpdateFaculty(c *fiber.Ctx) error {
 }
 
 func (h FacultyHandler) DeleteFaculty(c *fiber.Ctx) error {
-	ctx, cancel := h.withTimeout()
-	defer cancel()
+	ctx := c.UserContext()
 
 	facultyID := c.Params("id")
 	err := h.FacultyService.DeleteFaculty(ctx, facultyID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
 		"message": "Faculty deleted successfully",
 	})
 }
+
+func readImage(file *multipart.FileHeader) ([]byte, error) {
+	fileData, err := file.Open()
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to process image")
+	}
+	defer fileData.Close()
+
+	imageBytes, err := io.ReadAll(fileData)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to read image data")
+	}
+	return imageBytes, nil
+}
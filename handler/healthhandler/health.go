@@ -0,0 +1,74 @@
+package healthhandler
+
+import (
+	"BackendCoursyclopedia/buildinfo"
+	"BackendCoursyclopedia/logger"
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const readinessTimeout = 2 * time.Second
+
+type IHealthHandler interface {
+	Healthz(c *fiber.Ctx) error
+	Readyz(c *fiber.Ctx) error
+	Version(c *fiber.Ctx) error
+}
+
+type HealthHandler struct {
+	DB *mongo.Client
+}
+
+func NewHealthHandler(db *mongo.Client) IHealthHandler {
+	return &HealthHandler{
+		DB: db,
+	}
+}
+
+// Healthz reports that the process is up. It never touches dependencies so a
+// slow database cannot get a live instance restarted.
+func (h *HealthHandler) Healthz(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"status": "ok"})
+}
+
+// Readyz reports whether the instance can serve traffic by pinging MongoDB.
+func (h *HealthHandler) Readyz(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), readinessTimeout)
+	defer cancel()
+
+	mongoStatus := fiber.Map{"status": "ok"}
+	status := fiber.StatusOK
+
+	start := time.Now()
+	// The endpoint is public, so the cause is only logged: driver errors
+	// name hosts and the replica set topology.
+	if h.DB == nil {
+		logger.FromContext(ctx).Warn("readiness check failed", "dependency", "mongodb", "error", "not connected")
+		mongoStatus = fiber.Map{"status": "down"}
+		status = fiber.StatusServiceUnavailable
+	} else if err := h.DB.Ping(ctx, nil); err != nil {
+		logger.FromContext(ctx).Warn("readiness check failed", "dependency", "mongodb", "error", err)
+		mongoStatus = fiber.Map{"status": "down"}
+		status = fiber.StatusServiceUnavailable
+	}
+	mongoStatus["latencyMs"] = time.Since(start).Milliseconds()
+
+	overall := "ok"
+	if status != fiber.StatusOK {
+		overall = "unavailable"
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"status": overall,
+		"dependencies": fiber.Map{
+			"mongodb": mongoStatus,
+		},
+	})
+}
+
+func (h *HealthHandler) Version(c *fiber.Ctx) error {
+	return c.JSON(buildinfo.Get())
+}
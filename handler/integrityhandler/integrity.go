@@ -0,0 +1,48 @@
+package integrityhandler
+
+import (
+	"BackendCoursyclopedia/service/integrityservice"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type IIntegrityHandler interface {
+	GetLatestReport(c *fiber.Ctx) error
+	RunCheck(c *fiber.Ctx) error
+}
+
+type IntegrityHandler struct {
+	IntegrityService integrityservice.IIntegrityService
+}
+
+func NewIntegrityHandler(integrityService integrityservice.IIntegrityService) IIntegrityHandler {
+	return &IntegrityHandler{
+		IntegrityService: integrityService,
+	}
+}
+
+func (h *IntegrityHandler) GetLatestReport(c *fiber.Ctx) error {
+	report, err := h.IntegrityService.GetLatestReport(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Latest integrity report retrieved successfully",
+		"data":    report,
+	})
+}
+
+func (h *IntegrityHandler) RunCheck(c *fiber.Ctx) error {
+	fix := c.QueryBool("fix", false)
+
+	report, err := h.IntegrityService.Check(c.UserContext(), fix)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Integrity check completed",
+		"data":    report,
+	})
+}
@@ -0,0 +1,127 @@
+package jobhandler
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/model/jobmodel"
+	"BackendCoursyclopedia/service/jobservice"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type IJobHandler interface {
+	GetJobs(c *fiber.Ctx) error
+	TriggerJob(c *fiber.Ctx) error
+	GetRuns(c *fiber.Ctx) error
+	GetRun(c *fiber.Ctx) error
+	GetDeadLetters(c *fiber.Ctx) error
+	RetryRun(c *fiber.Ctx) error
+	DiscardRun(c *fiber.Ctx) error
+}
+
+type JobHandler struct {
+	JobService jobservice.IJobService
+}
+
+func NewJobHandler(jobService jobservice.IJobService) IJobHandler {
+	return &JobHandler{
+		JobService: jobService,
+	}
+}
+
+func (h *JobHandler) GetJobs(c *fiber.Ctx) error {
+	jobs, err := h.JobService.GetJobs(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Jobs retrieved successfully",
+		"data":    jobs,
+	})
+}
+
+// TriggerJob queues a run of a job now. The body is optional and may carry
+// a payload for the job.
+func (h *JobHandler) TriggerJob(c *fiber.Ctx) error {
+	var request jobmodel.TriggerRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&request); err != nil {
+			return apperror.InvalidArgument("invalid request body")
+		}
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	run, err := h.JobService.TriggerJob(c.UserContext(), c.Params("name"), request.Payload, userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message": "Job queued",
+		"data":    run,
+	})
+}
+
+// GetRuns lists runs newest first, optionally only those of ?job= or with
+// ?status=, up to ?limit=.
+func (h *JobHandler) GetRuns(c *fiber.Ctx) error {
+	runs, err := h.JobService.GetRuns(c.UserContext(), jobmodel.Filter{
+		Job:    c.Query("job"),
+		Status: c.Query("status"),
+		Limit:  int64(c.QueryInt("limit", 0)),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Job runs retrieved successfully",
+		"data":    runs,
+	})
+}
+
+func (h *JobHandler) GetRun(c *fiber.Ctx) error {
+	run, err := h.JobService.GetRun(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Job run retrieved successfully",
+		"data":    run,
+	})
+}
+
+func (h *JobHandler) GetDeadLetters(c *fiber.Ctx) error {
+	runs, err := h.JobService.GetDeadLetters(c.UserContext(), int64(c.QueryInt("limit", 0)))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Dead job runs retrieved successfully",
+		"data":    runs,
+	})
+}
+
+func (h *JobHandler) RetryRun(c *fiber.Ctx) error {
+	run, err := h.JobService.RetryRun(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message": "Job run queued for retry",
+		"data":    run,
+	})
+}
+
+func (h *JobHandler) DiscardRun(c *fiber.Ctx) error {
+	if err := h.JobService.DiscardRun(c.UserContext(), c.Params("id")); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Job run discarded",
+	})
+}
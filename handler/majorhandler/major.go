@@ -1,9 +1,10 @@
 package majorhandler
 
 import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/model/majormodel"
 	"BackendCoursyclopedia/service/majorservice"
-	"context"
-	"time"
+	"BackendCoursyclopedia/validation"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -25,17 +26,12 @@ func NewMajorHandler(majorService majorservice.IMajorService) *MajorHandler {
 	}
 }
 
-func (h MajorHandler) withTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), 30*time.Second)
-}
-
 func (h MajorHandler) GetMajors(c *fiber.Ctx) error {
-	ctx, cancel := h.withTimeout()
-	defer cancel()
+	ctx := c.UserContext()
 
 	majors, err := h.MajorService.GetAllMajors(ctx)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -45,13 +41,12 @@ func (h MajorHandler) GetMajors(c *fiber.Ctx) error {
 }
 
 func (h *MajorHandler) Geteachmajor(c *fiber.Ctx) error {
-	ctx, cancel := h.withTimeout()
-	defer cancel()
+	ctx := c.UserContext()
 
 	majorID := c.Params("id")
 	major, err := h.MajorService.GetMajorByID(ctx, majorID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -61,13 +56,12 @@ func (h *MajorHandler) Geteachmajor(c *fiber.Ctx) error {
 }
 
 func (h *MajorHandler) GetSubjectsForeachMajor(c *fiber.Ctx) error {
-	ctx, cancel := h.withTimeout()
-	defer cancel()
+	ctx := c.UserContext()
 
 	majorID := c.Params("id")
 	subjects, err := h.MajorService.GetSubjectsForMajor(ctx, majorID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -77,17 +71,17 @@ func (h *MajorHandler) GetSubjectsForeachMajor(c *fiber.Ctx) error {
 }
 
 func (h *MajorHandler) CreateMajor(c *fiber.Ctx) error {
-	var request struct {
-		MajorName string `json:"majorName"`
-		FacultyID string `json:"facultyId"`
-	}
+	var request majormodel.MajorCreateRequest
 	if err := c.BodyParser(&request); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		return apperror.InvalidArgument("invalid request body")
+	}
+	if err := validation.Struct(request); err != nil {
+		return err
 	}
 
-	err := h.MajorService.CreateMajor(request.MajorName, request.FacultyID)
+	err := h.MajorService.CreateMajor(c.UserContext(), request.MajorName, request.FacultyID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -98,9 +92,9 @@ func (h *MajorHandler) CreateMajor(c *fiber.Ctx) error {
 func (h *MajorHandler) DeleteMajor(c *fiber.Ctx) error {
 	majorId := c.Params("id")
 
-	err := h.MajorService.DeleteMajor(majorId)
+	err := h.MajorService.DeleteMajor(c.UserContext(), majorId)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -110,18 +104,18 @@ func (h *MajorHandler) DeleteMajor(c *fiber.Ctx) error {
 
 func (h *MajorHandler) UpdateMajor(c *fiber.Ctx) error {
 	majorId := c.Params("id")
-	var request struct {
-		NewMajorName string `json:"newMajorName"`
-		NewFacultyID string `json:"newFacultyId"`
-	}
+	var request majormodel.MajorUpdateRequest
 	if err := c.BodyParser(&request); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		return apperror.InvalidArgument("invalid request body")
+	}
+	if err := validation.Struct(request); err != nil {
+		return err
 	}
 
-	ctx := context.Background()
+	ctx := c.UserContext()
 	err := h.MajorService.UpdateMajor(ctx, majorId, request.NewMajorName, request.NewFacultyID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -0,0 +1,59 @@
+package notificationhandler
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/model/usermodel"
+	"BackendCoursyclopedia/service/notificationservice"
+	"BackendCoursyclopedia/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type INotificationHandler interface {
+	GetPreferences(c *fiber.Ctx) error
+	UpdatePreferences(c *fiber.Ctx) error
+}
+
+type NotificationHandler struct {
+	NotificationService notificationservice.INotificationService
+}
+
+func NewNotificationHandler(notificationService notificationservice.INotificationService) INotificationHandler {
+	return &NotificationHandler{
+		NotificationService: notificationService,
+	}
+}
+
+func (h *NotificationHandler) GetPreferences(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	prefs, err := h.NotificationService.GetPreferences(c.UserContext(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Notification preferences retrieved successfully",
+		"data":    prefs,
+	})
+}
+
+func (h *NotificationHandler) UpdatePreferences(c *fiber.Ctx) error {
+	var request usermodel.NotificationPreferencesRequest
+	if err := c.BodyParser(&request); err != nil {
+		return apperror.InvalidArgument("invalid request body")
+	}
+	if err := validation.Struct(request); err != nil {
+		return err
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	prefs, err := h.NotificationService.UpdatePreferences(c.UserContext(), userID, request)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Notification preferences updated successfully",
+		"data":    prefs,
+	})
+}
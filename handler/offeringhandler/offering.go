@@ -0,0 +1,130 @@
+package offeringhandler
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/model/offeringmodel"
+	"BackendCoursyclopedia/service/offeringservice"
+	"BackendCoursyclopedia/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type IOfferingHandler interface {
+	GetOffering(c *fiber.Ctx) error
+	GetOfferingsByTerm(c *fiber.Ctx) error
+	GetOfferingsBySubject(c *fiber.Ctx) error
+	GetOfferingsByMajor(c *fiber.Ctx) error
+	CreateOffering(c *fiber.Ctx) error
+	UpdateOffering(c *fiber.Ctx) error
+	DeleteOffering(c *fiber.Ctx) error
+}
+
+type OfferingHandler struct {
+	OfferingService offeringservice.IOfferingService
+}
+
+func NewOfferingHandler(offeringService offeringservice.IOfferingService) IOfferingHandler {
+	return &OfferingHandler{
+		OfferingService: offeringService,
+	}
+}
+
+func (h *OfferingHandler) GetOffering(c *fiber.Ctx) error {
+	offering, err := h.OfferingService.GetOffering(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Offering retrieved successfully",
+		"data":    offering,
+	})
+}
+
+// GetOfferingsByTerm lists a term's offerings, optionally only those with
+// ?status=.
+func (h *OfferingHandler) GetOfferingsByTerm(c *fiber.Ctx) error {
+	offerings, err := h.OfferingService.GetOfferingsByTerm(c.UserContext(), c.Params("id"), c.Query("status"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Offerings retrieved successfully",
+		"data":    offerings,
+	})
+}
+
+func (h *OfferingHandler) GetOfferingsBySubject(c *fiber.Ctx) error {
+	offerings, err := h.OfferingService.GetOfferingsBySubject(c.UserContext(), c.Params("id"), c.Query("term"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Offerings retrieved successfully",
+		"data":    offerings,
+	})
+}
+
+func (h *OfferingHandler) GetOfferingsByMajor(c *fiber.Ctx) error {
+	offerings, err := h.OfferingService.GetOfferingsByMajor(c.UserContext(), c.Params("id"), c.Query("term"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Offerings retrieved successfully",
+		"data":    offerings,
+	})
+}
+
+func (h *OfferingHandler) CreateOffering(c *fiber.Ctx) error {
+	var request offeringmodel.OfferingRequest
+	if err := c.BodyParser(&request); err != nil {
+		return apperror.InvalidArgument("invalid request body")
+	}
+	if err := validation.Struct(request); err != nil {
+		return err
+	}
+
+	offering, err := h.OfferingService.CreateOffering(c.UserContext(), request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Offering created successfully",
+		"data":    offering,
+	})
+}
+
+func (h *OfferingHandler) UpdateOffering(c *fiber.Ctx) error {
+	var request offeringmodel.OfferingRequest
+	if err := c.BodyParser(&request); err != nil {
+		return apperror.InvalidArgument("invalid request body")
+	}
+	if err := validation.Struct(request); err != nil {
+		return err
+	}
+
+	offering, err := h.OfferingService.UpdateOffering(c.UserContext(), c.Params("id"), request)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Offering updated successfully",
+		"data":    offering,
+	})
+}
+
+func (h *OfferingHandler) DeleteOffering(c *fiber.Ctx) error {
+	if err := h.OfferingService.DeleteOffering(c.UserContext(), c.Params("id")); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Offering deleted successfully",
+	})
+}
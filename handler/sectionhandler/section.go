@@ -0,0 +1,102 @@
+package sectionhandler
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/model/sectionmodel"
+	"BackendCoursyclopedia/service/sectionservice"
+	"BackendCoursyclopedia/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ISectionHandler interface {
+	GetSectionsByOffering(c *fiber.Ctx) error
+	GetSection(c *fiber.Ctx) error
+	CreateSection(c *fiber.Ctx) error
+	UpdateSection(c *fiber.Ctx) error
+	DeleteSection(c *fiber.Ctx) error
+}
+
+type SectionHandler struct {
+	SectionService sectionservice.ISectionService
+}
+
+func NewSectionHandler(sectionService sectionservice.ISectionService) ISectionHandler {
+	return &SectionHandler{
+		SectionService: sectionService,
+	}
+}
+
+func (h *SectionHandler) GetSectionsByOffering(c *fiber.Ctx) error {
+	sections, err := h.SectionService.GetSectionsByOffering(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Sections retrieved successfully",
+		"data":    sections,
+	})
+}
+
+func (h *SectionHandler) GetSection(c *fiber.Ctx) error {
+	section, err := h.SectionService.GetSection(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Section retrieved successfully",
+		"data":    section,
+	})
+}
+
+func (h *SectionHandler) CreateSection(c *fiber.Ctx) error {
+	var request sectionmodel.SectionRequest
+	if err := c.BodyParser(&request); err != nil {
+		return apperror.InvalidArgument("invalid request body")
+	}
+	if err := validation.Struct(request); err != nil {
+		return err
+	}
+
+	section, err := h.SectionService.CreateSection(c.UserContext(), c.Params("id"), request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Section created successfully",
+		"data":    section,
+	})
+}
+
+func (h *SectionHandler) UpdateSection(c *fiber.Ctx) error {
+	var request sectionmodel.SectionRequest
+	if err := c.BodyParser(&request); err != nil {
+		return apperror.InvalidArgument("invalid request body")
+	}
+	if err := validation.Struct(request); err != nil {
+		return err
+	}
+
+	section, err := h.SectionService.UpdateSection(c.UserContext(), c.Params("id"), request)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Section updated successfully",
+		"data":    section,
+	})
+}
+
+func (h *SectionHandler) DeleteSection(c *fiber.Ctx) error {
+	if err := h.SectionService.DeleteSection(c.UserContext(), c.Params("id")); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Section deleted successfully",
+	})
+}
@@ -0,0 +1,151 @@
+package studyplanhandler
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/model/studyplanmodel"
+	"BackendCoursyclopedia/service/studyplanservice"
+	"BackendCoursyclopedia/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type IStudyPlanHandler interface {
+	Generate(c *fiber.Ctx) error
+	GetPlans(c *fiber.Ctx) error
+	GetPlan(c *fiber.Ctx) error
+	SavePlan(c *fiber.Ctx) error
+	UpdatePlan(c *fiber.Ctx) error
+	RevalidatePlan(c *fiber.Ctx) error
+	DeletePlan(c *fiber.Ctx) error
+}
+
+type StudyPlanHandler struct {
+	StudyPlanService studyplanservice.IStudyPlanService
+}
+
+func NewStudyPlanHandler(studyPlanService studyplanservice.IStudyPlanService) IStudyPlanHandler {
+	return &StudyPlanHandler{
+		StudyPlanService: studyPlanService,
+	}
+}
+
+func (h *StudyPlanHandler) Generate(c *fiber.Ctx) error {
+	var request studyplanmodel.GenerateRequest
+	if err := c.BodyParser(&request); err != nil {
+		return apperror.InvalidArgument("invalid request body")
+	}
+	if err := validation.Struct(request); err != nil {
+		return err
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	plan, err := h.StudyPlanService.Generate(c.UserContext(), userID, request)
+	if err != nil {
+		return err
+	}
+
+	message := "Study plan generated"
+	if !plan.Feasible {
+		message = "Study plan generated with issues"
+	}
+	return c.JSON(fiber.Map{
+		"message": message,
+		"data":    plan,
+	})
+}
+
+func (h *StudyPlanHandler) GetPlans(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+
+	plans, err := h.StudyPlanService.GetPlans(c.UserContext(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Study plans retrieved successfully",
+		"data":    plans,
+	})
+}
+
+func (h *StudyPlanHandler) GetPlan(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+
+	plan, err := h.StudyPlanService.GetPlan(c.UserContext(), userID, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Study plan retrieved successfully",
+		"data":    plan,
+	})
+}
+
+func (h *StudyPlanHandler) SavePlan(c *fiber.Ctx) error {
+	var request studyplanmodel.PlanRequest
+	if err := c.BodyParser(&request); err != nil {
+		return apperror.InvalidArgument("invalid request body")
+	}
+	if err := validation.Struct(request); err != nil {
+		return err
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	plan, err := h.StudyPlanService.SavePlan(c.UserContext(), userID, request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Study plan saved successfully",
+		"data":    plan,
+	})
+}
+
+func (h *StudyPlanHandler) UpdatePlan(c *fiber.Ctx) error {
+	var request studyplanmodel.PlanRequest
+	if err := c.BodyParser(&request); err != nil {
+		return apperror.InvalidArgument("invalid request body")
+	}
+	if err := validation.Struct(request); err != nil {
+		return err
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	plan, err := h.StudyPlanService.UpdatePlan(c.UserContext(), userID, c.Params("id"), request)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Study plan updated successfully",
+		"data":    plan,
+	})
+}
+
+func (h *StudyPlanHandler) RevalidatePlan(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+
+	plan, err := h.StudyPlanService.RevalidatePlan(c.UserContext(), userID, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Study plan validated",
+		"data":    plan,
+	})
+}
+
+func (h *StudyPlanHandler) DeletePlan(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+
+	if err := h.StudyPlanService.DeletePlan(c.UserContext(), userID, c.Params("id")); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Study plan deleted successfully",
+	})
+}
@@ -0,0 +1,84 @@
+package subjecthandler
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/catalogio"
+	"BackendCoursyclopedia/model/subjectmodel"
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ImportSubjects accepts a catalogue file either as the "file" field of a
+// multipart form or as the raw request body. The format comes from the
+// "format" query parameter, else the file extension, else the content type.
+func (h *SubjectHandler) ImportSubjects(c *fiber.Ctx) error {
+	var (
+		body     io.Reader
+		filename string
+	)
+	if fh, err := c.FormFile("file"); err == nil {
+		f, err := fh.Open()
+		if err != nil {
+			return apperror.InvalidArgument("cannot read uploaded file")
+		}
+		defer f.Close()
+		body, filename = f, fh.Filename
+	} else {
+		body = bytes.NewReader(c.Body())
+	}
+
+	format, err := importFormat(c.Query("format"), filename, c.Get(fiber.HeaderContentType))
+	if err != nil {
+		return err
+	}
+
+	rows, err := catalogio.ReadSubjects(body, format)
+	if err != nil {
+		return err
+	}
+
+	opts := subjectmodel.SubjectImportOptions{
+		DryRun:       c.QueryBool("dryRun", false),
+		AllOrNothing: c.QueryBool("allOrNothing", false),
+	}
+	report, err := h.SubjectService.ImportSubjects(c.UserContext(), rows, opts)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case report.DryRun:
+		return c.JSON(fiber.Map{"message": "Dry run completed, nothing was written", "data": report})
+	case !report.Applied:
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"message": "Import rejected because some rows are invalid",
+			"data":    report,
+		})
+	case report.Failed > 0:
+		return c.JSON(fiber.Map{"message": "Some subjects could not be written; see the errors in the report", "data": report})
+	}
+	return c.JSON(fiber.Map{"message": "Subjects imported successfully", "data": report})
+}
+
+func importFormat(query, filename, contentType string) (catalogio.Format, error) {
+	if query != "" {
+		return catalogio.ParseFormat(query)
+	}
+	if filename != "" {
+		return catalogio.FormatFromFilename(filename)
+	}
+	switch {
+	case strings.HasPrefix(contentType, "text/csv"):
+		return catalogio.FormatCSV, nil
+	case strings.HasPrefix(contentType, "application/x-ndjson"):
+		return catalogio.FormatNDJSON, nil
+	case strings.HasPrefix(contentType, "application/json"):
+		return catalogio.FormatJSON, nil
+	case strings.HasPrefix(contentType, catalogio.FormatXLSX.ContentType()):
+		return catalogio.FormatXLSX, nil
+	}
+	return "", apperror.InvalidArgument("cannot tell the file format; pass ?format=csv|json|ndjson|xlsx")
+}
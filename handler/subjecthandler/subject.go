@@ -1,10 +1,10 @@
 package subjecthandler
 
 import (
+	"BackendCoursyclopedia/apperror"
 	"BackendCoursyclopedia/model/subjectmodel"
 	"BackendCoursyclopedia/service/subjectservice"
-	"context"
-	"time"
+	"BackendCoursyclopedia/validation"
 
 	// "context"
 
@@ -21,6 +21,7 @@ type ISubjectHandler interface {
 	UpdateSubject(c *fiber.Ctx) error
 	UpdateSubjectLikes(c *fiber.Ctx) error
 	AddLikeByEmailHandler(c *fiber.Ctx) error
+	ImportSubjects(c *fiber.Ctx) error
 }
 type SubjectHandler struct {
 	SubjectService subjectservice.ISubjectService
@@ -32,17 +33,12 @@ func NewSubjectHandler(subjectService subjectservice.ISubjectService) *SubjectHa
 	}
 }
 
-func (h SubjectHandler) withTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), 30*time.Second)
-}
-
 func (h SubjectHandler) GetSubjects(c *fiber.Ctx) error {
-	ctx, cancel := h.withTimeout()
-	defer cancel()
+	ctx := c.UserContext()
 
 	subjects, err := h.SubjectService.GetAllSubjects(ctx)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -52,13 +48,12 @@ func (h SubjectHandler) GetSubjects(c *fiber.Ctx) error {
 }
 
 func (h *SubjectHandler) GetEachSubject(c *fiber.Ctx) error {
-	ctx, cancel := h.withTimeout()
-	defer cancel()
+	ctx := c.UserContext()
 
 	subjectID := c.Params("id")
 	subject, err := h.SubjectService.GetSubjectByID(ctx, subjectID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -68,17 +63,22 @@ func (h *SubjectHandler) GetEachSubject(c *fiber.Ctx) error {
 }
 
 func (h *SubjectHandler) CreateSubject(c *fiber.Ctx) error {
-	var request struct {
-		subjectmodel.Subject
-		MajorId string `json:"majorId"`
-	}
+	var request subjectmodel.SubjectCreateRequest
 	if err := c.BodyParser(&request); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		return apperror.InvalidArgument("invalid request body")
+	}
+	if err := validation.Struct(request); err != nil {
+		return err
 	}
 
-	createdSubjectId, err := h.SubjectService.CreateSubject(c.Context(), request.Subject, request.MajorId)
+	subject, err := request.ToSubject()
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return apperror.InvalidArgument("invalid professor ID")
+	}
+
+	createdSubjectId, err := h.SubjectService.CreateSubject(c.UserContext(), subject, request.MajorID)
+	if err != nil {
+		return err
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"id": createdSubjectId, "message": "Major created successfully"})
@@ -87,9 +87,9 @@ func (h *SubjectHandler) CreateSubject(c *fiber.Ctx) error {
 func (h *SubjectHandler) DeleteSubject(c *fiber.Ctx) error {
 	subjectId := c.Params("id")
 
-	err := h.SubjectService.DeleteSubject(subjectId)
+	err := h.SubjectService.DeleteSubject(c.UserContext(), subjectId)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -106,24 +106,27 @@ func (h *SubjectHandler) UpdateSubject(c *fiber.Ctx) error {
 	}
 
 	if err := c.BodyParser(&request); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return apperror.InvalidArgument("invalid request body")
+	}
+	if err := validation.Struct(request.SubjectUpdateRequest); err != nil {
+		return err
 	}
 
 	professorObjectIDs := make([]primitive.ObjectID, len(request.Professors))
 	for i, profStr := range request.Professors {
 		profID, err := primitive.ObjectIDFromHex(profStr)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid professor ID"})
+			return apperror.InvalidArgument("invalid professor ID")
 		}
 		professorObjectIDs[i] = profID
 	}
 
 	request.SubjectUpdateRequest.Professors = professorObjectIDs
 
-	ctx := context.Background()
+	ctx := c.UserContext()
 	err := h.SubjectService.UpdateSubject(ctx, subjectId, request.SubjectUpdateRequest, request.NewMajorId)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -138,13 +141,13 @@ func (h *SubjectHandler) UpdateSubjectLikes(c *fiber.Ctx) error {
 		Likes int `json:"likes"`
 	}
 	if err := c.BodyParser(&request); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return apperror.InvalidArgument("invalid request body")
 	}
 
-	ctx := c.Context()
+	ctx := c.UserContext()
 	err := h.SubjectService.UpdateLikes(ctx, subjectId, request.Likes)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -153,24 +156,23 @@ func (h *SubjectHandler) UpdateSubjectLikes(c *fiber.Ctx) error {
 }
 
 func (h *SubjectHandler) AddLikeByEmailHandler(c *fiber.Ctx) error {
-	ctx, cancel := h.withTimeout()
-	defer cancel()
+	ctx := c.UserContext()
 	var request struct {
 		Email string `json:"email"`
 	}
 
 	if err := c.BodyParser(&request); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return apperror.InvalidArgument("invalid request body")
 	}
 
 	subjectId := c.Params("id")
 	if subjectId == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Subject ID is required"})
+		return apperror.InvalidArgument("subject ID is required")
 	}
 
 	err := h.SubjectService.AddLikeByEmail(ctx, subjectId, request.Email)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
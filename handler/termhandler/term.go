@@ -0,0 +1,102 @@
+package termhandler
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/model/termmodel"
+	"BackendCoursyclopedia/service/termservice"
+	"BackendCoursyclopedia/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ITermHandler interface {
+	GetTerms(c *fiber.Ctx) error
+	GetTerm(c *fiber.Ctx) error
+	CreateTerm(c *fiber.Ctx) error
+	UpdateTerm(c *fiber.Ctx) error
+	DeleteTerm(c *fiber.Ctx) error
+}
+
+type TermHandler struct {
+	TermService termservice.ITermService
+}
+
+func NewTermHandler(termService termservice.ITermService) ITermHandler {
+	return &TermHandler{
+		TermService: termService,
+	}
+}
+
+func (h *TermHandler) GetTerms(c *fiber.Ctx) error {
+	terms, err := h.TermService.GetTerms(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Terms retrieved successfully",
+		"data":    terms,
+	})
+}
+
+func (h *TermHandler) GetTerm(c *fiber.Ctx) error {
+	term, err := h.TermService.GetTerm(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Term retrieved successfully",
+		"data":    term,
+	})
+}
+
+func (h *TermHandler) CreateTerm(c *fiber.Ctx) error {
+	var request termmodel.TermRequest
+	if err := c.BodyParser(&request); err != nil {
+		return apperror.InvalidArgument("invalid request body")
+	}
+	if err := validation.Struct(request); err != nil {
+		return err
+	}
+
+	term, err := h.TermService.CreateTerm(c.UserContext(), request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Term created successfully",
+		"data":    term,
+	})
+}
+
+func (h *TermHandler) UpdateTerm(c *fiber.Ctx) error {
+	var request termmodel.TermRequest
+	if err := c.BodyParser(&request); err != nil {
+		return apperror.InvalidArgument("invalid request body")
+	}
+	if err := validation.Struct(request); err != nil {
+		return err
+	}
+
+	term, err := h.TermService.UpdateTerm(c.UserContext(), c.Params("id"), request)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Term updated successfully",
+		"data":    term,
+	})
+}
+
+func (h *TermHandler) DeleteTerm(c *fiber.Ctx) error {
+	if err := h.TermService.DeleteTerm(c.UserContext(), c.Params("id")); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Term deleted successfully",
+	})
+}
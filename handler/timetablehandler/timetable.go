@@ -0,0 +1,194 @@
+package timetablehandler
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/ical"
+	"BackendCoursyclopedia/model/timetablemodel"
+	"BackendCoursyclopedia/service/timetableservice"
+	"BackendCoursyclopedia/validation"
+	"os"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ITimetableHandler interface {
+	Check(c *fiber.Ctx) error
+	Generate(c *fiber.Ctx) error
+	GetMyTimetable(c *fiber.Ctx) error
+	SaveMyTimetable(c *fiber.Ctx) error
+	Calendar(c *fiber.Ctx) error
+	SubscribedCalendar(c *fiber.Ctx) error
+	CreateSubscription(c *fiber.Ctx) error
+	RevokeSubscription(c *fiber.Ctx) error
+}
+
+type TimetableHandler struct {
+	TimetableService timetableservice.ITimetableService
+}
+
+func NewTimetableHandler(timetableService timetableservice.ITimetableService) ITimetableHandler {
+	return &TimetableHandler{
+		TimetableService: timetableService,
+	}
+}
+
+func (h *TimetableHandler) Check(c *fiber.Ctx) error {
+	var request timetablemodel.CheckRequest
+	if err := c.BodyParser(&request); err != nil {
+		return apperror.InvalidArgument("invalid request body")
+	}
+	if err := validation.Struct(request); err != nil {
+		return err
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	report, err := h.TimetableService.Check(c.UserContext(), userID, request)
+	if err != nil {
+		return err
+	}
+
+	message := "Timetable has no conflicts"
+	if !report.Valid {
+		message = "Timetable has conflicts"
+	}
+	return c.JSON(fiber.Map{
+		"message": message,
+		"data":    report,
+	})
+}
+
+func (h *TimetableHandler) Generate(c *fiber.Ctx) error {
+	var request timetablemodel.GenerateRequest
+	if err := c.BodyParser(&request); err != nil {
+		return apperror.InvalidArgument("invalid request body")
+	}
+	if err := validation.Struct(request); err != nil {
+		return err
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	result, err := h.TimetableService.Generate(c.UserContext(), userID, request)
+	if err != nil {
+		return err
+	}
+
+	message := "Timetables generated"
+	if len(result.Timetables) == 0 {
+		message = "No conflict-free timetable found"
+	}
+	return c.JSON(fiber.Map{
+		"message": message,
+		"data":    result,
+	})
+}
+
+func (h *TimetableHandler) GetMyTimetable(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+
+	report, err := h.TimetableService.GetMyTimetable(c.UserContext(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Timetable retrieved successfully",
+		"data":    report,
+	})
+}
+
+func (h *TimetableHandler) SaveMyTimetable(c *fiber.Ctx) error {
+	var request timetablemodel.SaveRequest
+	if err := c.BodyParser(&request); err != nil {
+		return apperror.InvalidArgument("invalid request body")
+	}
+	if err := validation.Struct(request); err != nil {
+		return err
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	report, err := h.TimetableService.SaveMyTimetable(c.UserContext(), userID, request)
+	if err != nil {
+		return err
+	}
+
+	message := "Timetable saved successfully"
+	if !report.Valid {
+		message = "Timetable saved with conflicts"
+	}
+	return c.JSON(fiber.Map{
+		"message": message,
+		"data":    report,
+	})
+}
+
+// Calendar downloads the user's timetable as an iCalendar file.
+func (h *TimetableHandler) Calendar(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+
+	calendar, err := h.TimetableService.Calendar(c.UserContext(), userID)
+	if err != nil {
+		return err
+	}
+
+	c.Attachment("timetable.ics")
+	return sendCalendar(c, calendar)
+}
+
+// SubscribedCalendar serves the feed behind a subscription URL. It is not
+// authenticated: the token in the URL is the credential.
+func (h *TimetableHandler) SubscribedCalendar(c *fiber.Ctx) error {
+	calendar, err := h.TimetableService.CalendarByToken(c.UserContext(), c.Params("token"))
+	if apperror.Is(err, apperror.KindNotFound) {
+		return apperror.NotFound("calendar not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	return sendCalendar(c, calendar)
+}
+
+// CreateSubscription issues a calendar subscription URL, replacing any
+// earlier one.
+func (h *TimetableHandler) CreateSubscription(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+
+	token, err := h.TimetableService.CreateCalendarToken(c.UserContext(), userID)
+	if err != nil {
+		return err
+	}
+
+	base := os.Getenv("PUBLIC_BASE_URL")
+	if base == "" {
+		base = c.BaseURL()
+	}
+	url := strings.TrimSuffix(base, "/") + "/api/calendar/" + token + ".ics"
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Calendar subscription created; the URL is shown only once",
+		"data": fiber.Map{
+			"url":    url,
+			"webcal": "webcal://" + strings.SplitN(url, "://", 2)[1],
+		},
+	})
+}
+
+func (h *TimetableHandler) RevokeSubscription(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+
+	if err := h.TimetableService.RevokeCalendarToken(c.UserContext(), userID); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Calendar subscription revoked",
+	})
+}
+
+func sendCalendar(c *fiber.Ctx, calendar *ical.Calendar) error {
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Set(fiber.HeaderCacheControl, "private, max-age=900")
+	_, err := calendar.WriteTo(c.Response().BodyWriter())
+	return err
+}
@@ -1,10 +1,10 @@
 package userhandler
 
 import (
+	"BackendCoursyclopedia/apperror"
 	"BackendCoursyclopedia/model/usermodel"
 	usersvc "BackendCoursyclopedia/service/userservice"
-	"context"
-	"time"
+	"BackendCoursyclopedia/validation"
 
 	"github.com/gofiber/fiber/v2"
 	"golang.org/x/crypto/bcrypt"
@@ -20,6 +20,12 @@ type IUserHandler interface {
 	DropAllUsers(c *fiber.Ctx) error
 	Login(c *fiber.Ctx) error
 	GoogleLogin(c *fiber.Ctx) error
+	ForgotPassword(c *fiber.Ctx) error
+	ResetPassword(c *fiber.Ctx) error
+	VerifyEmail(c *fiber.Ctx) error
+	ResendVerification(c *fiber.Ctx) error
+	SuspendUser(c *fiber.Ctx) error
+	ReactivateUser(c *fiber.Ctx) error
 }
 
 type UserHandler struct {
@@ -32,17 +38,12 @@ func NewUserHandler(userService usersvc.IUserService) IUserHandler {
 	}
 }
 
-func (h UserHandler) withTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), 30*time.Second)
-}
-
 func (h *UserHandler) GetUsers(c *fiber.Ctx) error {
-	ctx, cancel := h.withTimeout()
-	defer cancel()
+	ctx := c.UserContext()
 
 	users, err := h.UserService.GetAllUsers(ctx)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 	for i := range users {
 		users[i].Password = ""
@@ -55,13 +56,12 @@ func (h *UserHandler) GetUsers(c *fiber.Ctx) error {
 }
 
 func (h *UserHandler) GetOneUser(c *fiber.Ctx) error {
-	ctx, cancel := h.withTimeout()
-	defer cancel()
+	ctx := c.UserContext()
 
 	userID := c.Params("id") // Assuming the user ID is passed as a URL parameter
 	user, err := h.UserService.GetUserByID(ctx, userID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 	user.Password = ""
 
@@ -72,17 +72,16 @@ func (h *UserHandler) GetOneUser(c *fiber.Ctx) error {
 }
 
 func (h *UserHandler) GetUserByEmail(c *fiber.Ctx) error {
-	ctx, cancel := h.withTimeout()
-	defer cancel()
+	ctx := c.UserContext()
 
 	email := c.Params("email")
 	if email == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Email is required"})
+		return apperror.InvalidArgument("email is required")
 	}
 
 	user, err := h.UserService.GetUserByEmail(ctx, email)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 	user.Password = ""
 
@@ -93,17 +92,19 @@ func (h *UserHandler) GetUserByEmail(c *fiber.Ctx) error {
 }
 
 func (h *UserHandler) CreateOneUser(c *fiber.Ctx) error {
-	ctx, cancel := h.withTimeout()
-	defer cancel()
+	ctx := c.UserContext()
 
-	var user usermodel.User
-	if err := c.BodyParser(&user); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	var request usermodel.UserCreateRequest
+	if err := c.BodyParser(&request); err != nil {
+		return apperror.InvalidArgument("invalid request body")
+	}
+	if err := validation.Struct(request); err != nil {
+		return err
 	}
 
-	createdUser, err := h.UserService.CreateNewUser(ctx, user)
+	createdUser, err := h.UserService.CreateNewUser(ctx, request.ToUser())
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	// To ensure the password hash doesn't get sent back, reset it to an empty string
@@ -116,14 +117,14 @@ func (h *UserHandler) CreateOneUser(c *fiber.Ctx) error {
 }
 
 func (h *UserHandler) DeleteOneUser(c *fiber.Ctx) error {
-	ctx, cancel := h.withTimeout()
-	defer cancel()
+	ctx := c.UserContext()
 
 	userID := c.Params("id") // Retrieve the userID from the URL parameter.
-	err := h.UserService.DeleteSpecificUser(ctx, userID)
+	actorID, _ := c.Locals("userID").(string)
+	err := h.UserService.DeleteSpecificUser(ctx, userID, actorID)
 	if err != nil {
 		// If an error occurred, send an appropriate response.
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	// If no error, send a success response.
@@ -133,27 +134,32 @@ func (h *UserHandler) DeleteOneUser(c *fiber.Ctx) error {
 }
 
 func (h *UserHandler) UpdateOneUser(c *fiber.Ctx) error {
-	// Context with timeout for the operation
-	ctx, cancel := h.withTimeout()
-	defer cancel()
+	ctx := c.UserContext()
 
 	// Extract the user ID from the URL parameter
 	userID := c.Params("id")
 	if userID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "User ID is required"})
+		return apperror.InvalidArgument("user ID is required")
+	}
+
+	var request usermodel.UserUpdateRequest
+	if err := c.BodyParser(&request); err != nil {
+		return apperror.InvalidArgument("invalid request body")
+	}
+	if err := validation.Struct(request); err != nil {
+		return err
 	}
 
-	// Parse the JSON body into a User struct
-	var updateUser usermodel.User
-	if err := c.BodyParser(&updateUser); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Could not parse request body"})
+	// JWTMiddleware has loaded the caller.
+	actor, ok := c.Locals("user").(*usermodel.User)
+	if !ok {
+		return apperror.Unauthenticated("missing user")
 	}
 
-	// Call the UserService to update the user
-	updatedUser, err := h.UserService.UpdateSpecificByID(ctx, userID, updateUser)
+	updatedUser, err := h.UserService.UpdateSpecificByID(ctx, *actor, userID, request)
 	if err != nil {
 		// Handle specific errors like "user not found" or "invalid input" differently if needed
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 	updatedUser.Password = ""
 
@@ -165,11 +171,10 @@ func (h *UserHandler) UpdateOneUser(c *fiber.Ctx) error {
 }
 
 func (h *UserHandler) DropAllUsers(c *fiber.Ctx) error {
-	ctx, cancel := h.withTimeout()
-	defer cancel()
+	ctx := c.UserContext()
 
 	if err := h.UserService.DropAllUsers(ctx); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	return c.SendStatus(fiber.StatusOK)
@@ -186,12 +191,12 @@ func (h *UserHandler) Login(c *fiber.Ctx) error {
 		Password string `json:"password"`
 	}
 	if err := c.BodyParser(&loginRequest); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Bad request"})
+		return apperror.InvalidArgument("invalid request body")
 	}
 
-	user, token, err := h.UserService.Login(c.Context(), loginRequest.Email, loginRequest.Password)
+	user, token, err := h.UserService.Login(c.UserContext(), loginRequest.Email, loginRequest.Password)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid credentials"})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -208,12 +213,12 @@ func (h *UserHandler) GoogleLogin(c *fiber.Ctx) error {
 	}
 
 	if err := c.BodyParser(&loginRequest); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Bad request"})
+		return apperror.InvalidArgument("invalid request body")
 	}
 
-	user, token, err := h.UserService.GoogleLogin(c.Context(), loginRequest.Email, loginRequest.FirebaseID)
+	user, token, err := h.UserService.GoogleLogin(c.UserContext(), loginRequest.Email, loginRequest.FirebaseID)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid Google Account"})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -223,9 +228,129 @@ func (h *UserHandler) GoogleLogin(c *fiber.Ctx) error {
 	})
 }
 
+// ForgotPassword emails a reset link if the address is registered. The
+// response is the same either way.
+func (h *UserHandler) ForgotPassword(c *fiber.Ctx) error {
+	var request usermodel.ForgotPasswordRequest
+	if err := c.BodyParser(&request); err != nil {
+		return apperror.InvalidArgument("invalid request body")
+	}
+	if err := validation.Struct(request); err != nil {
+		return err
+	}
+
+	if err := h.UserService.ForgotPassword(c.UserContext(), request.Email); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message": "If the address is registered, a password reset link has been sent to it",
+	})
+}
+
+func (h *UserHandler) ResetPassword(c *fiber.Ctx) error {
+	var request usermodel.ResetPasswordRequest
+	if err := c.BodyParser(&request); err != nil {
+		return apperror.InvalidArgument("invalid request body")
+	}
+	if err := validation.Struct(request); err != nil {
+		return err
+	}
+
+	if err := h.UserService.ResetPasswordWithToken(c.UserContext(), request.Token, request.Password); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Password reset successfully",
+	})
+}
+
+func (h *UserHandler) VerifyEmail(c *fiber.Ctx) error {
+	var request usermodel.VerifyEmailRequest
+	if err := c.BodyParser(&request); err != nil {
+		return apperror.InvalidArgument("invalid request body")
+	}
+	if err := validation.Struct(request); err != nil {
+		return err
+	}
+
+	if err := h.UserService.VerifyEmail(c.UserContext(), request.Token); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Email address verified",
+	})
+}
+
+// ResendVerification emails a new verification link if the address belongs
+// to an unverified account. The response is the same either way.
+func (h *UserHandler) ResendVerification(c *fiber.Ctx) error {
+	var request usermodel.ResendVerificationRequest
+	if err := c.BodyParser(&request); err != nil {
+		return apperror.InvalidArgument("invalid request body")
+	}
+	if err := validation.Struct(request); err != nil {
+		return err
+	}
+
+	if err := h.UserService.ResendVerification(c.UserContext(), request.Email); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message": "If the address belongs to an unverified account, a verification link has been sent to it",
+	})
+}
+
+func (h *UserHandler) SuspendUser(c *fiber.Ctx) error {
+	var request usermodel.StatusChangeRequest
+	if err := c.BodyParser(&request); err != nil {
+		return apperror.InvalidArgument("invalid request body")
+	}
+	if err := validation.Struct(request); err != nil {
+		return err
+	}
+
+	adminID, _ := c.Locals("userID").(string)
+	user, err := h.UserService.SuspendUser(c.UserContext(), c.Params("id"), adminID, request.Reason)
+	if err != nil {
+		return err
+	}
+	user.Password = ""
+
+	return c.JSON(fiber.Map{
+		"message": "User suspended",
+		"data":    user,
+	})
+}
+
+func (h *UserHandler) ReactivateUser(c *fiber.Ctx) error {
+	var request usermodel.StatusChangeRequest
+	if err := c.BodyParser(&request); err != nil {
+		return apperror.InvalidArgument("invalid request body")
+	}
+	if err := validation.Struct(request); err != nil {
+		return err
+	}
+
+	adminID, _ := c.Locals("userID").(string)
+	user, err := h.UserService.ReactivateUser(c.UserContext(), c.Params("id"), adminID, request.Reason)
+	if err != nil {
+		return err
+	}
+	user.Password = ""
+
+	return c.JSON(fiber.Map{
+		"message": "User reactivated",
+		"data":    user,
+	})
+}
+
 // func (h *UserHandler) UpdateOneUser(c *fiber.Ctx) error {
 // 	// Context with timeout for the operation
-// 	ctx, cancel := h.withTimeout()
+// 	ctx, cancel := h.withTimeout(c)
 // 	defer cancel()
 
 // 	// Extract the user ID from the URL parameter
@@ -0,0 +1,207 @@
+// Package ical writes RFC 5545 iCalendar files with single and weekly
+// recurring events.
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	// Embed the zone database so VTIMEZONE data is available on hosts
+	// without one.
+	_ "time/tzdata"
+)
+
+const (
+	prodID     = "-//Coursyclopedia//Timetable//EN"
+	dateTime   = "20060102T150405"
+	maxLineLen = 75
+)
+
+// Event is a VEVENT. Events with Until set repeat weekly, on the weekday of
+// Start, up to and including Until, and are written in the calendar's time
+// zone so that they keep their wall-clock time across daylight saving
+// changes. Other events are written in UTC.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Start       time.Time
+	End         time.Time
+	Until       time.Time
+}
+
+type Calendar struct {
+	Name     string
+	Location *time.Location
+	// Stamp is written as every event's DTSTAMP.
+	Stamp  time.Time
+	Events []Event
+}
+
+// WriteTo writes c as an iCalendar stream.
+func (c Calendar) WriteTo(w io.Writer) (int64, error) {
+	loc := c.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	zoned := loc != time.UTC
+
+	var b builder
+	b.line("BEGIN:VCALENDAR")
+	b.line("VERSION:2.0")
+	b.line("PRODID:" + prodID)
+	b.line("CALSCALE:GREGORIAN")
+	b.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		b.line("X-WR-CALNAME:" + escape(c.Name))
+	}
+	if zoned {
+		b.line("X-WR-TIMEZONE:" + loc.String())
+		if from, to, ok := recurringRange(c.Events); ok {
+			writeTimezone(&b, loc, from, to)
+		}
+	}
+
+	stamp := utc(c.Stamp)
+	for _, e := range c.Events {
+		b.line("BEGIN:VEVENT")
+		b.line("UID:" + e.UID)
+		b.line("DTSTAMP:" + stamp)
+		if !e.Until.IsZero() && zoned {
+			b.line("DTSTART;TZID=" + loc.String() + ":" + e.Start.In(loc).Format(dateTime))
+			b.line("DTEND;TZID=" + loc.String() + ":" + e.End.In(loc).Format(dateTime))
+		} else {
+			b.line("DTSTART:" + utc(e.Start))
+			b.line("DTEND:" + utc(e.End))
+		}
+		if !e.Until.IsZero() {
+			b.line("RRULE:FREQ=WEEKLY;UNTIL=" + utc(e.Until))
+		}
+		b.line("SUMMARY:" + escape(e.Summary))
+		if e.Description != "" {
+			b.line("DESCRIPTION:" + escape(e.Description))
+		}
+		if e.Location != "" {
+			b.line("LOCATION:" + escape(e.Location))
+		}
+		b.line("END:VEVENT")
+	}
+	b.line("END:VCALENDAR")
+
+	return b.WriteTo(w)
+}
+
+// recurringRange returns the span covered by recurring events.
+func recurringRange(events []Event) (from, to time.Time, ok bool) {
+	for _, e := range events {
+		if e.Until.IsZero() {
+			continue
+		}
+		if !ok || e.Start.Before(from) {
+			from = e.Start
+		}
+		if !ok || e.Until.After(to) {
+			to = e.Until
+		}
+		ok = true
+	}
+	return from, to, ok
+}
+
+// writeTimezone writes a VTIMEZONE for loc with one observance for the
+// offset in effect at from and one for every transition up to to.
+func writeTimezone(b *builder, loc *time.Location, from, to time.Time) {
+	b.line("BEGIN:VTIMEZONE")
+	b.line("TZID:" + loc.String())
+
+	from = from.Add(-24 * time.Hour).In(loc)
+	_, offset := from.Zone()
+	writeObservance(b, from, offset)
+
+	for t := from; t.Before(to); {
+		next := t.Add(24 * time.Hour)
+		if _, o := next.In(loc).Zone(); o != offset {
+			at := transition(loc, t, next)
+			writeObservance(b, at, offset)
+			offset = o
+		}
+		t = next
+	}
+
+	b.line("END:VTIMEZONE")
+}
+
+// transition finds the first second in (lo, hi] with a different offset
+// from lo.
+func transition(loc *time.Location, lo, hi time.Time) time.Time {
+	_, offset := lo.In(loc).Zone()
+	for hi.Sub(lo) > time.Second {
+		mid := lo.Add(hi.Sub(lo) / 2)
+		if _, o := mid.In(loc).Zone(); o == offset {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi.In(loc)
+}
+
+// writeObservance writes the observance that starts at t, whose offset
+// before t was fromOffset.
+func writeObservance(b *builder, t time.Time, fromOffset int) {
+	name, offset := t.Zone()
+	kind := "STANDARD"
+	if t.IsDST() {
+		kind = "DAYLIGHT"
+	}
+	b.line("BEGIN:" + kind)
+	b.line("DTSTART:" + t.UTC().Add(time.Duration(fromOffset)*time.Second).Format(dateTime))
+	b.line("TZOFFSETFROM:" + formatOffset(fromOffset))
+	b.line("TZOFFSETTO:" + formatOffset(offset))
+	b.line("TZNAME:" + escape(name))
+	b.line("END:" + kind)
+}
+
+func formatOffset(seconds int) string {
+	sign := '+'
+	if seconds < 0 {
+		sign, seconds = '-', -seconds
+	}
+	return fmt.Sprintf("%c%02d%02d", sign, seconds/3600, seconds%3600/60)
+}
+
+func utc(t time.Time) string {
+	return t.UTC().Format(dateTime) + "Z"
+}
+
+// escape escapes TEXT values as RFC 5545 section 3.3.11 requires.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// builder writes CRLF-terminated content lines folded at 75 octets.
+type builder struct {
+	bytes.Buffer
+}
+
+func (b *builder) line(s string) {
+	limit := maxLineLen
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		// Continuation lines start with a space, which counts.
+		limit = maxLineLen - 1
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,232 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// unfold joins folded content lines and splits the stream into lines.
+func unfold(s string) []string {
+	s = strings.ReplaceAll(s, "\r\n ", "")
+	return strings.Split(strings.TrimSuffix(s, "\r\n"), "\r\n")
+}
+
+func TestLineFolding(t *testing.T) {
+	tests := []struct {
+		name      string
+		line      string
+		wantLines int
+	}{
+		{name: "short", line: "SUMMARY:CSX3003", wantLines: 1},
+		{name: "exactly 75 octets", line: "SUMMARY:" + strings.Repeat("a", 67), wantLines: 1},
+		{name: "76 octets", line: "SUMMARY:" + strings.Repeat("a", 68), wantLines: 2},
+		{name: "continuations hold 74 octets", line: "SUMMARY:" + strings.Repeat("a", 67+74), wantLines: 2},
+		{name: "long", line: "DESCRIPTION:" + strings.Repeat("0123456789", 30), wantLines: 5},
+		{name: "multi-byte characters are not split", line: "LOCATION:" + strings.Repeat("ห้องเรียน ", 20), wantLines: 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b builder
+			b.line(tt.line)
+			out := b.String()
+
+			if !strings.HasSuffix(out, "\r\n") {
+				t.Fatalf("output %q does not end with CRLF", out)
+			}
+			lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+			if len(lines) != tt.wantLines {
+				t.Errorf("got %d lines, want %d", len(lines), tt.wantLines)
+			}
+			for i, line := range lines {
+				if len(line) > maxLineLen {
+					t.Errorf("line %d is %d octets long", i, len(line))
+				}
+				if i > 0 && !strings.HasPrefix(line, " ") {
+					t.Errorf("continuation line %d does not start with a space", i)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d splits a UTF-8 sequence", i)
+				}
+			}
+			if got := unfold(out); len(got) != 1 || got[0] != tt.line {
+				t.Errorf("unfolded = %q, want %q", got, tt.line)
+			}
+		})
+	}
+}
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{in: "CSX3003 Data Structures", want: "CSX3003 Data Structures"},
+		{in: "VMS0301, Hua Mak", want: `VMS0301\, Hua Mak`},
+		{in: "lecture; lab", want: `lecture\; lab`},
+		{in: `C:\path`, want: `C:\\path`},
+		{in: "line one\nline two", want: `line one\nline two`},
+		{in: "line one\r\nline two", want: `line one\nline two`},
+	}
+
+	for _, tt := range tests {
+		if got := escape(tt.in); got != tt.want {
+			t.Errorf("escape(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFormatOffset(t *testing.T) {
+	tests := []struct {
+		seconds int
+		want    string
+	}{
+		{seconds: 0, want: "+0000"},
+		{seconds: 7 * 3600, want: "+0700"},
+		{seconds: 5*3600 + 30*60, want: "+0530"},
+		{seconds: -4 * 3600, want: "-0400"},
+		{seconds: -(3*3600 + 30*60), want: "-0330"},
+	}
+
+	for _, tt := range tests {
+		if got := formatOffset(tt.seconds); got != tt.want {
+			t.Errorf("formatOffset(%d) = %q, want %q", tt.seconds, got, tt.want)
+		}
+	}
+}
+
+func TestWriteTo(t *testing.T) {
+	bangkok, err := time.LoadLocation("Asia/Bangkok")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stamp := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		calendar Calendar
+		// want lists lines that must appear in this order.
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "single event in UTC",
+			calendar: Calendar{
+				Name:  "Exams, 2026",
+				Stamp: stamp,
+				Events: []Event{{
+					UID:     "exam-1@coursyclopedia",
+					Summary: "CSX3003 final",
+					Start:   time.Date(2026, 12, 10, 9, 0, 0, 0, bangkok),
+					End:     time.Date(2026, 12, 10, 12, 0, 0, 0, bangkok),
+				}},
+			},
+			want: []string{
+				"BEGIN:VCALENDAR", "VERSION:2.0", `X-WR-CALNAME:Exams\, 2026`,
+				"BEGIN:VEVENT", "UID:exam-1@coursyclopedia", "DTSTAMP:20261001T000000Z",
+				"DTSTART:20261210T020000Z", "DTEND:20261210T050000Z", "SUMMARY:CSX3003 final",
+				"END:VEVENT", "END:VCALENDAR",
+			},
+			notWant: []string{"BEGIN:VTIMEZONE", "RRULE"},
+		},
+		{
+			name: "weekly event in a zone without daylight saving",
+			calendar: Calendar{
+				Location: bangkok,
+				Stamp:    stamp,
+				Events: []Event{{
+					UID:      "class-1@coursyclopedia",
+					Summary:  "CSX3003",
+					Location: "VMS0301",
+					Start:    time.Date(2026, 8, 3, 9, 0, 0, 0, bangkok),
+					End:      time.Date(2026, 8, 3, 10, 30, 0, 0, bangkok),
+					Until:    time.Date(2026, 11, 30, 23, 59, 59, 0, bangkok),
+				}},
+			},
+			want: []string{
+				"X-WR-TIMEZONE:Asia/Bangkok",
+				"BEGIN:VTIMEZONE", "TZID:Asia/Bangkok",
+				"BEGIN:STANDARD", "DTSTART:20260802T090000", "TZOFFSETFROM:+0700", "TZOFFSETTO:+0700", "END:STANDARD",
+				"END:VTIMEZONE",
+				"DTSTART;TZID=Asia/Bangkok:20260803T090000", "DTEND;TZID=Asia/Bangkok:20260803T103000",
+				"RRULE:FREQ=WEEKLY;UNTIL=20261130T165959Z", "LOCATION:VMS0301",
+			},
+			notWant: []string{"BEGIN:DAYLIGHT"},
+		},
+		{
+			name: "weekly event across a daylight saving change",
+			calendar: Calendar{
+				Location: newYork,
+				Stamp:    stamp,
+				Events: []Event{{
+					UID:     "class-2@coursyclopedia",
+					Summary: "CSX3003",
+					Start:   time.Date(2026, 2, 2, 9, 0, 0, 0, newYork),
+					End:     time.Date(2026, 2, 2, 10, 30, 0, 0, newYork),
+					Until:   time.Date(2026, 4, 27, 23, 59, 59, 0, newYork),
+				}},
+			},
+			want: []string{
+				"BEGIN:VTIMEZONE", "TZID:America/New_York",
+				"BEGIN:STANDARD", "TZOFFSETFROM:-0500", "TZOFFSETTO:-0500", "TZNAME:EST", "END:STANDARD",
+				"BEGIN:DAYLIGHT", "DTSTART:20260308T020000", "TZOFFSETFROM:-0500", "TZOFFSETTO:-0400", "TZNAME:EDT", "END:DAYLIGHT",
+				"END:VTIMEZONE",
+				"DTSTART;TZID=America/New_York:20260202T090000",
+				"RRULE:FREQ=WEEKLY;UNTIL=20260428T035959Z",
+			},
+		},
+		{
+			name: "weekly event without a calendar zone",
+			calendar: Calendar{
+				Stamp: stamp,
+				Events: []Event{{
+					UID:     "class-3@coursyclopedia",
+					Summary: "CSX3003",
+					Start:   time.Date(2026, 8, 3, 9, 0, 0, 0, bangkok),
+					End:     time.Date(2026, 8, 3, 10, 30, 0, 0, bangkok),
+					Until:   time.Date(2026, 11, 30, 23, 59, 59, 0, bangkok),
+				}},
+			},
+			want:    []string{"DTSTART:20260803T020000Z", "RRULE:FREQ=WEEKLY;UNTIL=20261130T165959Z"},
+			notWant: []string{"BEGIN:VTIMEZONE", "TZID="},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			n, err := tt.calendar.WriteTo(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != int64(buf.Len()) {
+				t.Errorf("WriteTo reported %d bytes, wrote %d", n, buf.Len())
+			}
+
+			lines := unfold(buf.String())
+			next := 0
+			for _, want := range tt.want {
+				for next < len(lines) && lines[next] != want {
+					next++
+				}
+				if next == len(lines) {
+					t.Fatalf("missing %q in order; calendar:\n%s", want, buf.String())
+				}
+				next++
+			}
+			for _, line := range lines {
+				for _, unwanted := range tt.notWant {
+					if strings.Contains(line, unwanted) {
+						t.Errorf("unexpected line %q", line)
+					}
+				}
+			}
+		})
+	}
+}
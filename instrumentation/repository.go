@@ -0,0 +1,45 @@
+package instrumentation
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/logger"
+	"BackendCoursyclopedia/metrics"
+	"BackendCoursyclopedia/tracing"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// StartRepositoryOp opens a span and records latency and errors for one
+// repository call. The returned context carries the span so MongoDB command
+// spans nest under it; the returned function must be called with the call's
+// error once it completes.
+func StartRepositoryOp(ctx context.Context, repository, method string) (context.Context, func(error)) {
+	start := time.Now()
+	ctx, span := tracing.Tracer().Start(ctx, repository+"."+method,
+		trace.WithAttributes(
+			attribute.String("repository", repository),
+			attribute.String("repository.method", method),
+		),
+	)
+
+	return ctx, func(err error) {
+		defer span.End()
+
+		metrics.MongoOperationDuration.WithLabelValues(repository, method).Observe(time.Since(start).Seconds())
+		// Domain errors such as NotFound or InvalidArgument are expected
+		// outcomes, not failed database operations.
+		if _, isDomainErr := apperror.As(err); err != nil && !isDomainErr && !errors.Is(err, mongo.ErrNoDocuments) {
+			metrics.MongoOperationErrors.WithLabelValues(repository, method).Inc()
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			logger.FromContext(ctx).Error("repository operation failed",
+				"repository", repository, "method", method, "error", err)
+		}
+	}
+}
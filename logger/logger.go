@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+type ctxKey struct{}
+
+var base = slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+// Init configures the process-wide JSON logger on stdout. LOG_LEVEL accepts
+// debug, info, warn or error and defaults to info.
+func Init() {
+	InitWithWriter(os.Stdout)
+}
+
+// InitWithWriter is Init with logs written to w, for tools that keep stdout
+// for their own output.
+func InitWithWriter(w io.Writer) {
+	level := slog.LevelInfo
+	switch strings.ToLower(os.Getenv("LOG_LEVEL")) {
+	case "debug":
+		level = slog.LevelDebug
+	case "warn":
+		level = slog.LevelWarn
+	case "error":
+		level = slog.LevelError
+	}
+
+	base = slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}))
+	slog.SetDefault(base)
+}
+
+// L returns the process-wide logger for code that runs outside a request.
+func L() *slog.Logger {
+	return base
+}
+
+// WithContext returns a copy of ctx carrying l.
+func WithContext(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext returns the request-scoped logger stored in ctx, falling back
+// to the process-wide logger.
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+			return l
+		}
+	}
+	return base
+}
+
+// Fatal logs msg at error level and exits the process.
+func Fatal(msg string, args ...any) {
+	base.Error(msg, args...)
+	os.Exit(1)
+}
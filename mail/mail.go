@@ -0,0 +1,209 @@
+// Package mail builds MIME messages and hands them to a transport: SMTP in
+// production, or a directory of .eml files or the log for local and test
+// runs.
+package mail
+
+import (
+	"BackendCoursyclopedia/logger"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Message is an email with a plain-text and an HTML body. Either body may
+// be empty.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+	// Sensitive marks bodies that hold a secret, such as a one-time link.
+	// LogTransport leaves them out.
+	Sensitive bool
+}
+
+// Transport delivers a message. An error means the message was not
+// accepted and may be retried.
+type Transport interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// FromEnv picks the transport named by MAIL_TRANSPORT: smtp, file or log
+// (the default).
+func FromEnv() (Transport, error) {
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = "Coursyclopedia <no-reply@localhost>"
+	}
+	if _, err := mail.ParseAddress(from); err != nil {
+		return nil, fmt.Errorf("invalid MAIL_FROM %q: %w", from, err)
+	}
+
+	switch transport := os.Getenv("MAIL_TRANSPORT"); transport {
+	case "", "log":
+		return &LogTransport{From: from}, nil
+	case "file":
+		dir := os.Getenv("MAIL_DIR")
+		if dir == "" {
+			dir = "mail"
+		}
+		return &FileTransport{Dir: dir, From: from}, nil
+	case "smtp":
+		host := os.Getenv("SMTP_HOST")
+		if host == "" {
+			return nil, fmt.Errorf("MAIL_TRANSPORT=smtp needs SMTP_HOST")
+		}
+		port := 587
+		if value := os.Getenv("SMTP_PORT"); value != "" {
+			p, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid SMTP_PORT %q", value)
+			}
+			port = p
+		}
+		return &SMTPTransport{
+			Host:     host,
+			Port:     port,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     from,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown MAIL_TRANSPORT %q; use smtp, file or log", transport)
+	}
+}
+
+// Bytes renders msg as an RFC 5322 message from from, with the bodies as
+// quoted-printable parts of a multipart/alternative.
+func (msg Message) Bytes(from string, date time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	// Line breaks in a value would let it inject further headers.
+	header := func(key, value string) {
+		value = strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+		fmt.Fprintf(&buf, "%s: %s\r\n", key, value)
+	}
+
+	header("From", from)
+	header("To", msg.To)
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", date.Format(time.RFC1123Z))
+	header("Message-ID", messageID(from))
+	header("MIME-Version", "1.0")
+
+	parts := [][2]string{}
+	if msg.Text != "" {
+		parts = append(parts, [2]string{"text/plain; charset=utf-8", msg.Text})
+	}
+	if msg.HTML != "" {
+		parts = append(parts, [2]string{"text/html; charset=utf-8", msg.HTML})
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("mail: message to %s has no body", msg.To)
+	}
+
+	if len(parts) == 1 {
+		header("Content-Type", parts[0][0])
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := writeQuotedPrintable(&buf, parts[0][1]); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for _, part := range parts {
+		pw, err := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part[0]},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(pw, part[1]); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	header("Content-Type", "multipart/alternative; boundary="+w.Boundary())
+	buf.WriteString("\r\n")
+	buf.Write(body.Bytes())
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintable(w io.Writer, s string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(s, "\n", "\r\n"))); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+func messageID(from string) string {
+	domain := "localhost"
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if at := strings.LastIndex(addr.Address, "@"); at >= 0 {
+			domain = addr.Address[at+1:]
+		}
+	}
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">"
+}
+
+// LogTransport writes each message's recipient, subject and text body to
+// the log instead of sending it. Sensitive bodies are left out, since logs
+// are kept and read more widely than mailboxes.
+type LogTransport struct {
+	From string
+}
+
+func (t *LogTransport) Send(ctx context.Context, msg Message) error {
+	text := msg.Text
+	if msg.Sensitive {
+		text = "[omitted: sensitive]"
+	}
+	logger.FromContext(ctx).Info("email not sent; MAIL_TRANSPORT is log",
+		"from", t.From, "to", msg.To, "subject", msg.Subject, "text", text)
+	return nil
+}
+
+// FileTransport writes each message as an .eml file in Dir, which most
+// mail clients can open.
+type FileTransport struct {
+	Dir  string
+	From string
+}
+
+func (t *FileTransport) Send(ctx context.Context, msg Message) error {
+	now := time.Now()
+	data, err := msg.Bytes(t.From, now)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(t.Dir, 0o755); err != nil {
+		return err
+	}
+
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	name := fmt.Sprintf("%s-%s.eml", now.UTC().Format("20060102T150405.000"), hex.EncodeToString(suffix))
+	return os.WriteFile(filepath.Join(t.Dir, name), data, 0o644)
+}
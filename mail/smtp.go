@@ -0,0 +1,90 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// SMTPTransport submits messages to an SMTP server, upgrading to TLS with
+// STARTTLS when the server offers it, or connecting with implicit TLS on
+// port 465. Credentials are only sent over TLS.
+type SMTPTransport struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+func (t *SMTPTransport) Send(ctx context.Context, msg Message) error {
+	from, err := mail.ParseAddress(t.From)
+	if err != nil {
+		return err
+	}
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient %q: %w", msg.To, err)
+	}
+	data, err := msg.Bytes(t.From, time.Now())
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(t.Host, strconv.Itoa(t.Port))
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	tlsConfig := &tls.Config{ServerName: t.Host}
+	if t.Port == 465 {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, t.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && t.Port != 465 {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if t.Username != "" {
+		// smtp.PlainAuth refuses to send credentials over an unencrypted
+		// connection to a remote host.
+		if err := client.Auth(smtp.PlainAuth("", t.Username, t.Password, t.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -1,9 +1,17 @@
 package main
 
 import (
+	"BackendCoursyclopedia/db"
+	"BackendCoursyclopedia/logger"
+	"BackendCoursyclopedia/middleware"
+	"BackendCoursyclopedia/migrations"
 	"BackendCoursyclopedia/route"
-	"log"
+	"BackendCoursyclopedia/tracing"
+	"context"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/joho/godotenv"
@@ -18,11 +26,32 @@ func main() {
 
 	if _, exists := os.LookupEnv("RAILWAY_ENVIRONMENT"); exists == false {
 		if err := godotenv.Load(); err != nil {
-			log.Fatal("error loading .env file:", err)
+			logger.Fatal("error loading .env file", "error", err)
 
 		}
 	}
-	app := fiber.New()
+	logger.Init()
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		db.ConnectDB()
+		defer db.DisconnectDB()
+		if err := migrations.RunCLI(context.Background(), db.GetDB(), os.Args[2:], os.Stdout); err != nil {
+			logger.Fatal("migrate failed", "error", err)
+		}
+		return
+	}
+
+	shutdownTracing, err := tracing.Init(context.Background())
+	if err != nil {
+		logger.Fatal("failed to initialise tracing", "error", err)
+	}
+
+	app := fiber.New(fiber.Config{
+		ErrorHandler: middleware.ErrorHandler,
+		// Behind a reverse proxy, PROXY_HEADER (e.g. X-Forwarded-For) names
+		// the header carrying the client IP that rate limits are keyed on.
+		ProxyHeader: os.Getenv("PROXY_HEADER"),
+	})
 
 	route.Setup(app)
 
@@ -31,6 +60,28 @@ func main() {
 		port = "3000"
 	}
 
+	// Listen returns as soon as shutdown starts; shutdownDone closes once
+	// the shutdown hooks, which stop background work, have returned.
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+		<-quit
+		if err := app.ShutdownWithTimeout(10 * time.Second); err != nil {
+			logger.L().Error("server shutdown failed", "error", err)
+		}
+	}()
+
 	// log.Fatal(app.Listen("0.0.0.0" + port))
-	log.Fatal(app.Listen(":" + port))
+	if err := app.Listen(":" + port); err != nil {
+		logger.Fatal("server stopped", "error", err)
+	}
+	<-shutdownDone
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(ctx); err != nil {
+		logger.L().Error("failed to flush traces", "error", err)
+	}
 }
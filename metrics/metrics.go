@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Collectors are registered on the default registry, which already exports
+// the Go runtime and process collectors.
+var (
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests processed, by method, route pattern and status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency, by method, route pattern and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	MongoOperationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mongo_operation_duration_seconds",
+		Help:    "Latency of repository operations against MongoDB.",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"repository", "method"})
+
+	MongoOperationErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mongo_operation_errors_total",
+		Help: "Repository operations against MongoDB that returned an error.",
+	}, []string{"repository", "method"})
+
+	Logins = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "coursyclopedia_logins_total",
+		Help: "Login attempts, by method (password or google) and result.",
+	}, []string{"method", "result"})
+
+	SubjectsCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "coursyclopedia_subjects_created_total",
+		Help: "Subjects created.",
+	})
+
+	LikesAdded = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "coursyclopedia_likes_added_total",
+		Help: "Likes added to subjects.",
+	})
+
+	JobRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "coursyclopedia_job_runs_total",
+		Help: "Background job runs finished, by job and result (succeeded, retry, dead or interrupted).",
+	}, []string{"job", "result"})
+
+	JobRunDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "coursyclopedia_job_run_duration_seconds",
+		Help:    "Time spent running background jobs, by job.",
+		Buckets: []float64{.01, .05, .1, .5, 1, 5, 10, 30, 60, 300, 600},
+	}, []string{"job"})
+)
+
+const (
+	LoginMethodPassword = "password"
+	LoginMethodGoogle   = "google"
+)
+
+func ObserveLogin(method string, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	Logins.WithLabelValues(method, result).Inc()
+}
+
+// Handler serves the default registry in the Prometheus text format.
+func Handler() fiber.Handler {
+	return adaptor.HTTPHandler(promhttp.Handler())
+}
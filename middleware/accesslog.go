@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"BackendCoursyclopedia/logger"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AccessLog writes one structured line per request once the response status
+// is known. It must run after RequestID so the line carries the request ID.
+func AccessLog(c *fiber.Ctx) error {
+	start := time.Now()
+
+	err := c.Next()
+	if err != nil {
+		if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+			_ = c.SendStatus(fiber.StatusInternalServerError)
+		}
+	}
+
+	status := c.Response().StatusCode()
+	level := slog.LevelInfo
+	if status >= fiber.StatusInternalServerError {
+		level = slog.LevelError
+	}
+
+	attrs := []any{
+		"method", c.Method(),
+		"route", c.Route().Path,
+		"path", redactedPath(c),
+		"status", status,
+		"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if userID, ok := c.Locals("userID").(string); ok {
+		attrs = append(attrs, "user_id", userID)
+	}
+	if err != nil {
+		attrs = append(attrs, "error", err.Error())
+	}
+
+	logger.FromContext(c.UserContext()).Log(c.UserContext(), level, "request", attrs...)
+
+	return nil
+}
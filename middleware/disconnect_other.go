@@ -0,0 +1,11 @@
+//go:build !unix
+
+package middleware
+
+import "net"
+
+// watchDisconnect is a no-op where the socket cannot be peeked; requests
+// then run until their deadline after a client disconnects.
+func watchDisconnect(net.Conn, func()) (stop func()) {
+	return func() {}
+}
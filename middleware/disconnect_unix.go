@@ -0,0 +1,69 @@
+//go:build unix
+
+package middleware
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"syscall"
+	"time"
+)
+
+const disconnectPollInterval = 250 * time.Millisecond
+
+// watchDisconnect calls cancel once the client closes conn. fasthttp does not
+// read from a connection while its handler runs, so the socket is peeked
+// every disconnectPollInterval: a zero-length read or a reset means the peer
+// is gone. Bytes of a pipelined request are left in place. The returned stop
+// ends the watch and waits for it to exit.
+func watchDisconnect(conn net.Conn, cancel func()) (stop func()) {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return func() {}
+	}
+	raw, err := sc.SyscallConn()
+	if err != nil {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		ticker := time.NewTicker(disconnectPollInterval)
+		defer ticker.Stop()
+
+		buf := make([]byte, 1)
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			if peerClosed(raw, buf) {
+				cancel()
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-exited
+	}
+}
+
+func peerClosed(raw syscall.RawConn, buf []byte) bool {
+	closed := false
+	err := raw.Read(func(fd uintptr) bool {
+		n, _, err := syscall.Recvfrom(int(fd), buf, syscall.MSG_PEEK)
+		closed = (n == 0 && err == nil) || errors.Is(err, syscall.ECONNRESET)
+		// Returning true stops the runtime from parking on EAGAIN.
+		return true
+	})
+	return closed || err != nil
+}
@@ -0,0 +1,85 @@
+//go:build unix
+
+package middleware
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func tcpPair(t *testing.T) (server, client net.Conn) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, _ := ln.Accept()
+		accepted <- conn
+	}()
+	client, err = net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	server = <-accepted
+	if server == nil {
+		t.Fatal("accept failed")
+	}
+	t.Cleanup(func() {
+		server.Close()
+		client.Close()
+	})
+	return server, client
+}
+
+func TestWatchDisconnect(t *testing.T) {
+	tests := []struct {
+		name       string
+		act        func(client net.Conn)
+		wantCancel bool
+	}{
+		{name: "client closes", act: func(client net.Conn) { client.Close() }, wantCancel: true},
+		{name: "client stays", act: func(net.Conn) {}},
+		{name: "client pipelines a request", act: func(client net.Conn) { client.Write([]byte("GET / HTTP/1.1\r\n")) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, client := tcpPair(t)
+			cancelled := make(chan struct{})
+			stop := watchDisconnect(server, func() { close(cancelled) })
+			defer stop()
+
+			tt.act(client)
+
+			select {
+			case <-cancelled:
+				if !tt.wantCancel {
+					t.Fatal("cancelled while the client was connected")
+				}
+			case <-time.After(4 * disconnectPollInterval):
+				if tt.wantCancel {
+					t.Fatal("not cancelled after the client closed")
+				}
+			}
+		})
+	}
+}
+
+func TestWatchDisconnectLeavesPipelinedBytes(t *testing.T) {
+	server, client := tcpPair(t)
+	stop := watchDisconnect(server, func() {})
+	client.Write([]byte("x"))
+	time.Sleep(2 * disconnectPollInterval)
+	stop()
+
+	buf := make([]byte, 1)
+	server.SetReadDeadline(time.Now().Add(time.Second))
+	if n, err := server.Read(buf); n != 1 || buf[0] != 'x' {
+		t.Fatalf("Read() = %d, %v; want the peeked byte", n, err)
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/logger"
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+const problemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details body.
+type Problem struct {
+	Type      string                `json:"type"`
+	Title     string                `json:"title"`
+	Status    int                   `json:"status"`
+	Detail    string                `json:"detail,omitempty"`
+	Instance  string                `json:"instance,omitempty"`
+	Field     string                `json:"field,omitempty"`
+	Errors    []apperror.FieldError `json:"errors,omitempty"`
+	RequestID string                `json:"requestId,omitempty"`
+}
+
+// ErrorHandler renders every error returned by a handler as problem+json.
+// Domain errors keep their message; anything else is logged and reported as
+// a generic 500 so driver internals never reach the client.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	problem := Problem{
+		Type:     "about:blank",
+		Status:   fiber.StatusInternalServerError,
+		Instance: c.OriginalURL(),
+	}
+
+	var fiberErr *fiber.Error
+	if appErr, ok := apperror.As(err); ok {
+		problem.Type = "/problems/" + string(appErr.Kind)
+		problem.Status = appErr.Status()
+		problem.Detail = appErr.Message
+		problem.Field = appErr.Field
+		problem.Errors = appErr.Fields
+	} else if errors.As(err, &fiberErr) {
+		problem.Status = fiberErr.Code
+		problem.Detail = fiberErr.Message
+	} else if errors.Is(err, context.DeadlineExceeded) {
+		problem.Status = fiber.StatusGatewayTimeout
+		problem.Detail = "Request timed out"
+	} else {
+		logger.FromContext(c.UserContext()).Error("unhandled error", "error", err)
+	}
+
+	problem.Title = utils.StatusMessage(problem.Status)
+	if requestID, ok := c.Locals("requestID").(string); ok {
+		problem.RequestID = requestID
+	}
+
+	return c.Status(problem.Status).JSON(problem, problemContentType)
+}
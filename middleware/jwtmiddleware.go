@@ -1,6 +1,9 @@
 package middleware
 
 import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/model/usermodel"
+	userrepo "BackendCoursyclopedia/repository/userrepository"
 	"os"
 	"strings"
 
@@ -8,41 +11,64 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 )
 
-func JWTMiddleware(c *fiber.Ctx) error {
-	tokenString := c.Get("Authorization")
-	tokenString = strings.TrimPrefix(tokenString, "Bearer ")
+// JWTMiddleware authenticates the bearer token and loads its user into
+// Locals("userID") and Locals("user"). The user is read on every request,
+// so a suspension takes effect immediately: tokens of users who are not
+// active, or issued before their tokens were revoked, are refused.
+func JWTMiddleware(userRepository userrepo.IUserRepository) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		tokenString := c.Get("Authorization")
+		tokenString = strings.TrimPrefix(tokenString, "Bearer ")
 
-	if tokenString == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Missing or malformed JWT"})
-	}
-
-	// Parse and validate the token
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fiber.NewError(fiber.StatusUnauthorized, "Unexpected signing method")
+		if tokenString == "" {
+			return apperror.Unauthenticated("missing or malformed JWT")
 		}
-		return []byte(os.Getenv("JWTSECRET")), nil
-	})
 
-	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid token"})
-	}
+		// Parse and validate the token
+		var claims usermodel.TokenClaims
+		token, err := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fiber.NewError(fiber.StatusUnauthorized, "Unexpected signing method")
+			}
+			return []byte(os.Getenv("JWTSECRET")), nil
+		})
+		if err != nil || !token.Valid {
+			return apperror.Unauthenticated("invalid token")
+		}
+		if claims.Subject == "" {
+			return apperror.Unauthenticated("invalid token claims")
+		}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		// Here you can use claims to extract additional information
-		// Example: Check if the token subject (sub) matches an expected value
-		subject, ok := claims["sub"].(string)
-		if !ok {
-			// Handle missing or invalid subject claim
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid token claims"})
+		user, err := userRepository.FindUserByID(c.UserContext(), claims.Subject)
+		if apperror.Is(err, apperror.KindNotFound) || apperror.Is(err, apperror.KindInvalidArgument) {
+			return apperror.Unauthenticated("user no longer exists")
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkTokenUser(user, claims); err != nil {
+			return err
 		}
 
-		// Optionally, use the subject (e.g., user ID) for further processing
-		// For example: Set it in Fiber's Locals for use in subsequent handler functions
-		c.Locals("userID", subject)
-	} else {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid token"})
+		c.Locals("userID", claims.Subject)
+		c.Locals("user", user)
+		return c.Next()
 	}
+}
 
-	return c.Next()
+func checkTokenUser(user *usermodel.User, claims usermodel.TokenClaims) error {
+	switch {
+	case user.IsActive():
+	case user.Status == usermodel.StatusSuspended:
+		return apperror.Unauthenticated("account suspended")
+	case user.Status == usermodel.StatusDeleted:
+		return apperror.Unauthenticated("user no longer exists")
+	default:
+		return apperror.Unauthenticated("account is not active")
+	}
+
+	if claims.TokenVersion != user.TokenVersion {
+		return apperror.Unauthenticated("token has been revoked")
+	}
+	return nil
 }
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/model/usermodel"
+	"testing"
+)
+
+func TestCheckTokenUser(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    usermodel.User
+		version int
+		wantErr bool
+	}{
+		{name: "active", user: usermodel.User{Status: usermodel.StatusActive}},
+		{name: "stored without a status", user: usermodel.User{}},
+		{name: "current version", user: usermodel.User{Status: usermodel.StatusActive, TokenVersion: 2}, version: 2},
+		{name: "revoked", user: usermodel.User{Status: usermodel.StatusActive, TokenVersion: 2}, version: 1, wantErr: true},
+		{name: "issued without a version, then revoked", user: usermodel.User{Status: usermodel.StatusActive, TokenVersion: 1}, wantErr: true},
+		{name: "suspended", user: usermodel.User{Status: usermodel.StatusSuspended}, wantErr: true},
+		{name: "deleted", user: usermodel.User{Status: usermodel.StatusDeleted}, wantErr: true},
+		{name: "pending", user: usermodel.User{Status: usermodel.StatusPending}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkTokenUser(&tt.user, usermodel.TokenClaims{TokenVersion: tt.version})
+			if !tt.wantErr && err != nil {
+				t.Fatalf("checkTokenUser() error = %v, want nil", err)
+			}
+			if tt.wantErr && !apperror.Is(err, apperror.KindUnauthenticated) {
+				t.Fatalf("checkTokenUser() error = %v, want unauthenticated", err)
+			}
+		})
+	}
+}
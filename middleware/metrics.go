@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"BackendCoursyclopedia/metrics"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Metrics records request counts and latency per route pattern. It must be
+// registered before AccessLog so errors have already been rendered.
+func Metrics(c *fiber.Ctx) error {
+	start := time.Now()
+
+	err := c.Next()
+
+	status := c.Response().StatusCode()
+	route := c.Route().Path
+	if status == fiber.StatusNotFound && route == "/" {
+		route = "unmatched"
+	}
+
+	labels := []string{c.Method(), route, strconv.Itoa(status)}
+	metrics.HTTPRequests.WithLabelValues(labels...).Inc()
+	metrics.HTTPRequestDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+
+	return err
+}
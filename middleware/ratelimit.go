@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/logger"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+)
+
+// RateLimit allows each client IP max requests per window to the routes it
+// guards, and answers the rest with 429. The limit can be overridden with
+// RATE_LIMIT_<KEY> in the form "<max>/<window>", such as "5/15m". Counters
+// are kept per instance.
+func RateLimit(key string, max int, window time.Duration) fiber.Handler {
+	name := "RATE_LIMIT_" + strings.ToUpper(key)
+	if value := os.Getenv(name); value != "" {
+		if m, w, err := parseRateLimit(value); err == nil {
+			max, window = m, w
+		} else {
+			logger.L().Warn("ignoring invalid rate limit", "variable", name, "value", value)
+		}
+	}
+
+	return limiter.New(limiter.Config{
+		Max:        max,
+		Expiration: window,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return key + ":" + c.IP()
+		},
+		LimitReached: func(c *fiber.Ctx) error {
+			logger.FromContext(c.UserContext()).Warn("rate limit reached", "limit", key, "ip", c.IP())
+			return apperror.TooManyRequests("too many requests; try again later")
+		},
+	})
+}
+
+func parseRateLimit(value string) (int, time.Duration, error) {
+	count, duration, ok := strings.Cut(value, "/")
+	if !ok {
+		return 0, 0, fmt.Errorf("missing window")
+	}
+	max, err := strconv.Atoi(count)
+	if err != nil || max <= 0 {
+		return 0, 0, fmt.Errorf("invalid count %q", count)
+	}
+	window, err := time.ParseDuration(duration)
+	if err != nil || window <= 0 {
+		return 0, 0, fmt.Errorf("invalid window %q", duration)
+	}
+	return max, window, nil
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// secretParams are route parameters that carry credentials, such as the
+// calendar subscription token.
+var secretParams = []string{"token"}
+
+// redactedPath returns the request path with the values of secret route
+// parameters replaced by REDACTED. It must be called after c.Next, once the
+// route has been matched.
+func redactedPath(c *fiber.Ctx) string {
+	path := c.Path()
+	for _, name := range c.Route().Params {
+		if !slices.Contains(secretParams, name) {
+			continue
+		}
+		value := c.Params(name)
+		if i := strings.LastIndex(path, value); value != "" && i >= 0 {
+			path = path[:i] + "REDACTED" + path[i+len(value):]
+		}
+	}
+	return path
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestRedactedPath(t *testing.T) {
+	var got string
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		err := c.Next()
+		got = redactedPath(c)
+		return err
+	})
+	ok := func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) }
+	app.Get("/api/calendar/:token.ics", ok)
+	app.Get("/api/subjects/:id", ok)
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{name: "calendar token", path: "/api/calendar/c2VjcmV0.ics", want: "/api/calendar/REDACTED.ics"},
+		{name: "token that repeats part of the path", path: "/api/calendar/api.ics", want: "/api/calendar/REDACTED.ics"},
+		{name: "other parameters are kept", path: "/api/subjects/65f1c0ffee", want: "/api/subjects/65f1c0ffee"},
+		{name: "unmatched route", path: "/api/calendar/c2VjcmV0", want: "/api/calendar/c2VjcmV0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = ""
+			if _, err := app.Test(httptest.NewRequest("GET", tt.path, nil)); err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("redactedPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"BackendCoursyclopedia/logger"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID honours an incoming X-Request-ID or generates one, echoes it on
+// the response and stores a logger tagged with it in the request context.
+func RequestID(c *fiber.Ctx) error {
+	requestID := c.Get(RequestIDHeader)
+	if requestID == "" || len(requestID) > 128 {
+		requestID = uuid.NewString()
+	}
+
+	c.Set(RequestIDHeader, requestID)
+	c.Locals("requestID", requestID)
+
+	l := logger.L().With("request_id", requestID)
+	c.SetUserContext(logger.WithContext(c.UserContext(), l))
+
+	return c.Next()
+}
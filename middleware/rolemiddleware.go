@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/model/usermodel"
+	userrepo "BackendCoursyclopedia/repository/userrepository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireRole allows the request only if the authenticated user has one of
+// the given role slugs. It must run after JWTMiddleware.
+func RequireRole(userRepository userrepo.IUserRepository, slugs ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("userID").(string)
+		if !ok || userID == "" {
+			return apperror.Unauthenticated("missing user")
+		}
+
+		// JWTMiddleware has usually loaded the user already.
+		user, ok := c.Locals("user").(*usermodel.User)
+		if !ok {
+			var err error
+			user, err = userRepository.FindUserByID(c.UserContext(), userID)
+			if apperror.Is(err, apperror.KindNotFound) {
+				return apperror.Unauthenticated("user no longer exists")
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, slug := range slugs {
+			if user.Role.Slug == slug {
+				return c.Next()
+			}
+		}
+		return apperror.Forbidden("this action requires the %s role", slugs[0])
+	}
+}
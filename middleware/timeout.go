@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"BackendCoursyclopedia/logger"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const defaultRequestTimeout = 30 * time.Second
+
+// Timeout bounds the request context handed to handlers with the deadline
+// configured for key. The deadline is read from TIMEOUT_<KEY> (a Go duration
+// such as "5s"), then REQUEST_TIMEOUT, then defaults to 30s. Handlers whose
+// context hit the deadline are answered with 504.
+//
+// The context is cancelled when the client disconnects, so abandoned requests
+// stop their database work, and when the server shuts down.
+func Timeout(key string) fiber.Handler {
+	timeout := timeoutFor(key)
+
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(c.UserContext(), timeout)
+		defer cancel()
+
+		stopShutdown := context.AfterFunc(c.Context(), cancel)
+		defer stopShutdown()
+
+		stopWatch := watchDisconnect(c.Context().Conn(), cancel)
+		defer stopWatch()
+
+		c.SetUserContext(ctx)
+		err := c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			logger.FromContext(ctx).Warn("request deadline exceeded", "timeout", timeout.String())
+			return fiber.NewError(fiber.StatusGatewayTimeout, "Request timed out")
+		}
+
+		return err
+	}
+}
+
+func timeoutFor(key string) time.Duration {
+	for _, name := range []string{"TIMEOUT_" + strings.ToUpper(key), "REQUEST_TIMEOUT"} {
+		if value := os.Getenv(name); value != "" {
+			if d, err := time.ParseDuration(value); err == nil && d > 0 {
+				return d
+			}
+			logger.L().Warn("ignoring invalid timeout", "variable", name, "value", value)
+		}
+	}
+	return defaultRequestTimeout
+}
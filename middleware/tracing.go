@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"BackendCoursyclopedia/logger"
+	"BackendCoursyclopedia/tracing"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type requestHeaderCarrier struct{ c *fiber.Ctx }
+
+func (h requestHeaderCarrier) Get(key string) string { return h.c.Get(key) }
+func (h requestHeaderCarrier) Set(key, value string) { h.c.Request().Header.Set(key, value) }
+func (h requestHeaderCarrier) Keys() []string {
+	var keys []string
+	h.c.Request().Header.VisitAll(func(k, _ []byte) { keys = append(keys, string(k)) })
+	return keys
+}
+
+type responseHeaderCarrier struct{ c *fiber.Ctx }
+
+func (h responseHeaderCarrier) Get(key string) string { return string(h.c.Response().Header.Peek(key)) }
+func (h responseHeaderCarrier) Set(key, value string) { h.c.Set(key, value) }
+func (h responseHeaderCarrier) Keys() []string {
+	var keys []string
+	h.c.Response().Header.VisitAll(func(k, _ []byte) { keys = append(keys, string(k)) })
+	return keys
+}
+
+// Tracing starts a server span for each request, continuing any W3C trace
+// context sent by the caller and returning it in the response headers. It
+// must run after RequestID so the request logger can be tagged with the trace.
+func Tracing(c *fiber.Ctx) error {
+	propagator := otel.GetTextMapPropagator()
+	ctx := propagator.Extract(c.UserContext(), requestHeaderCarrier{c})
+
+	// The path is only recorded once the route is known, so that secrets in
+	// it can be redacted; until then the span is named by its method.
+	ctx, span := tracing.Tracer().Start(ctx, c.Method(),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(semconv.HTTPRequestMethodKey.String(c.Method())),
+	)
+	defer span.End()
+
+	if sc := span.SpanContext(); sc.IsValid() {
+		ctx = logger.WithContext(ctx, logger.FromContext(ctx).With("trace_id", sc.TraceID().String()))
+	}
+	c.SetUserContext(ctx)
+	propagator.Inject(ctx, responseHeaderCarrier{c})
+
+	err := c.Next()
+
+	route := c.Route().Path
+	status := c.Response().StatusCode()
+	span.SetName(c.Method() + " " + route)
+	span.SetAttributes(
+		semconv.HTTPRoute(route),
+		semconv.URLPath(redactedPath(c)),
+		semconv.HTTPResponseStatusCode(status),
+	)
+	if userID, ok := c.Locals("userID").(string); ok {
+		span.SetAttributes(attribute.String("enduser.id", userID))
+	}
+	if status >= fiber.StatusInternalServerError {
+		span.SetStatus(codes.Error, fmt.Sprintf("HTTP %d", status))
+	}
+
+	return err
+}
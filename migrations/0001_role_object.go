@@ -0,0 +1,98 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Users created before the Role struct was introduced store their roles as a
+// `roles` string array. The first entry becomes the embedded `role` object;
+// users that already have a role keep it.
+func init() {
+	register(Migration{
+		Version: 1,
+		Name:    "convert legacy roles array to role object",
+		Up:      convertRolesToRole,
+		Down:    convertRoleToRoles,
+	})
+}
+
+func convertRolesToRole(ctx context.Context, db *mongo.Database) error {
+	users := db.Collection("users")
+
+	cursor, err := users.Find(ctx, bson.M{"roles": bson.M{"$exists": true}})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID    primitive.ObjectID `bson:"_id"`
+			Roles []string           `bson:"roles"`
+			Role  *struct {
+				Slug string `bson:"slug"`
+			} `bson:"role"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+
+		update := bson.M{"$unset": bson.M{"roles": ""}}
+		if doc.Role == nil || doc.Role.Slug == "" {
+			slug := "user"
+			if len(doc.Roles) > 0 && doc.Roles[0] != "" {
+				slug = doc.Roles[0]
+			}
+			update["$set"] = bson.M{"role": bson.M{
+				"name":        slug,
+				"slug":        slug,
+				"description": "Migrated from legacy roles",
+				"permissions": []string{},
+			}}
+		}
+
+		if _, err := users.UpdateOne(ctx, bson.M{"_id": doc.ID}, update); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func convertRoleToRoles(ctx context.Context, db *mongo.Database) error {
+	users := db.Collection("users")
+
+	cursor, err := users.Find(ctx, bson.M{"role": bson.M{"$exists": true}, "roles": bson.M{"$exists": false}})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID   primitive.ObjectID `bson:"_id"`
+			Role struct {
+				Slug string `bson:"slug"`
+			} `bson:"role"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+
+		roles := []string{}
+		if doc.Role.Slug != "" {
+			roles = append(roles, doc.Role.Slug)
+		}
+		update := bson.M{
+			"$set":   bson.M{"roles": roles},
+			"$unset": bson.M{"role": ""},
+		}
+		if _, err := users.UpdateOne(ctx, bson.M{"_id": doc.ID}, update); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
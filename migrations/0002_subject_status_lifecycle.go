@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Subjects used to be AVAILABLE or UNAVAILABLE, and CreateSubject saved
+// them without a status at all. Subjects without one become AVAILABLE and
+// UNAVAILABLE ones become SUSPENDED. Available subjects start their
+// availability duration when the migration runs, so none of them expire
+// straight away.
+func init() {
+	register(Migration{
+		Version: 2,
+		Name:    "subject status lifecycle",
+		Up:      subjectStatusLifecycleUp,
+		Down:    subjectStatusLifecycleDown,
+	})
+}
+
+func subjectStatusLifecycleUp(ctx context.Context, db *mongo.Database) error {
+	subjects := db.Collection("subjects")
+
+	missing := bson.M{"$or": bson.A{
+		bson.M{"subjectStatus": bson.M{"$exists": false}},
+		bson.M{"subjectStatus": bson.M{"$in": bson.A{"", nil}}},
+	}}
+	if _, err := subjects.UpdateMany(ctx, missing, bson.M{"$set": bson.M{"subjectStatus": "AVAILABLE"}}); err != nil {
+		return err
+	}
+	if _, err := subjects.UpdateMany(ctx, bson.M{"subjectStatus": "UNAVAILABLE"}, bson.M{"$set": bson.M{"subjectStatus": "SUSPENDED"}}); err != nil {
+		return err
+	}
+
+	since := bson.A{bson.M{"$set": bson.M{"available_since": "$$NOW"}}}
+	_, err := subjects.UpdateMany(ctx, bson.M{"subjectStatus": "AVAILABLE", "available_since": bson.M{"$exists": false}}, since)
+	return err
+}
+
+func subjectStatusLifecycleDown(ctx context.Context, db *mongo.Database) error {
+	subjects := db.Collection("subjects")
+
+	unavailable := bson.M{"subjectStatus": bson.M{"$in": bson.A{"DRAFT", "SUSPENDED", "ARCHIVED"}}}
+	if _, err := subjects.UpdateMany(ctx, unavailable, bson.M{"$set": bson.M{"subjectStatus": "UNAVAILABLE"}}); err != nil {
+		return err
+	}
+	_, err := subjects.UpdateMany(ctx, bson.M{}, bson.M{"$unset": bson.M{"available_since": ""}})
+	return err
+}
@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Only users created through Google sign-in used to get a status. Sign-in
+// now requires an active one, so users without a status become active.
+func init() {
+	register(Migration{
+		Version: 3,
+		Name:    "user status",
+		Up:      userStatusUp,
+		Down:    userStatusDown,
+	})
+}
+
+func userStatusUp(ctx context.Context, db *mongo.Database) error {
+	missing := bson.M{"$or": bson.A{
+		bson.M{"status": bson.M{"$exists": false}},
+		bson.M{"status": bson.M{"$in": bson.A{"", nil}}},
+	}}
+	_, err := db.Collection("users").UpdateMany(ctx, missing, bson.M{"$set": bson.M{"status": "active"}})
+	return err
+}
+
+// userStatusDown leaves the statuses in place: the code before this
+// migration treated users without a status and active ones alike.
+func userStatusDown(ctx context.Context, db *mongo.Database) error {
+	return nil
+}
@@ -0,0 +1,67 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const usage = "usage: migrate up | down [steps] | status"
+
+// RunCLI executes a migrate subcommand: up, down [steps] or status.
+func RunCLI(ctx context.Context, db *mongo.Database, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+
+	runner := NewRunner(db)
+	switch args[0] {
+	case "up":
+		applied, err := runner.Up(ctx)
+		for _, m := range applied {
+			fmt.Fprintf(out, "applied %d %s\n", m.Version, m.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Fprintln(out, "schema is up to date")
+		}
+		return err
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid steps %q: %s", args[1], usage)
+			}
+			steps = n
+		}
+		reverted, err := runner.Down(ctx, steps)
+		for _, m := range reverted {
+			fmt.Fprintf(out, "reverted %d %s\n", m.Version, m.Name)
+		}
+		return err
+
+	case "status":
+		statuses, err := runner.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05Z07:00")
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		return w.Flush()
+	}
+
+	return fmt.Errorf("unknown command %q: %s", args[0], usage)
+}
@@ -0,0 +1,255 @@
+package migrations
+
+import (
+	"BackendCoursyclopedia/logger"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	migrationsCollection = "schema_migrations"
+	lockCollection       = "schema_migrations_lock"
+	lockID               = "migrations"
+	defaultLockTTL       = 10 * time.Minute
+)
+
+// ErrLocked is returned when another instance holds the migration lock.
+var ErrLocked = errors.New("migrations are locked by another instance")
+
+// ErrLockLost is returned when the lock expired during a run and another
+// instance took it over.
+var ErrLockLost = errors.New("migration lock was lost during the run")
+
+// Migration is one ordered schema change. Up and Down must be idempotent:
+// a migration interrupted halfway is simply run again.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(ctx context.Context, db *mongo.Database) error
+	Down    func(ctx context.Context, db *mongo.Database) error
+}
+
+type record struct {
+	Version   int       `bson:"_id"`
+	Name      string    `bson:"name"`
+	AppliedAt time.Time `bson:"appliedAt"`
+}
+
+type Status struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"appliedAt,omitempty"`
+}
+
+var registry []Migration
+
+// register adds m to the set of known migrations. Each migration file calls it
+// from init.
+func register(m Migration) {
+	for _, existing := range registry {
+		if existing.Version == m.Version {
+			panic(fmt.Sprintf("duplicate migration version %d", m.Version))
+		}
+	}
+	registry = append(registry, m)
+	sort.Slice(registry, func(i, j int) bool { return registry[i].Version < registry[j].Version })
+}
+
+type Runner struct {
+	DB      *mongo.Database
+	Owner   string
+	LockTTL time.Duration
+}
+
+func NewRunner(db *mongo.Database) *Runner {
+	hostname, _ := os.Hostname()
+	return &Runner{
+		DB:      db,
+		Owner:   hostname + "/" + uuid.NewString(),
+		LockTTL: defaultLockTTL,
+	}
+}
+
+// Up applies every pending migration in version order.
+func (r *Runner) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := r.withLock(ctx, func(ctx context.Context) error {
+		done, err := r.appliedVersions(ctx)
+		if err != nil {
+			return err
+		}
+		for _, m := range registry {
+			if _, ok := done[m.Version]; ok {
+				continue
+			}
+			logger.FromContext(ctx).Info("applying migration", "version", m.Version, "name", m.Name)
+			if err := m.Up(ctx, r.DB); err != nil {
+				return fmt.Errorf("migration %d %s: %w", m.Version, m.Name, err)
+			}
+			_, err := r.DB.Collection(migrationsCollection).InsertOne(ctx, record{
+				Version:   m.Version,
+				Name:      m.Name,
+				AppliedAt: time.Now().UTC(),
+			})
+			if err != nil {
+				return err
+			}
+			applied = append(applied, m)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts the most recently applied migrations, newest first.
+func (r *Runner) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+	err := r.withLock(ctx, func(ctx context.Context) error {
+		done, err := r.appliedVersions(ctx)
+		if err != nil {
+			return err
+		}
+		for i := len(registry) - 1; i >= 0 && len(reverted) < steps; i-- {
+			m := registry[i]
+			if _, ok := done[m.Version]; !ok {
+				continue
+			}
+			if m.Down == nil {
+				return fmt.Errorf("migration %d %s cannot be reverted", m.Version, m.Name)
+			}
+			logger.FromContext(ctx).Info("reverting migration", "version", m.Version, "name", m.Name)
+			if err := m.Down(ctx, r.DB); err != nil {
+				return fmt.Errorf("migration %d %s: %w", m.Version, m.Name, err)
+			}
+			if _, err := r.DB.Collection(migrationsCollection).DeleteOne(ctx, bson.M{"_id": m.Version}); err != nil {
+				return err
+			}
+			reverted = append(reverted, m)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status lists every known migration and whether it has been applied.
+func (r *Runner) Status(ctx context.Context) ([]Status, error) {
+	done, err := r.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(registry))
+	for _, m := range registry {
+		status := Status{Version: m.Version, Name: m.Name}
+		if rec, ok := done[m.Version]; ok {
+			appliedAt := rec.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func (r *Runner) appliedVersions(ctx context.Context) (map[int]record, error) {
+	cursor, err := r.DB.Collection(migrationsCollection).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	done := map[int]record{}
+	for cursor.Next(ctx) {
+		var rec record
+		if err := cursor.Decode(&rec); err != nil {
+			return nil, err
+		}
+		done[rec.Version] = rec
+	}
+	return done, cursor.Err()
+}
+
+// withLock runs fn while holding a lease on the lock document. The lease
+// expires after LockTTL so a crashed instance cannot block migrations forever,
+// and is renewed every third of that while fn runs, so a long migration
+// keeps it. If the lease is lost anyway, fn's context is cancelled.
+func (r *Runner) withLock(ctx context.Context, fn func(ctx context.Context) error) error {
+	locks := r.DB.Collection(lockCollection)
+	now := time.Now().UTC()
+
+	_, err := locks.UpdateOne(ctx,
+		bson.M{"_id": lockID, "expiresAt": bson.M{"$lt": now}},
+		bson.M{"$set": bson.M{"owner": r.Owner, "lockedAt": now, "expiresAt": now.Add(r.LockTTL)}},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrLocked
+	}
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		releaseCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if _, err := locks.DeleteOne(releaseCtx, bson.M{"_id": lockID, "owner": r.Owner}); err != nil {
+			logger.FromContext(ctx).Error("failed to release migration lock", "error", err)
+		}
+	}()
+
+	fnCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		r.renewLock(fnCtx, cancel)
+	}()
+
+	err = fn(fnCtx)
+	cancel(nil)
+	<-stopped
+	if cause := context.Cause(fnCtx); errors.Is(cause, ErrLockLost) && err != nil {
+		return fmt.Errorf("%w: %w", ErrLockLost, err)
+	}
+	return err
+}
+
+// renewLock extends the lease until ctx is done. It cancels ctx with
+// ErrLockLost if another instance has taken the lock over.
+func (r *Runner) renewLock(ctx context.Context, cancel context.CancelCauseFunc) {
+	ticker := time.NewTicker(r.LockTTL / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		result, err := r.DB.Collection(lockCollection).UpdateOne(ctx,
+			bson.M{"_id": lockID, "owner": r.Owner},
+			bson.M{"$set": bson.M{"expiresAt": time.Now().UTC().Add(r.LockTTL)}},
+		)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.FromContext(ctx).Error("failed to renew migration lock", "error", err)
+			}
+			continue
+		}
+		if result.MatchedCount == 0 {
+			logger.FromContext(ctx).Error("lost the migration lock to another instance; stopping")
+			cancel(ErrLockLost)
+			return
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package authtokenmodel
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	PurposePasswordReset     = "password_reset"
+	PurposeEmailVerification = "email_verification"
+)
+
+// Token is a single-use credential emailed to a user. Only the SHA-256 of
+// the token is stored; it is deleted when used and expires at ExpiresAt.
+type Token struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `bson:"userId"`
+	Purpose   string             `bson:"purpose"`
+	Hash      string             `bson:"hash"`
+	ExpiresAt time.Time          `bson:"expiresAt"`
+	CreatedAt time.Time          `bson:"createdAt"`
+}
@@ -0,0 +1,62 @@
+package curriculummodel
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// Every subject of a core group is required.
+	GroupCore = "core"
+	// An elective group is satisfied by MinCredits and/or MinCount of its
+	// subjects.
+	GroupElective = "elective"
+)
+
+// CurriculumSubject places a subject in the recommended study sequence.
+type CurriculumSubject struct {
+	SubjectID primitive.ObjectID `bson:"subjectId" json:"subjectId"`
+	Year      int                `bson:"year" json:"year"`
+	Semester  int                `bson:"semester" json:"semester"`
+}
+
+type RequirementGroup struct {
+	Name       string              `bson:"name" json:"name"`
+	Kind       string              `bson:"kind" json:"kind"`
+	MinCredits int                 `bson:"minCredits,omitempty" json:"minCredits,omitempty"`
+	MinCount   int                 `bson:"minCount,omitempty" json:"minCount,omitempty"`
+	Subjects   []CurriculumSubject `bson:"subjects" json:"subjects"`
+}
+
+// Curriculum is the set of requirements a major places on students of one
+// intake year. Curricula are never edited in place: each save stores a new
+// version and the highest version is the current one.
+type Curriculum struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	MajorID    primitive.ObjectID `bson:"majorId" json:"majorId"`
+	IntakeYear int                `bson:"intakeYear" json:"intakeYear"`
+	Version    int                `bson:"version" json:"version"`
+	// TotalCredits is the minimum number of credits needed to graduate,
+	// which may exceed what the groups require on their own.
+	TotalCredits int                `bson:"totalCredits" json:"totalCredits"`
+	Groups       []RequirementGroup `bson:"groups" json:"groups"`
+	Note         string             `bson:"note,omitempty" json:"note,omitempty"`
+	CreatedBy    primitive.ObjectID `bson:"createdBy,omitempty" json:"createdBy,omitempty"`
+	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// SubjectIDs lists every subject referenced by the curriculum once.
+func (c Curriculum) SubjectIDs() []primitive.ObjectID {
+	seen := map[primitive.ObjectID]bool{}
+	var ids []primitive.ObjectID
+	for _, g := range c.Groups {
+		for _, s := range g.Subjects {
+			if !seen[s.SubjectID] {
+				seen[s.SubjectID] = true
+				ids = append(ids, s.SubjectID)
+			}
+		}
+	}
+	return ids
+}
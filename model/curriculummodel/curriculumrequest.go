@@ -0,0 +1,48 @@
+package curriculummodel
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CurriculumSubjectRequest struct {
+	SubjectID string `json:"subjectId" validate:"required,mongodb"`
+	Year      int    `json:"year" validate:"required,min=1,max=8"`
+	Semester  int    `json:"semester" validate:"required,min=1,max=3"`
+}
+
+type RequirementGroupRequest struct {
+	Name       string                     `json:"name" validate:"required,max=200"`
+	Kind       string                     `json:"kind" validate:"required,oneof=core elective"`
+	MinCredits int                        `json:"minCredits" validate:"min=0"`
+	MinCount   int                        `json:"minCount" validate:"min=0"`
+	Subjects   []CurriculumSubjectRequest `json:"subjects" validate:"required,min=1,max=200,dive"`
+}
+
+type CurriculumRequest struct {
+	TotalCredits int                       `json:"totalCredits" validate:"required,min=1,max=400"`
+	Groups       []RequirementGroupRequest `json:"groups" validate:"required,min=1,max=50,dive"`
+	Note         string                    `json:"note" validate:"max=2000"`
+}
+
+// ToGroups converts validated groups into the stored model.
+func (r CurriculumRequest) ToGroups() ([]RequirementGroup, error) {
+	groups := make([]RequirementGroup, len(r.Groups))
+	for i, g := range r.Groups {
+		subjects := make([]CurriculumSubject, len(g.Subjects))
+		for j, s := range g.Subjects {
+			id, err := primitive.ObjectIDFromHex(s.SubjectID)
+			if err != nil {
+				return nil, err
+			}
+			subjects[j] = CurriculumSubject{SubjectID: id, Year: s.Year, Semester: s.Semester}
+		}
+		groups[i] = RequirementGroup{
+			Name:       g.Name,
+			Kind:       g.Kind,
+			MinCredits: g.MinCredits,
+			MinCount:   g.MinCount,
+			Subjects:   subjects,
+		}
+	}
+	return groups, nil
+}
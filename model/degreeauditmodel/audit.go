@@ -0,0 +1,67 @@
+package degreeauditmodel
+
+import (
+	"BackendCoursyclopedia/model/usermodel"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SubjectRef struct {
+	SubjectID   primitive.ObjectID `json:"subjectId"`
+	SubjectCode string             `json:"subjectCode"`
+	Name        string             `json:"name"`
+	Credit      int                `json:"credit"`
+	Year        int                `json:"year,omitempty"`
+	Semester    int                `json:"semester,omitempty"`
+}
+
+type RequirementResult struct {
+	Group           string       `json:"group"`
+	Kind            string       `json:"kind"`
+	Satisfied       bool         `json:"satisfied"`
+	RequiredCredits int          `json:"requiredCredits,omitempty"`
+	EarnedCredits   int          `json:"earnedCredits"`
+	RequiredCount   int          `json:"requiredCount,omitempty"`
+	CompletedCount  int          `json:"completedCount"`
+	Completed       []SubjectRef `json:"completed"`
+	Remaining       []SubjectRef `json:"remaining"`
+}
+
+// EligibleSubject can be taken next: every prerequisite is passed. Any
+// co-requisites not yet passed must be taken in the same term.
+type EligibleSubject struct {
+	SubjectRef
+	TakeWith []string `json:"takeWith,omitempty"`
+}
+
+// BlockedSubject still needs MissingPrerequisites, in the order they can be
+// taken.
+type BlockedSubject struct {
+	SubjectRef
+	MissingPrerequisites []string `json:"missingPrerequisites"`
+}
+
+type Report struct {
+	UserID            primitive.ObjectID  `json:"userId"`
+	MajorID           primitive.ObjectID  `json:"majorId"`
+	IntakeYear        int                 `json:"intakeYear"`
+	CurriculumID      primitive.ObjectID  `json:"curriculumId"`
+	CurriculumVersion int                 `json:"curriculumVersion"`
+	Satisfied         bool                `json:"satisfied"`
+	RequiredCredits   int                 `json:"requiredCredits"`
+	EarnedCredits     int                 `json:"earnedCredits"`
+	RemainingCredits  int                 `json:"remainingCredits"`
+	Requirements      []RequirementResult `json:"requirements"`
+	Eligible          []EligibleSubject   `json:"eligible"`
+	Blocked           []BlockedSubject    `json:"blocked"`
+	// Unknown lists completed subject IDs that no longer exist; they earn
+	// no credits.
+	Unknown []primitive.ObjectID `json:"unknown,omitempty"`
+}
+
+// Record is the part of a user that the audit reads.
+type Record struct {
+	MajorID           primitive.ObjectID           `json:"majorId"`
+	IntakeYear        int                          `json:"intakeYear"`
+	CompletedSubjects []usermodel.CompletedSubject `json:"completedSubjects"`
+}
@@ -0,0 +1,14 @@
+package facultymodel
+
+// FacultyCreateRequest is the form of a new faculty; its image is uploaded
+// alongside as the "image" file.
+type FacultyCreateRequest struct {
+	FacultyName string `json:"FacultyName" form:"FacultyName" validate:"required,max=200"`
+}
+
+// FacultyUpdateRequest is the JSON carried in the "faculty" form field of an
+// update. The faculty's majors are managed through the majors API and cannot
+// be set here.
+type FacultyUpdateRequest struct {
+	FacultyName string `json:"FacultyName" validate:"required,max=200"`
+}
@@ -0,0 +1,45 @@
+package integritymodel
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// A parent references a document that does not exist.
+	KindDanglingMajor   = "dangling_major"
+	KindDanglingSubject = "dangling_subject"
+	KindDanglingFaculty = "dangling_faculty"
+	// A document that should have exactly one parent has none.
+	KindOrphanMajor   = "orphan_major"
+	KindOrphanSubject = "orphan_subject"
+	// A document is referenced by more than one parent.
+	KindMultiParentMajor   = "multi_parent_major"
+	KindMultiParentSubject = "multi_parent_subject"
+)
+
+type Anomaly struct {
+	Kind string `bson:"kind" json:"kind"`
+	// Collection and DocumentID identify the document holding the bad
+	// reference, or the orphan itself.
+	Collection string             `bson:"collection" json:"collection"`
+	DocumentID primitive.ObjectID `bson:"documentId" json:"documentId"`
+	Field      string             `bson:"field,omitempty" json:"field,omitempty"`
+	// RefID is the missing document of a dangling reference; Parents lists
+	// every parent of a multi-parent document, oldest first. A fix keeps
+	// the document in the oldest parent.
+	RefID   primitive.ObjectID   `bson:"refId,omitempty" json:"refId,omitempty"`
+	Parents []primitive.ObjectID `bson:"parents,omitempty" json:"parents,omitempty"`
+	Fixed   bool                 `bson:"fixed" json:"fixed"`
+	Detail  string               `bson:"detail" json:"detail"`
+}
+
+type Report struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	StartedAt  time.Time          `bson:"startedAt" json:"startedAt"`
+	FinishedAt time.Time          `bson:"finishedAt" json:"finishedAt"`
+	FixMode    bool               `bson:"fixMode" json:"fixMode"`
+	Counts     map[string]int     `bson:"counts" json:"counts"`
+	Anomalies  []Anomaly          `bson:"anomalies" json:"anomalies"`
+}
@@ -0,0 +1,76 @@
+package jobmodel
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	StatusQueued    = "queued"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	// StatusDead marks a run that failed on every attempt. Dead runs form
+	// the dead-letter list until they are retried or discarded.
+	StatusDead = "dead"
+)
+
+// Statuses lists every value of Run.Status.
+var Statuses = []string{StatusQueued, StatusRunning, StatusSucceeded, StatusDead}
+
+const (
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+	TriggerRetry    = "retry"
+)
+
+// Run is one execution of a job in the queue. A worker leases a queued run
+// by setting LeaseOwner and LeaseExpiresAt and keeps extending the lease
+// while it works, so a run whose lease has lapsed belongs to a worker that
+// died and may be picked up again.
+type Run struct {
+	ID      primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Job     string             `bson:"job" json:"job"`
+	Payload bson.M             `bson:"payload,omitempty" json:"payload,omitempty"`
+	Status  string             `bson:"status" json:"status"`
+	Trigger string             `bson:"trigger" json:"trigger"`
+	// TriggeredBy is the admin who enqueued a manual run.
+	TriggeredBy primitive.ObjectID `bson:"triggeredBy,omitempty" json:"triggeredBy,omitempty"`
+	// DedupKey is set on scheduled runs so that every replica enqueuing the
+	// same occurrence produces one run.
+	DedupKey       string     `bson:"dedupKey,omitempty" json:"-"`
+	Attempts       int        `bson:"attempts" json:"attempts"`
+	MaxAttempts    int        `bson:"maxAttempts" json:"maxAttempts"`
+	RunAt          time.Time  `bson:"runAt" json:"runAt"`
+	LeaseOwner     string     `bson:"leaseOwner,omitempty" json:"leaseOwner,omitempty"`
+	LeaseExpiresAt *time.Time `bson:"leaseExpiresAt,omitempty" json:"leaseExpiresAt,omitempty"`
+	LastError      string     `bson:"lastError,omitempty" json:"lastError,omitempty"`
+	CreatedAt      time.Time  `bson:"createdAt" json:"createdAt"`
+	StartedAt      *time.Time `bson:"startedAt,omitempty" json:"startedAt,omitempty"`
+	FinishedAt     *time.Time `bson:"finishedAt,omitempty" json:"finishedAt,omitempty"`
+}
+
+// Filter selects runs, newest first. Zero fields match everything.
+type Filter struct {
+	Job    string
+	Status string
+	Limit  int64
+}
+
+// Info describes a registered job for the admin API.
+type Info struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Schedule is the cron expression the job runs on; empty for jobs that
+	// only run when triggered.
+	Schedule    string     `json:"schedule,omitempty"`
+	NextRun     *time.Time `json:"nextRun,omitempty"`
+	MaxAttempts int        `json:"maxAttempts"`
+	Timeout     string     `json:"timeout"`
+	LastRun     *Run       `json:"lastRun,omitempty"`
+}
+
+type TriggerRequest struct {
+	Payload bson.M `json:"payload"`
+}
@@ -0,0 +1,11 @@
+package majormodel
+
+type MajorCreateRequest struct {
+	MajorName string `json:"majorName" validate:"required,max=100"`
+	FacultyID string `json:"facultyId" validate:"required,mongodb"`
+}
+
+type MajorUpdateRequest struct {
+	NewMajorName string `json:"newMajorName" validate:"max=100"`
+	NewFacultyID string `json:"newFacultyId" validate:"omitempty,mongodb"`
+}
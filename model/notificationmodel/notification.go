@@ -0,0 +1,61 @@
+package notificationmodel
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	KindAccountCreated = "account_created"
+	// KindPasswordReset tells a user their password was changed.
+	KindPasswordReset = "password_reset"
+	// KindPasswordResetRequest and KindEmailVerification carry a one-time
+	// link.
+	KindPasswordResetRequest = "password_reset_request"
+	KindEmailVerification    = "email_verification"
+	// KindSubjectStatus tells a user that a subject on their wishlist
+	// changed status.
+	KindSubjectStatus = "subject_status"
+)
+
+// OptionalKinds are the kinds a user may mute. The others concern the
+// account itself and are always sent.
+var OptionalKinds = []string{KindSubjectStatus}
+
+// RedactedKinds are the kinds whose emails carry a one-time link.
+var RedactedKinds = []string{KindPasswordResetRequest, KindEmailVerification}
+
+const (
+	StatusPending = "pending"
+	StatusSending = "sending"
+	StatusSent    = "sent"
+	// StatusFailed marks a notification that could not be delivered after
+	// every attempt.
+	StatusFailed = "failed"
+)
+
+// Notification is an email in the outbox. It is rendered when it is queued,
+// so it is delivered as it read at the time of the event. A sender claims it
+// by moving it to sending until LeaseExpiresAt; one left sending after that
+// belongs to a sender that died and is picked up again.
+type Notification struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID         primitive.ObjectID `bson:"userId" json:"userId"`
+	Kind           string             `bson:"kind" json:"kind"`
+	Locale         string             `bson:"locale" json:"locale"`
+	To             string             `bson:"to" json:"to"`
+	Subject        string             `bson:"subject" json:"subject"`
+	Text           string             `bson:"text" json:"text"`
+	HTML           string             `bson:"html" json:"html"`
+	Status         string             `bson:"status" json:"status"`
+	Attempts       int                `bson:"attempts" json:"attempts"`
+	NextAttemptAt  time.Time          `bson:"nextAttemptAt" json:"nextAttemptAt"`
+	LeaseExpiresAt *time.Time         `bson:"leaseExpiresAt,omitempty" json:"-"`
+	LastError      string             `bson:"lastError,omitempty" json:"lastError,omitempty"`
+	CreatedAt      time.Time          `bson:"createdAt" json:"createdAt"`
+	SentAt         *time.Time         `bson:"sentAt,omitempty" json:"sentAt,omitempty"`
+	// Redact marks an email carrying a one-time link. Its bodies are
+	// removed once it has been sent or has failed, so the link is not kept.
+	Redact bool `bson:"redact,omitempty" json:"-"`
+}
@@ -0,0 +1,38 @@
+package offeringmodel
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	StatusPlanned   = "planned"
+	StatusOpen      = "open"
+	StatusClosed    = "closed"
+	StatusCancelled = "cancelled"
+)
+
+// Statuses lists every value accepted for Offering.Status.
+var Statuses = []string{StatusPlanned, StatusOpen, StatusClosed, StatusCancelled}
+
+// Offering is a subject taught in a term on one campus.
+type Offering struct {
+	ID         primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	SubjectID  primitive.ObjectID   `bson:"subjectId" json:"subjectId"`
+	TermID     primitive.ObjectID   `bson:"termId" json:"termId"`
+	Campus     string               `bson:"campus" json:"campus"`
+	Professors []primitive.ObjectID `bson:"professors" json:"professors"`
+	// Capacity is the number of seats; 0 means unlimited.
+	Capacity  int       `bson:"capacity" json:"capacity"`
+	Status    string    `bson:"status" json:"status"`
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// Filter selects offerings. Zero fields match everything.
+type Filter struct {
+	TermID     primitive.ObjectID
+	SubjectIDs []primitive.ObjectID
+	Status     string
+}
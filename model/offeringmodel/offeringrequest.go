@@ -0,0 +1,37 @@
+package offeringmodel
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OfferingRequest struct {
+	SubjectID  string   `json:"subjectId" validate:"required,mongodb"`
+	TermID     string   `json:"termId" validate:"required,mongodb"`
+	Campus     string   `json:"campus" validate:"max=100"`
+	Professors []string `json:"professors" validate:"max=20,dive,mongodb"`
+	Capacity   int      `json:"capacity" validate:"min=0,max=10000"`
+	Status     string   `json:"status" validate:"omitempty,oneof=planned open closed cancelled"`
+}
+
+// ToOffering converts a validated request. Status defaults to planned.
+func (r OfferingRequest) ToOffering() Offering {
+	subjectID, _ := primitive.ObjectIDFromHex(r.SubjectID)
+	termID, _ := primitive.ObjectIDFromHex(r.TermID)
+	professors := make([]primitive.ObjectID, 0, len(r.Professors))
+	for _, p := range r.Professors {
+		id, _ := primitive.ObjectIDFromHex(p)
+		professors = append(professors, id)
+	}
+	status := r.Status
+	if status == "" {
+		status = StatusPlanned
+	}
+	return Offering{
+		SubjectID:  subjectID,
+		TermID:     termID,
+		Campus:     r.Campus,
+		Professors: professors,
+		Capacity:   r.Capacity,
+		Status:     status,
+	}
+}
@@ -0,0 +1,80 @@
+package offeringmodel
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/validation"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestOfferingRequestValidation(t *testing.T) {
+	subjectID, termID := primitive.NewObjectID().Hex(), primitive.NewObjectID().Hex()
+	valid := func() OfferingRequest {
+		return OfferingRequest{SubjectID: subjectID, TermID: termID, Capacity: 60, Status: StatusOpen}
+	}
+
+	tests := []struct {
+		name       string
+		modify     func(r *OfferingRequest)
+		wantFields []string
+	}{
+		{name: "valid", modify: func(*OfferingRequest) {}},
+		{name: "status may be left out", modify: func(r *OfferingRequest) { r.Status = "" }},
+		{name: "missing subject and term", modify: func(r *OfferingRequest) { r.SubjectID, r.TermID = "", "" }, wantFields: []string{"subjectId", "termId"}},
+		{name: "malformed IDs", modify: func(r *OfferingRequest) { r.SubjectID, r.Professors = "CSX3001", []string{"nobody"} }, wantFields: []string{"subjectId", "professors[0]"}},
+		{name: "negative capacity", modify: func(r *OfferingRequest) { r.Capacity = -1 }, wantFields: []string{"capacity"}},
+		{name: "unknown status", modify: func(r *OfferingRequest) { r.Status = "full" }, wantFields: []string{"status"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := valid()
+			tt.modify(&request)
+
+			var got []string
+			if err := validation.Struct(request); err != nil {
+				appErr, ok := apperror.As(err)
+				if !ok {
+					t.Fatalf("validation.Struct() error = %v", err)
+				}
+				for _, fe := range appErr.Fields {
+					got = append(got, fe.Field)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.wantFields) {
+				t.Errorf("invalid fields = %v, want %v", got, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestToOffering(t *testing.T) {
+	subjectID, termID, professor := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+
+	tests := []struct {
+		name       string
+		status     string
+		wantStatus string
+	}{
+		{name: "status defaults to planned", wantStatus: StatusPlanned},
+		{name: "status kept", status: StatusClosed, wantStatus: StatusClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := OfferingRequest{
+				SubjectID: subjectID.Hex(), TermID: termID.Hex(), Campus: "Hua Mak",
+				Professors: []string{professor.Hex()}, Capacity: 40, Status: tt.status,
+			}.ToOffering()
+			want := Offering{
+				SubjectID: subjectID, TermID: termID, Campus: "Hua Mak",
+				Professors: []primitive.ObjectID{professor}, Capacity: 40, Status: tt.wantStatus,
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ToOffering() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
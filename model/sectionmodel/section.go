@@ -0,0 +1,88 @@
+package sectionmodel
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ExamMidterm = "midterm"
+	ExamFinal   = "final"
+)
+
+// Days lists the values accepted for Meeting.Day, in week order.
+var Days = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+// Meeting is a weekly class slot. Start and End are local wall-clock times
+// formatted as "15:04".
+type Meeting struct {
+	Day   string `bson:"day" json:"day"`
+	Start string `bson:"start" json:"start"`
+	End   string `bson:"end" json:"end"`
+	Room  string `bson:"room,omitempty" json:"room,omitempty"`
+}
+
+// Weekday returns Day as a time.Weekday.
+func (m Meeting) Weekday() time.Weekday {
+	for i, d := range Days {
+		if d == m.Day {
+			return time.Weekday((i + 1) % 7)
+		}
+	}
+	return time.Sunday
+}
+
+// Minutes returns Start and End as minutes after midnight.
+func (m Meeting) Minutes() (start, end int) {
+	return ClockMinutes(m.Start), ClockMinutes(m.End)
+}
+
+// Overlaps reports whether m and o share any time on the same day. Meetings
+// that only touch, one ending as the other starts, do not overlap.
+func (m Meeting) Overlaps(o Meeting) bool {
+	if m.Day != o.Day {
+		return false
+	}
+	ms, me := m.Minutes()
+	os, oe := o.Minutes()
+	return ms < oe && os < me
+}
+
+// Exam is a one-off examination sitting.
+type Exam struct {
+	Kind  string    `bson:"kind" json:"kind"`
+	Start time.Time `bson:"start" json:"start"`
+	End   time.Time `bson:"end" json:"end"`
+	Room  string    `bson:"room,omitempty" json:"room,omitempty"`
+}
+
+// Overlaps reports whether e and o share any time.
+func (e Exam) Overlaps(o Exam) bool {
+	return e.Start.Before(o.End) && o.Start.Before(e.End)
+}
+
+// Section is one class group of an offering, with its weekly meetings and
+// exam sittings.
+type Section struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	OfferingID primitive.ObjectID `bson:"offeringId" json:"offeringId"`
+	Number     string             `bson:"number" json:"number"`
+	Meetings   []Meeting          `bson:"meetings" json:"meetings"`
+	Exams      []Exam             `bson:"exams" json:"exams"`
+	// Capacity is the number of seats; 0 means the offering's capacity.
+	Capacity  int       `bson:"capacity" json:"capacity"`
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// ClockMinutes converts "15:04" into minutes after midnight. Invalid input
+// returns 0; requests are validated before they get here.
+func ClockMinutes(clock string) int {
+	var h, m int
+	if _, err := fmt.Sscanf(clock, "%d:%d", &h, &m); err != nil {
+		return 0
+	}
+	return h*60 + m
+}
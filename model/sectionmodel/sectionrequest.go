@@ -0,0 +1,49 @@
+package sectionmodel
+
+import "time"
+
+type MeetingRequest struct {
+	Day   string `json:"day" validate:"required,oneof=monday tuesday wednesday thursday friday saturday sunday"`
+	Start string `json:"start" validate:"required,datetime=15:04"`
+	End   string `json:"end" validate:"required,datetime=15:04"`
+	Room  string `json:"room" validate:"max=50"`
+}
+
+type ExamRequest struct {
+	Kind  string `json:"kind" validate:"required,oneof=midterm final"`
+	Start string `json:"start" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+	End   string `json:"end" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+	Room  string `json:"room" validate:"max=50"`
+}
+
+type SectionRequest struct {
+	Number   string           `json:"number" validate:"required,max=20"`
+	Meetings []MeetingRequest `json:"meetings" validate:"max=14,dive"`
+	Exams    []ExamRequest    `json:"exams" validate:"max=4,dive"`
+	Capacity int              `json:"capacity" validate:"min=0,max=10000"`
+}
+
+// ToSection converts a validated request.
+func (r SectionRequest) ToSection() Section {
+	section := Section{
+		Number:   r.Number,
+		Meetings: make([]Meeting, len(r.Meetings)),
+		Exams:    make([]Exam, len(r.Exams)),
+		Capacity: r.Capacity,
+	}
+	for i, m := range r.Meetings {
+		section.Meetings[i] = Meeting{Day: m.Day, Start: clock(m.Start), End: clock(m.End), Room: m.Room}
+	}
+	for i, e := range r.Exams {
+		start, _ := time.Parse(time.RFC3339, e.Start)
+		end, _ := time.Parse(time.RFC3339, e.End)
+		section.Exams[i] = Exam{Kind: e.Kind, Start: start.UTC(), End: end.UTC(), Room: e.Room}
+	}
+	return section
+}
+
+// clock normalises "9:05" to "09:05" so that clock strings sort correctly.
+func clock(s string) string {
+	t, _ := time.Parse("15:04", s)
+	return t.Format("15:04")
+}
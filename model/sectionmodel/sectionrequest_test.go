@@ -0,0 +1,107 @@
+package sectionmodel
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/validation"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSectionRequestValidation(t *testing.T) {
+	valid := func() SectionRequest {
+		return SectionRequest{
+			Number:   "541",
+			Capacity: 40,
+			Meetings: []MeetingRequest{{Day: "monday", Start: "09:00", End: "10:30", Room: "VMS0301"}},
+			Exams:    []ExamRequest{{Kind: ExamFinal, Start: "2026-12-10T09:00:00+07:00", End: "2026-12-10T12:00:00+07:00"}},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		modify     func(r *SectionRequest)
+		wantFields []string
+	}{
+		{name: "valid", modify: func(*SectionRequest) {}},
+		{name: "no meetings or exams", modify: func(r *SectionRequest) { r.Meetings, r.Exams = nil, nil }},
+		{name: "missing number", modify: func(r *SectionRequest) { r.Number = "" }, wantFields: []string{"number"}},
+		{name: "unknown day", modify: func(r *SectionRequest) { r.Meetings[0].Day = "mon" }, wantFields: []string{"meetings[0].day"}},
+		{name: "malformed clock", modify: func(r *SectionRequest) { r.Meetings[0].Start = "9am" }, wantFields: []string{"meetings[0].start"}},
+		{name: "exam time without offset", modify: func(r *SectionRequest) { r.Exams[0].End = "2026-12-10 12:00" }, wantFields: []string{"exams[0].end"}},
+		{name: "unknown exam kind", modify: func(r *SectionRequest) { r.Exams[0].Kind = "quiz" }, wantFields: []string{"exams[0].kind"}},
+		{name: "negative capacity", modify: func(r *SectionRequest) { r.Capacity = -1 }, wantFields: []string{"capacity"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := valid()
+			tt.modify(&request)
+
+			var got []string
+			if err := validation.Struct(request); err != nil {
+				appErr, ok := apperror.As(err)
+				if !ok {
+					t.Fatalf("validation.Struct() error = %v", err)
+				}
+				for _, fe := range appErr.Fields {
+					got = append(got, fe.Field)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.wantFields) {
+				t.Errorf("invalid fields = %v, want %v", got, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestToSection(t *testing.T) {
+	request := SectionRequest{
+		Number:   "541",
+		Capacity: 40,
+		Meetings: []MeetingRequest{{Day: "monday", Start: "9:05", End: "10:30", Room: "VMS0301"}},
+		Exams:    []ExamRequest{{Kind: ExamFinal, Start: "2026-12-10T09:00:00+07:00", End: "2026-12-10T12:00:00+07:00"}},
+	}
+
+	got := request.ToSection()
+
+	wantMeetings := []Meeting{{Day: "monday", Start: "09:05", End: "10:30", Room: "VMS0301"}}
+	if !reflect.DeepEqual(got.Meetings, wantMeetings) {
+		t.Errorf("Meetings = %+v, want %+v", got.Meetings, wantMeetings)
+	}
+	wantExams := []Exam{{
+		Kind:  ExamFinal,
+		Start: time.Date(2026, 12, 10, 2, 0, 0, 0, time.UTC),
+		End:   time.Date(2026, 12, 10, 5, 0, 0, 0, time.UTC),
+	}}
+	if !reflect.DeepEqual(got.Exams, wantExams) {
+		t.Errorf("Exams = %+v, want %+v", got.Exams, wantExams)
+	}
+	if got.Number != "541" || got.Capacity != 40 {
+		t.Errorf("Number, Capacity = %q, %d, want 541, 40", got.Number, got.Capacity)
+	}
+}
+
+func TestMeetingOverlaps(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Meeting
+		want bool
+	}{
+		{name: "same slot", a: Meeting{Day: "monday", Start: "09:00", End: "10:30"}, b: Meeting{Day: "monday", Start: "09:00", End: "10:30"}, want: true},
+		{name: "partial", a: Meeting{Day: "monday", Start: "09:00", End: "10:30"}, b: Meeting{Day: "monday", Start: "10:00", End: "11:00"}, want: true},
+		{name: "touching", a: Meeting{Day: "monday", Start: "09:00", End: "10:30"}, b: Meeting{Day: "monday", Start: "10:30", End: "12:00"}},
+		{name: "other day", a: Meeting{Day: "monday", Start: "09:00", End: "10:30"}, b: Meeting{Day: "tuesday", Start: "09:00", End: "10:30"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Overlaps(tt.b); got != tt.want {
+				t.Errorf("Overlaps() = %v, want %v", got, tt.want)
+			}
+			if got := tt.b.Overlaps(tt.a); got != tt.want {
+				t.Errorf("reversed Overlaps() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
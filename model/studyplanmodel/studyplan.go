@@ -0,0 +1,59 @@
+package studyplanmodel
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Issue kinds. A plan with any issue is not feasible.
+const (
+	IssueNotOffered          = "not_offered"
+	IssueOverCreditCap       = "over_credit_cap"
+	IssueUnknownPrerequisite = "unknown_prerequisite"
+	IssuePrerequisiteCycle   = "prerequisite_cycle"
+	IssuePrerequisiteOrder   = "prerequisite_order"
+	IssueCoRequisiteMissing  = "corequisite_missing"
+	IssueDuplicate           = "duplicate"
+	IssueAlreadyPassed       = "already_passed"
+	IssueUnknownSubject      = "unknown_subject"
+	IssueTooFewSemesters     = "too_few_semesters"
+	IssueRequirementsUnmet   = "requirements_unmet"
+)
+
+type PlannedSubject struct {
+	SubjectID   primitive.ObjectID `bson:"subjectId" json:"subjectId"`
+	SubjectCode string             `bson:"subjectCode" json:"subjectCode"`
+	Name        string             `bson:"name" json:"name"`
+	Credit      int                `bson:"credit" json:"credit"`
+}
+
+type Semester struct {
+	Subjects []PlannedSubject `bson:"subjects" json:"subjects"`
+	Credits  int              `bson:"credits" json:"credits"`
+}
+
+// Issue explains why a plan is not feasible. Semester is 1-based and 0 for
+// issues about the plan as a whole.
+type Issue struct {
+	Kind        string `bson:"kind" json:"kind"`
+	Semester    int    `bson:"semester,omitempty" json:"semester,omitempty"`
+	SubjectCode string `bson:"subjectCode,omitempty" json:"subjectCode,omitempty"`
+	Message     string `bson:"message" json:"message"`
+}
+
+type Plan struct {
+	ID                    primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID                primitive.ObjectID `bson:"userId" json:"userId"`
+	Name                  string             `bson:"name" json:"name"`
+	MajorID               primitive.ObjectID `bson:"majorId" json:"majorId"`
+	IntakeYear            int                `bson:"intakeYear" json:"intakeYear"`
+	CurriculumVersion     int                `bson:"curriculumVersion" json:"curriculumVersion"`
+	MaxCreditsPerSemester int                `bson:"maxCreditsPerSemester" json:"maxCreditsPerSemester"`
+	Semesters             []Semester         `bson:"semesters" json:"semesters"`
+	Feasible              bool               `bson:"feasible" json:"feasible"`
+	Issues                []Issue            `bson:"issues" json:"issues"`
+	ValidatedAt           time.Time          `bson:"validatedAt" json:"validatedAt"`
+	CreatedAt             time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt             time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
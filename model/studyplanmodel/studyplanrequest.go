@@ -0,0 +1,25 @@
+package studyplanmodel
+
+// GenerateRequest asks for a plan that finishes within Semesters semesters,
+// or as soon as possible when Semesters is 0. MajorID and IntakeYear default
+// to the user's own.
+type GenerateRequest struct {
+	MaxCreditsPerSemester int    `json:"maxCreditsPerSemester" validate:"required,min=1,max=40"`
+	Semesters             int    `json:"semesters" validate:"min=0,max=16"`
+	MajorID               string `json:"majorId" validate:"omitempty,mongodb"`
+	IntakeYear            int    `json:"intakeYear" validate:"omitempty,min=1950,max=2200"`
+}
+
+type SemesterRequest struct {
+	Subjects []string `json:"subjects" validate:"max=20,dive,mongodb"`
+}
+
+// PlanRequest saves or replaces a plan. Semesters list subject IDs in the
+// order they are to be taken.
+type PlanRequest struct {
+	Name                  string            `json:"name" validate:"required,max=100"`
+	MaxCreditsPerSemester int               `json:"maxCreditsPerSemester" validate:"required,min=1,max=40"`
+	Semesters             []SemesterRequest `json:"semesters" validate:"required,min=1,max=16,dive"`
+	MajorID               string            `json:"majorId" validate:"omitempty,mongodb"`
+	IntakeYear            int               `json:"intakeYear" validate:"omitempty,min=1950,max=2200"`
+}
@@ -1,6 +1,8 @@
 package subjectmodel
 
 import (
+	"time"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -18,5 +20,24 @@ type Subject struct {
 	Likelist           []string             `bson:"likelist"`
 	SubjectStatus      string               `bson:"subjectStatus"`
 	LastUpdated        primitive.DateTime   `bson:"last_updated"`
-	AvailableDuration  int                  `bson:"available_duration"`
+	// AvailableDuration is the number of days a subject stays AVAILABLE
+	// before it is archived; 0 keeps it available indefinitely.
+	AvailableDuration int `bson:"available_duration"`
+	// AvailableSince is when the subject last became AVAILABLE.
+	AvailableSince primitive.DateTime `bson:"available_since,omitempty"`
+}
+
+// IsAvailable reports whether students can currently take the subject.
+// Subjects saved before statuses were enforced may have none.
+func (s Subject) IsAvailable() bool {
+	return s.SubjectStatus == "" || s.SubjectStatus == StatusAvailable
+}
+
+// ExpiresAt returns when an AVAILABLE subject's availability duration runs
+// out, if it has one.
+func (s Subject) ExpiresAt() (time.Time, bool) {
+	if !s.IsAvailable() || s.AvailableDuration <= 0 || s.AvailableSince == 0 {
+		return time.Time{}, false
+	}
+	return s.AvailableSince.Time().AddDate(0, 0, s.AvailableDuration), true
 }
@@ -0,0 +1,57 @@
+package subjectmodel
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestExpiresAt(t *testing.T) {
+	since := time.Date(2026, 1, 31, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		subject Subject
+		want    time.Time
+		wantOK  bool
+	}{
+		{
+			name:    "available with a duration",
+			subject: Subject{SubjectStatus: StatusAvailable, AvailableDuration: 30, AvailableSince: primitive.NewDateTimeFromTime(since)},
+			want:    time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC),
+			wantOK:  true,
+		},
+		{
+			name:    "no status counts as available",
+			subject: Subject{AvailableDuration: 1, AvailableSince: primitive.NewDateTimeFromTime(since)},
+			want:    time.Date(2026, 2, 1, 8, 0, 0, 0, time.UTC),
+			wantOK:  true,
+		},
+		{
+			name:    "zero duration never expires",
+			subject: Subject{SubjectStatus: StatusAvailable, AvailableSince: primitive.NewDateTimeFromTime(since)},
+		},
+		{
+			name:    "no start date",
+			subject: Subject{SubjectStatus: StatusAvailable, AvailableDuration: 30},
+		},
+		{
+			name:    "suspended",
+			subject: Subject{SubjectStatus: StatusSuspended, AvailableDuration: 30, AvailableSince: primitive.NewDateTimeFromTime(since)},
+		},
+		{
+			name:    "draft",
+			subject: Subject{SubjectStatus: StatusDraft, AvailableDuration: 30, AvailableSince: primitive.NewDateTimeFromTime(since)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.subject.ExpiresAt()
+			if ok != tt.wantOK || !got.Equal(tt.want) {
+				t.Errorf("ExpiresAt() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
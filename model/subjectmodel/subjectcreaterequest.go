@@ -0,0 +1,47 @@
+package subjectmodel
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SubjectCreateRequest struct {
+	SubjectCode        string   `json:"subjectCode" validate:"required,subjectcode"`
+	Name               string   `json:"name" validate:"required,max=200"`
+	Professors         []string `json:"professors" validate:"max=20,dive,mongodb"`
+	SubjectDescription string   `json:"subjectDescription" validate:"max=5000"`
+	Campus             string   `json:"campus" validate:"max=100"`
+	Credit             *int     `json:"credit" validate:"required,min=0,max=30"`
+	PreRequisite       []string `json:"preRequisite" validate:"max=20,dive,required,max=50"`
+	CoRequisite        []string `json:"coRequisite" validate:"max=20,dive,required,max=50"`
+	SubjectStatus      string   `json:"subjectStatus" validate:"omitempty,subjectstatus"`
+	AvailableDuration  int      `json:"availableDuration" validate:"min=0"`
+	MajorID            string   `json:"majorId" validate:"required,mongodb"`
+}
+
+// ToSubject converts a validated request into the stored model.
+func (r SubjectCreateRequest) ToSubject() (Subject, error) {
+	professors := make([]primitive.ObjectID, 0, len(r.Professors))
+	for _, p := range r.Professors {
+		id, err := primitive.ObjectIDFromHex(p)
+		if err != nil {
+			return Subject{}, err
+		}
+		professors = append(professors, id)
+	}
+
+	subject := Subject{
+		SubjectCode:        r.SubjectCode,
+		Name:               r.Name,
+		Professors:         professors,
+		SubjectDescription: r.SubjectDescription,
+		Campus:             r.Campus,
+		PreRequisite:       r.PreRequisite,
+		CoRequisite:        r.CoRequisite,
+		SubjectStatus:      r.SubjectStatus,
+		AvailableDuration:  r.AvailableDuration,
+	}
+	if r.Credit != nil {
+		subject.Credit = *r.Credit
+	}
+	return subject, nil
+}
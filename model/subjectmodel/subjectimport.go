@@ -0,0 +1,114 @@
+package subjectmodel
+
+import (
+	"BackendCoursyclopedia/apperror"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SubjectImportRow is one subject read from a bulk import file. Major holds
+// either a major ID or a major name; MajorID, when set, takes precedence so
+// that exported files import back into the same majors.
+type SubjectImportRow struct {
+	SubjectCode        string   `json:"subjectCode" validate:"required,subjectcode"`
+	Name               string   `json:"name" validate:"required,max=200"`
+	Major              string   `json:"major" validate:"required_without=MajorID,max=200"`
+	MajorID            string   `json:"majorId" validate:"omitempty,mongodb"`
+	SubjectDescription string   `json:"subjectDescription" validate:"max=5000"`
+	Campus             string   `json:"campus" validate:"max=100"`
+	Credit             *int     `json:"credit" validate:"required,min=0,max=30"`
+	Professors         []string `json:"professors" validate:"max=20,dive,mongodb"`
+	PreRequisite       []string `json:"preRequisite" validate:"max=20,dive,required,max=50"`
+	CoRequisite        []string `json:"coRequisite" validate:"max=20,dive,required,max=50"`
+	SubjectStatus      string   `json:"subjectStatus" validate:"omitempty,subjectstatus"`
+	AvailableDuration  int      `json:"availableDuration" validate:"min=0"`
+
+	// Line is the 1-based row in the source file, header excluded.
+	Line int `json:"-"`
+	// ParseErrors holds cells that could not be converted, e.g. a
+	// non-numeric credit.
+	ParseErrors []apperror.FieldError `json:"-"`
+	// Columns holds the JSON names of the fields the source provides. An
+	// existing subject is updated only in those fields. Nil means all.
+	Columns map[string]bool `json:"-"`
+}
+
+// HasColumn reports whether the source provides field, named as in JSON.
+func (r SubjectImportRow) HasColumn(field string) bool {
+	return r.Columns == nil || r.Columns[field]
+}
+
+// ToSubject converts a validated row into the stored model.
+func (r SubjectImportRow) ToSubject() Subject {
+	professors := make([]primitive.ObjectID, 0, len(r.Professors))
+	for _, p := range r.Professors {
+		if id, err := primitive.ObjectIDFromHex(p); err == nil {
+			professors = append(professors, id)
+		}
+	}
+
+	subject := Subject{
+		SubjectCode:        r.SubjectCode,
+		Name:               r.Name,
+		Professors:         professors,
+		SubjectDescription: r.SubjectDescription,
+		Campus:             r.Campus,
+		PreRequisite:       r.PreRequisite,
+		CoRequisite:        r.CoRequisite,
+		SubjectStatus:      r.SubjectStatus,
+		AvailableDuration:  r.AvailableDuration,
+	}
+	if r.Credit != nil {
+		subject.Credit = *r.Credit
+	}
+	return subject
+}
+
+const (
+	ImportActionCreate  = "create"
+	ImportActionUpdate  = "update"
+	ImportActionInvalid = "invalid"
+)
+
+type SubjectImportOptions struct {
+	// DryRun validates every row and reports what would change without
+	// writing anything.
+	DryRun bool
+	// AllOrNothing applies the rows in one transaction: nothing is written
+	// if any row is invalid or any write fails. Without it, invalid rows are
+	// skipped and a row that fails to write does not stop the others.
+	AllOrNothing bool
+}
+
+type SubjectImportRowResult struct {
+	Line        int                   `json:"line"`
+	SubjectCode string                `json:"subjectCode"`
+	Action      string                `json:"action"`
+	SubjectID   string                `json:"subjectId,omitempty"`
+	MajorID     string                `json:"majorId,omitempty"`
+	Errors      []apperror.FieldError `json:"errors,omitempty"`
+	// Applied is set once the row has been written. Error holds the reason
+	// a valid row could not be written.
+	Applied bool   `json:"applied"`
+	Error   string `json:"error,omitempty"`
+}
+
+type SubjectImportReport struct {
+	DryRun       bool                     `json:"dryRun"`
+	AllOrNothing bool                     `json:"allOrNothing"`
+	Applied      bool                     `json:"applied"`
+	Created      int                      `json:"created"`
+	Updated      int                      `json:"updated"`
+	Invalid      int                      `json:"invalid"`
+	Failed       int                      `json:"failed"`
+	Rows         []SubjectImportRowResult `json:"rows"`
+}
+
+// SubjectExportRow is one subject in a catalogue export, placed in its
+// faculty and major. Its JSON form is a valid SubjectImportRow.
+type SubjectExportRow struct {
+	Faculty   string `json:"faculty"`
+	FacultyID string `json:"facultyId"`
+	SubjectID string `json:"subjectId"`
+	SubjectImportRow
+}
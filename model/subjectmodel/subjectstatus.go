@@ -0,0 +1,53 @@
+package subjectmodel
+
+// A subject starts as a DRAFT or AVAILABLE, can be SUSPENDED and made
+// AVAILABLE again, and ends ARCHIVED.
+const (
+	StatusDraft     = "DRAFT"
+	StatusAvailable = "AVAILABLE"
+	StatusSuspended = "SUSPENDED"
+	StatusArchived  = "ARCHIVED"
+)
+
+// Statuses lists every value accepted for Subject.SubjectStatus.
+var Statuses = []string{StatusDraft, StatusAvailable, StatusSuspended, StatusArchived}
+
+// transitions lists the statuses each status may change to. ARCHIVED is
+// final.
+var transitions = map[string][]string{
+	StatusDraft:     {StatusAvailable, StatusArchived},
+	StatusAvailable: {StatusSuspended, StatusArchived},
+	StatusSuspended: {StatusAvailable, StatusArchived},
+}
+
+func IsValidStatus(status string) bool {
+	for _, s := range Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// IsInitialStatus reports whether a new subject may be created with status.
+func IsInitialStatus(status string) bool {
+	return status == StatusDraft || status == StatusAvailable
+}
+
+// CanTransition reports whether a subject may change from one status to
+// another. Staying in the same status is always allowed. Subjects saved
+// before statuses were enforced may have none, which counts as AVAILABLE.
+func CanTransition(from, to string) bool {
+	if from == "" {
+		from = StatusAvailable
+	}
+	if from == to {
+		return true
+	}
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
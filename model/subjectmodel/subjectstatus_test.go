@@ -0,0 +1,37 @@
+package subjectmodel
+
+import "testing"
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{from: StatusDraft, to: StatusAvailable, want: true},
+		{from: StatusDraft, to: StatusArchived, want: true},
+		{from: StatusDraft, to: StatusSuspended},
+		{from: StatusAvailable, to: StatusSuspended, want: true},
+		{from: StatusAvailable, to: StatusArchived, want: true},
+		{from: StatusAvailable, to: StatusDraft},
+		{from: StatusSuspended, to: StatusAvailable, want: true},
+		{from: StatusSuspended, to: StatusArchived, want: true},
+		{from: StatusSuspended, to: StatusDraft},
+		{from: StatusArchived, to: StatusAvailable},
+		{from: StatusArchived, to: StatusSuspended},
+		{from: StatusArchived, to: StatusDraft},
+		{from: StatusArchived, to: StatusArchived, want: true},
+		{from: StatusAvailable, to: StatusAvailable, want: true},
+		// Subjects without a status count as AVAILABLE.
+		{from: "", to: StatusSuspended, want: true},
+		{from: "", to: StatusAvailable, want: true},
+		{from: "", to: StatusDraft},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"->"+tt.to, func(t *testing.T) {
+			if got := CanTransition(tt.from, tt.to); got != tt.want {
+				t.Errorf("CanTransition(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
@@ -5,14 +5,14 @@ import (
 )
 
 type SubjectUpdateRequest struct {
-	SubjectCode        string               `json:"subjectCode"`
-	Name               string               `json:"name"`
+	SubjectCode        string               `json:"subjectCode" validate:"omitempty,subjectcode"`
+	Name               string               `json:"name" validate:"max=200"`
 	Professors         []primitive.ObjectID `json:"professors"`
-	SubjectDescription string               `json:"subjectDescription"`
-	Campus             string               `json:"campus"`
-	Credit             *int                 `json:"credit"`
-	PreRequisite       *[]string            `json:"preRequisite"`
-	CoRequisite        *[]string            `json:"coRequisite"`
-	SubjectStatus      string               `json:"subjectStatus"`
-	AvailableDuration  *int                 `json:"availableDuration"`
+	SubjectDescription string               `json:"subjectDescription" validate:"max=5000"`
+	Campus             string               `json:"campus" validate:"max=100"`
+	Credit             *int                 `json:"credit" validate:"omitempty,min=0,max=30"`
+	PreRequisite       *[]string            `json:"preRequisite" validate:"omitempty,max=20,dive,required,max=50"`
+	CoRequisite        *[]string            `json:"coRequisite" validate:"omitempty,max=20,dive,required,max=50"`
+	SubjectStatus      string               `json:"subjectStatus" validate:"omitempty,subjectstatus"`
+	AvailableDuration  *int                 `json:"availableDuration" validate:"omitempty,min=0"`
 }
@@ -0,0 +1,18 @@
+package termmodel
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Term is one semester of an academic year. StartDate is the first day of
+// classes and EndDate the last day of the term, both as UTC midnight.
+type Term struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	AcademicYear int                `bson:"academicYear" json:"academicYear"`
+	Semester     int                `bson:"semester" json:"semester"`
+	Name         string             `bson:"name" json:"name"`
+	StartDate    time.Time          `bson:"startDate" json:"startDate"`
+	EndDate      time.Time          `bson:"endDate" json:"endDate"`
+}
@@ -0,0 +1,34 @@
+package termmodel
+
+import (
+	"fmt"
+	"time"
+)
+
+// DateLayout is the format of the dates in TermRequest.
+const DateLayout = "2006-01-02"
+
+type TermRequest struct {
+	AcademicYear int    `json:"academicYear" validate:"required,min=1950,max=2200"`
+	Semester     int    `json:"semester" validate:"required,min=1,max=3"`
+	Name         string `json:"name" validate:"max=100"`
+	StartDate    string `json:"startDate" validate:"required,datetime=2006-01-02"`
+	EndDate      string `json:"endDate" validate:"required,datetime=2006-01-02"`
+}
+
+// ToTerm converts a validated request. Name defaults to "<year>/<semester>".
+func (r TermRequest) ToTerm() Term {
+	start, _ := time.Parse(DateLayout, r.StartDate)
+	end, _ := time.Parse(DateLayout, r.EndDate)
+	name := r.Name
+	if name == "" {
+		name = fmt.Sprintf("%d/%d", r.AcademicYear, r.Semester)
+	}
+	return Term{
+		AcademicYear: r.AcademicYear,
+		Semester:     r.Semester,
+		Name:         name,
+		StartDate:    start,
+		EndDate:      end,
+	}
+}
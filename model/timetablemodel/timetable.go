@@ -0,0 +1,82 @@
+package timetablemodel
+
+import (
+	"BackendCoursyclopedia/model/sectionmodel"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ConflictMeetingOverlap   = "meeting_overlap"
+	ConflictExamClash        = "exam_clash"
+	ConflictDuplicateSubject = "duplicate_subject"
+)
+
+// SectionRef names a chosen section by its subject and section number.
+type SectionRef struct {
+	SectionID   primitive.ObjectID `json:"sectionId"`
+	Number      string             `json:"number"`
+	OfferingID  primitive.ObjectID `json:"offeringId"`
+	TermID      primitive.ObjectID `json:"termId"`
+	SubjectID   primitive.ObjectID `json:"subjectId"`
+	SubjectCode string             `json:"subjectCode"`
+	Campus      string             `json:"campus,omitempty"`
+}
+
+// Conflict is a clash between two chosen sections. Day, Start and End give
+// the overlapping weekly slot for meeting overlaps; Start and End give the
+// overlapping exam period, in RFC 3339, for exam clashes.
+type Conflict struct {
+	Kind     string       `json:"kind"`
+	Sections []SectionRef `json:"sections"`
+	Day      string       `json:"day,omitempty"`
+	Start    string       `json:"start,omitempty"`
+	End      string       `json:"end,omitempty"`
+	Message  string       `json:"message"`
+}
+
+// MissingCorequisite lists the co-requisites of a chosen subject that are
+// neither chosen for the same term nor already passed.
+type MissingCorequisite struct {
+	Section SectionRef `json:"section"`
+	Missing []string   `json:"missing"`
+}
+
+type Report struct {
+	Valid               bool                 `json:"valid"`
+	Sections            []SectionRef         `json:"sections"`
+	Conflicts           []Conflict           `json:"conflicts"`
+	MissingCorequisites []MissingCorequisite `json:"missingCorequisites"`
+}
+
+// ScheduledSection is a section of a generated timetable with its meetings.
+type ScheduledSection struct {
+	SectionRef
+	Meetings []sectionmodel.Meeting `json:"meetings"`
+}
+
+// Timetable is one conflict-free choice of a section per subject. Score is
+// 0 when every preference is met and falls by a penalty for each one that
+// is not; Unmet lists those preferences.
+type Timetable struct {
+	Score    int                `json:"score"`
+	Sections []ScheduledSection `json:"sections"`
+	Unmet    []string           `json:"unmet"`
+}
+
+const (
+	StopTimeLimit   = "time_limit"
+	StopSearchLimit = "search_limit"
+)
+
+// GenerateResult holds the best timetables found. Complete is false when the
+// search stopped early, as named by StoppedBy, so better timetables may
+// exist.
+type GenerateResult struct {
+	Timetables          []Timetable          `json:"timetables"`
+	Found               int                  `json:"found"`
+	Explored            int                  `json:"explored"`
+	Complete            bool                 `json:"complete"`
+	StoppedBy           string               `json:"stoppedBy,omitempty"`
+	MissingCorequisites []MissingCorequisite `json:"missingCorequisites"`
+}
@@ -0,0 +1,27 @@
+package timetablemodel
+
+type CheckRequest struct {
+	Sections []string `json:"sections" validate:"required,min=1,max=30,dive,mongodb"`
+}
+
+// Preferences are soft: timetables that break them are still returned, but
+// rank lower.
+type Preferences struct {
+	// NoClassesBefore is a "15:04" time before which no meeting should start.
+	NoClassesBefore string   `json:"noClassesBefore" validate:"omitempty,datetime=15:04"`
+	FreeDays        []string `json:"freeDays" validate:"max=7,dive,oneof=monday tuesday wednesday thursday friday saturday sunday"`
+	Campus          string   `json:"campus" validate:"max=100"`
+}
+
+type GenerateRequest struct {
+	TermID      string      `json:"termId" validate:"required,mongodb"`
+	Subjects    []string    `json:"subjects" validate:"required,min=1,max=12,dive,mongodb"`
+	Preferences Preferences `json:"preferences"`
+	Limit       int         `json:"limit" validate:"min=0,max=50"`
+	TimeLimitMs int         `json:"timeLimitMs" validate:"min=0,max=10000"`
+}
+
+// SaveRequest replaces the user's chosen sections; an empty list clears them.
+type SaveRequest struct {
+	Sections []string `json:"sections" validate:"max=30,dive,mongodb"`
+}
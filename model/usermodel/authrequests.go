@@ -0,0 +1,21 @@
+package usermodel
+
+// The requests of the password reset and email verification flows. Tokens
+// are the ones emailed in the links.
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email,max=254"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required,max=128"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required,max=128"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email,max=254"`
+}
@@ -0,0 +1,11 @@
+package usermodel
+
+import "github.com/golang-jwt/jwt/v4"
+
+// TokenClaims are the claims of the JWTs issued at login. TokenVersion is
+// the user's TokenVersion when the token was issued; raising the user's
+// version revokes every token issued before.
+type TokenClaims struct {
+	jwt.RegisteredClaims
+	TokenVersion int `json:"ver"`
+}
@@ -0,0 +1,58 @@
+package usermodel
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CompletedSubject is one attempt at a subject. A subject taken again after
+// a failing grade appears once per attempt.
+type CompletedSubject struct {
+	SubjectID primitive.ObjectID `bson:"subjectId" json:"subjectId"`
+	Term      string             `bson:"term" json:"term"`
+	Grade     string             `bson:"grade" json:"grade"`
+}
+
+// Grades lists every grade a completed subject may carry.
+var Grades = []string{"A", "A-", "B+", "B", "B-", "C+", "C", "C-", "D+", "D", "F", "S", "U", "W", "I"}
+
+// IsPassingGrade reports whether grade earns the subject's credits.
+func IsPassingGrade(grade string) bool {
+	switch grade {
+	case "F", "U", "W", "I":
+		return false
+	}
+	for _, g := range Grades {
+		if g == grade {
+			return true
+		}
+	}
+	return false
+}
+
+type CompletedSubjectRequest struct {
+	SubjectID string `json:"subjectId" validate:"required,mongodb"`
+	Term      string `json:"term" validate:"required,max=20"`
+	Grade     string `json:"grade" validate:"required,oneof=A A- B+ B B- C+ C C- D+ D F S U W I"`
+}
+
+type CompletedSubjectsRequest struct {
+	Subjects []CompletedSubjectRequest `json:"subjects" validate:"max=300,dive"`
+}
+
+// ToCompletedSubjects converts a validated request into the stored model.
+func (r CompletedSubjectsRequest) ToCompletedSubjects() ([]CompletedSubject, error) {
+	subjects := make([]CompletedSubject, len(r.Subjects))
+	for i, s := range r.Subjects {
+		id, err := primitive.ObjectIDFromHex(s.SubjectID)
+		if err != nil {
+			return nil, err
+		}
+		subjects[i] = CompletedSubject{SubjectID: id, Term: s.Term, Grade: s.Grade}
+	}
+	return subjects, nil
+}
+
+type ProgramRequest struct {
+	MajorID    string `json:"majorId" validate:"required,mongodb"`
+	IntakeYear int    `json:"intakeYear" validate:"required,min=1950,max=2200"`
+}
@@ -0,0 +1,52 @@
+package usermodel
+
+// NotificationPreferences is how a user wants to be emailed. The zero value
+// sends every notification in the default language.
+type NotificationPreferences struct {
+	// Locale picks the language of emails, e.g. "en" or "th-TH". Locales
+	// without templates fall back to the default.
+	Locale string `bson:"locale,omitempty" json:"locale"`
+	// Muted lists the optional notification kinds the user does not want.
+	// Notifications about the account itself are always sent.
+	Muted []string `bson:"muted,omitempty" json:"muted"`
+}
+
+// NotificationPreferences returns the user's preferences, or the defaults
+// if they never set any.
+func (u User) NotificationPreferences() NotificationPreferences {
+	if u.Notifications == nil {
+		return NotificationPreferences{Muted: []string{}}
+	}
+	prefs := *u.Notifications
+	if prefs.Muted == nil {
+		prefs.Muted = []string{}
+	}
+	return prefs
+}
+
+// Wants reports whether the user has not muted kind.
+func (p NotificationPreferences) Wants(kind string) bool {
+	for _, muted := range p.Muted {
+		if muted == kind {
+			return false
+		}
+	}
+	return true
+}
+
+type NotificationPreferencesRequest struct {
+	Locale string   `json:"locale" validate:"omitempty,bcp47_language_tag"`
+	Muted  []string `json:"muted" validate:"max=10,dive,oneof=subject_status"`
+}
+
+func (r NotificationPreferencesRequest) ToPreferences() NotificationPreferences {
+	muted := []string{}
+	seen := map[string]bool{}
+	for _, kind := range r.Muted {
+		if !seen[kind] {
+			seen[kind] = true
+			muted = append(muted, kind)
+		}
+	}
+	return NotificationPreferences{Locale: r.Locale, Muted: muted}
+}
@@ -0,0 +1,33 @@
+package usermodel
+
+import "time"
+
+const (
+	// StatusPending is a self-registered user who has not confirmed their
+	// email address yet and cannot sign in.
+	StatusPending = "pending"
+	StatusActive  = "active"
+	// StatusSuspended is set by an admin. The user cannot sign in and their
+	// existing tokens stop working until an admin reactivates them.
+	StatusSuspended = "suspended"
+	// StatusDeleted is a closed account, kept for its history. It is treated
+	// as if it did not exist.
+	StatusDeleted = "deleted"
+)
+
+// IsActive reports whether the user may sign in. Users stored before
+// statuses existed have none until migration 3 runs, and count as active.
+func (u User) IsActive() bool {
+	return u.Status == StatusActive || u.Status == ""
+}
+
+// StatusChange records who last changed a user's status and why.
+type StatusChange struct {
+	Reason    string    `bson:"reason" json:"reason"`
+	ChangedBy string    `bson:"changedBy" json:"changedBy"`
+	ChangedAt time.Time `bson:"changedAt" json:"changedAt"`
+}
+
+type StatusChangeRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
@@ -63,5 +63,23 @@ type User struct {
 		FirebaseId string `bson:"firebaseId"`
 	} `bson:"profile"`
 	FacultyID primitive.ObjectID `bson:"facultyId,omitempty"`
-	Status    string             `bson:"status"`
+	// Status is one of the Status constants; only SetUserStatus changes it.
+	Status string `bson:"status,omitempty"`
+	// StatusChange explains the last suspension, reactivation or deletion.
+	StatusChange *StatusChange `bson:"statusChange,omitempty" json:"statusChange,omitempty"`
+	// TokenVersion is raised to revoke every token the user holds; see
+	// TokenClaims.
+	TokenVersion int `bson:"tokenVersion,omitempty" json:"-"`
+	// MajorID and IntakeYear select the curriculum the user is audited
+	// against.
+	MajorID           primitive.ObjectID `bson:"majorId,omitempty"`
+	IntakeYear        int                `bson:"intakeYear,omitempty"`
+	CompletedSubjects []CompletedSubject `bson:"completedSubjects,omitempty"`
+	// TimetableSections are the sections the user has chosen to attend.
+	TimetableSections []primitive.ObjectID `bson:"timetableSections,omitempty"`
+	// CalendarTokenHash is the SHA-256 of the token in the user's calendar
+	// subscription URL; the token itself is never stored.
+	CalendarTokenHash string `bson:"calendarTokenHash,omitempty" json:"-"`
+	// Notifications is nil until the user saves preferences.
+	Notifications *NotificationPreferences `bson:"notifications,omitempty"`
 }
@@ -0,0 +1,30 @@
+package usermodel
+
+type UserCreateRequest struct {
+	Email       string `json:"email" validate:"required,email,max=254"`
+	Password    string `json:"password" validate:"required,min=8,max=72"`
+	PhoneNumber string `json:"phoneNumber" validate:"max=32"`
+	Profile     struct {
+		FirstName string `json:"firstName" validate:"max=100"`
+		LastName  string `json:"lastName" validate:"max=100"`
+	} `json:"profile"`
+}
+
+// ToUser converts a validated request into the stored model with the
+// default user role.
+func (r UserCreateRequest) ToUser() User {
+	user := User{
+		Email:       r.Email,
+		Password:    r.Password,
+		PhoneNumber: r.PhoneNumber,
+		Role: Role{
+			Name:        "user",
+			Slug:        "user",
+			Description: "Default user role",
+			Permissions: []string{},
+		},
+	}
+	user.Profile.FirstName = r.Profile.FirstName
+	user.Profile.LastName = r.Profile.LastName
+	return user
+}
@@ -0,0 +1,13 @@
+package usermodel
+
+// UserUpdateRequest holds the fields a user may change on their own
+// account. Empty fields are left unchanged. The role, status, programme,
+// timetable and preferences have their own endpoints.
+type UserUpdateRequest struct {
+	PhoneNumber string `json:"phoneNumber" validate:"max=32"`
+	Profile     struct {
+		FirstName string `json:"firstName" validate:"max=100"`
+		LastName  string `json:"lastName" validate:"max=100"`
+	} `json:"profile"`
+	Password string `json:"password" validate:"omitempty,min=8,max=72"`
+}
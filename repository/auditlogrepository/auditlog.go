@@ -1,6 +1,7 @@
 package auditlogrepo
 
 import (
+	"BackendCoursyclopedia/apperror"
 	"BackendCoursyclopedia/db"
 	"BackendCoursyclopedia/model/auditlogmodel"
 	"context"
@@ -52,11 +53,11 @@ func (r *AuditLogRepository) FindAuditLogByID(ctx context.Context, auditlogId st
 
 	objID, err := primitive.ObjectIDFromHex(auditlogId)
 	if err != nil {
-		return nil, err
+		return nil, apperror.InvalidID("audit log", err)
 	}
 	err = collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&auditlog)
 	if err != nil {
-		return nil, err
+		return nil, apperror.FromMongo(err, "audit log")
 	}
 	return &auditlog, nil
 
@@ -0,0 +1,31 @@
+package auditlogrepo
+
+import (
+	"BackendCoursyclopedia/instrumentation"
+	"BackendCoursyclopedia/model/auditlogmodel"
+	"context"
+)
+
+type instrumentedAuditLogRepository struct {
+	next IAuditLogRepository
+}
+
+// NewInstrumentedAuditLogRepository wraps next so every call records MongoDB
+// latency and errors under the "auditlogs" repository label.
+func NewInstrumentedAuditLogRepository(next IAuditLogRepository) IAuditLogRepository {
+	return &instrumentedAuditLogRepository{next: next}
+}
+
+func (r *instrumentedAuditLogRepository) FindAllAuditLogs(ctx context.Context) ([]auditlogmodel.AuditLog, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "auditlogs", "FindAllAuditLogs")
+	result, err := r.next.FindAllAuditLogs(ctx)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedAuditLogRepository) FindAuditLogByID(ctx context.Context, auditlogId string) (*auditlogmodel.AuditLog, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "auditlogs", "FindAuditLogByID")
+	result, err := r.next.FindAuditLogByID(ctx, auditlogId)
+	done(err)
+	return result, err
+}
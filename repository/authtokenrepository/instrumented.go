@@ -0,0 +1,48 @@
+package authtokenrepository
+
+import (
+	"BackendCoursyclopedia/instrumentation"
+	"BackendCoursyclopedia/model/authtokenmodel"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type instrumentedAuthTokenRepository struct {
+	next IAuthTokenRepository
+}
+
+// NewInstrumentedAuthTokenRepository wraps next so every call records MongoDB
+// latency and errors under the "authtokens" repository label.
+func NewInstrumentedAuthTokenRepository(next IAuthTokenRepository) IAuthTokenRepository {
+	return &instrumentedAuthTokenRepository{next: next}
+}
+
+func (r *instrumentedAuthTokenRepository) CreateToken(ctx context.Context, token authtokenmodel.Token) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "authtokens", "CreateToken")
+	err := r.next.CreateToken(ctx, token)
+	done(err)
+	return err
+}
+
+func (r *instrumentedAuthTokenRepository) ConsumeToken(ctx context.Context, purpose, hash string, now time.Time) (*authtokenmodel.Token, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "authtokens", "ConsumeToken")
+	result, err := r.next.ConsumeToken(ctx, purpose, hash, now)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedAuthTokenRepository) CountTokensSince(ctx context.Context, userID primitive.ObjectID, purpose string, since time.Time) (int64, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "authtokens", "CountTokensSince")
+	result, err := r.next.CountTokensSince(ctx, userID, purpose, since)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedAuthTokenRepository) DeleteTokens(ctx context.Context, userID primitive.ObjectID, purpose string) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "authtokens", "DeleteTokens")
+	err := r.next.DeleteTokens(ctx, userID, purpose)
+	done(err)
+	return err
+}
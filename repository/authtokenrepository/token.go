@@ -0,0 +1,66 @@
+package authtokenrepository
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/db"
+	"BackendCoursyclopedia/model/authtokenmodel"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type IAuthTokenRepository interface {
+	CreateToken(ctx context.Context, token authtokenmodel.Token) error
+	ConsumeToken(ctx context.Context, purpose, hash string, now time.Time) (*authtokenmodel.Token, error)
+	CountTokensSince(ctx context.Context, userID primitive.ObjectID, purpose string, since time.Time) (int64, error)
+	DeleteTokens(ctx context.Context, userID primitive.ObjectID, purpose string) error
+}
+
+type AuthTokenRepository struct {
+	DB *mongo.Client
+}
+
+func NewAuthTokenRepository(db *mongo.Client) IAuthTokenRepository {
+	return &AuthTokenRepository{
+		DB: db,
+	}
+}
+
+func (r *AuthTokenRepository) CreateToken(ctx context.Context, token authtokenmodel.Token) error {
+	collection := db.GetCollection("authtokens")
+
+	_, err := collection.InsertOne(ctx, token)
+	return apperror.FromMongo(err, "token")
+}
+
+// ConsumeToken deletes and returns the unexpired token with the given hash,
+// so each token can be used once. It returns NotFound for unknown, used and
+// expired tokens alike.
+func (r *AuthTokenRepository) ConsumeToken(ctx context.Context, purpose, hash string, now time.Time) (*authtokenmodel.Token, error) {
+	collection := db.GetCollection("authtokens")
+	var token authtokenmodel.Token
+
+	filter := bson.M{"purpose": purpose, "hash": hash, "expiresAt": bson.M{"$gt": now}}
+	if err := collection.FindOneAndDelete(ctx, filter).Decode(&token); err != nil {
+		return nil, apperror.FromMongo(err, "token")
+	}
+	return &token, nil
+}
+
+// CountTokensSince counts the tokens issued to the user for purpose since
+// the given time that have not been used yet.
+func (r *AuthTokenRepository) CountTokensSince(ctx context.Context, userID primitive.ObjectID, purpose string, since time.Time) (int64, error) {
+	collection := db.GetCollection("authtokens")
+
+	return collection.CountDocuments(ctx, bson.M{"userId": userID, "purpose": purpose, "createdAt": bson.M{"$gte": since}})
+}
+
+func (r *AuthTokenRepository) DeleteTokens(ctx context.Context, userID primitive.ObjectID, purpose string) error {
+	collection := db.GetCollection("authtokens")
+
+	_, err := collection.DeleteMany(ctx, bson.M{"userId": userID, "purpose": purpose})
+	return err
+}
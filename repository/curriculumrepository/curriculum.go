@@ -0,0 +1,99 @@
+package curriculumrepository
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/db"
+	"BackendCoursyclopedia/model/curriculummodel"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ICurriculumRepository interface {
+	CreateCurriculum(ctx context.Context, curriculum curriculummodel.Curriculum) (primitive.ObjectID, error)
+	FindCurricula(ctx context.Context, majorID primitive.ObjectID) ([]curriculummodel.Curriculum, error)
+	FindCurriculum(ctx context.Context, majorID primitive.ObjectID, intakeYear, version int) (*curriculummodel.Curriculum, error)
+	FindApplicableCurriculum(ctx context.Context, majorID primitive.ObjectID, intakeYear int) (*curriculummodel.Curriculum, error)
+	CountCurriculaBySubject(ctx context.Context, subjectID primitive.ObjectID) (int64, error)
+}
+
+type CurriculumRepository struct {
+	DB *mongo.Client
+}
+
+func NewCurriculumRepository(db *mongo.Client) ICurriculumRepository {
+	return &CurriculumRepository{
+		DB: db,
+	}
+}
+
+func (r *CurriculumRepository) CreateCurriculum(ctx context.Context, curriculum curriculummodel.Curriculum) (primitive.ObjectID, error) {
+	collection := db.GetCollection("curricula")
+
+	result, err := collection.InsertOne(ctx, curriculum)
+	if err != nil {
+		return primitive.NilObjectID, apperror.FromMongo(err, "curriculum")
+	}
+	return result.InsertedID.(primitive.ObjectID), nil
+}
+
+// FindCurricula returns every version of every intake year of a major,
+// newest intake year and version first.
+func (r *CurriculumRepository) FindCurricula(ctx context.Context, majorID primitive.ObjectID) ([]curriculummodel.Curriculum, error) {
+	collection := db.GetCollection("curricula")
+	curricula := []curriculummodel.Curriculum{}
+
+	opts := options.Find().SetSort(bson.D{{Key: "intakeYear", Value: -1}, {Key: "version", Value: -1}})
+	cursor, err := collection.Find(ctx, bson.M{"majorId": majorID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &curricula); err != nil {
+		return nil, err
+	}
+	return curricula, nil
+}
+
+// FindCurriculum returns one version of a major's curriculum for an intake
+// year, or the latest version when version is 0.
+func (r *CurriculumRepository) FindCurriculum(ctx context.Context, majorID primitive.ObjectID, intakeYear, version int) (*curriculummodel.Curriculum, error) {
+	collection := db.GetCollection("curricula")
+	var curriculum curriculummodel.Curriculum
+
+	filter := bson.M{"majorId": majorID, "intakeYear": intakeYear}
+	if version > 0 {
+		filter["version"] = version
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}})
+	if err := collection.FindOne(ctx, filter, opts).Decode(&curriculum); err != nil {
+		return nil, apperror.FromMongo(err, "curriculum")
+	}
+	return &curriculum, nil
+}
+
+// FindApplicableCurriculum returns the latest version of the curriculum that
+// applies to students who started in intakeYear: the one for the most recent
+// intake year not after it.
+func (r *CurriculumRepository) FindApplicableCurriculum(ctx context.Context, majorID primitive.ObjectID, intakeYear int) (*curriculummodel.Curriculum, error) {
+	collection := db.GetCollection("curricula")
+	var curriculum curriculummodel.Curriculum
+
+	filter := bson.M{"majorId": majorID, "intakeYear": bson.M{"$lte": intakeYear}}
+	opts := options.FindOne().SetSort(bson.D{{Key: "intakeYear", Value: -1}, {Key: "version", Value: -1}})
+	if err := collection.FindOne(ctx, filter, opts).Decode(&curriculum); err != nil {
+		return nil, apperror.FromMongo(err, "curriculum")
+	}
+	return &curriculum, nil
+}
+
+// CountCurriculaBySubject counts curriculum versions, current or not, that
+// list the subject.
+func (r *CurriculumRepository) CountCurriculaBySubject(ctx context.Context, subjectID primitive.ObjectID) (int64, error) {
+	collection := db.GetCollection("curricula")
+	return collection.CountDocuments(ctx, bson.M{"groups.subjects.subjectId": subjectID})
+}
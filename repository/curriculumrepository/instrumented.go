@@ -0,0 +1,54 @@
+package curriculumrepository
+
+import (
+	"BackendCoursyclopedia/instrumentation"
+	"BackendCoursyclopedia/model/curriculummodel"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type instrumentedCurriculumRepository struct {
+	next ICurriculumRepository
+}
+
+// NewInstrumentedCurriculumRepository wraps next so every call records MongoDB
+// latency and errors under the "curricula" repository label.
+func NewInstrumentedCurriculumRepository(next ICurriculumRepository) ICurriculumRepository {
+	return &instrumentedCurriculumRepository{next: next}
+}
+
+func (r *instrumentedCurriculumRepository) CreateCurriculum(ctx context.Context, curriculum curriculummodel.Curriculum) (primitive.ObjectID, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "curricula", "CreateCurriculum")
+	result, err := r.next.CreateCurriculum(ctx, curriculum)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedCurriculumRepository) FindCurricula(ctx context.Context, majorID primitive.ObjectID) ([]curriculummodel.Curriculum, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "curricula", "FindCurricula")
+	result, err := r.next.FindCurricula(ctx, majorID)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedCurriculumRepository) FindCurriculum(ctx context.Context, majorID primitive.ObjectID, intakeYear, version int) (*curriculummodel.Curriculum, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "curricula", "FindCurriculum")
+	result, err := r.next.FindCurriculum(ctx, majorID, intakeYear, version)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedCurriculumRepository) FindApplicableCurriculum(ctx context.Context, majorID primitive.ObjectID, intakeYear int) (*curriculummodel.Curriculum, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "curricula", "FindApplicableCurriculum")
+	result, err := r.next.FindApplicableCurriculum(ctx, majorID, intakeYear)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedCurriculumRepository) CountCurriculaBySubject(ctx context.Context, subjectID primitive.ObjectID) (int64, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "curricula", "CountCurriculaBySubject")
+	result, err := r.next.CountCurriculaBySubject(ctx, subjectID)
+	done(err)
+	return result, err
+}
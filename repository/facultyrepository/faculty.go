@@ -1,21 +1,22 @@
 package facultyrepository
 
 import (
+	"BackendCoursyclopedia/apperror"
 	"BackendCoursyclopedia/db"
 	"BackendCoursyclopedia/model/facultymodel"
 	"context"
-	"errors"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type IFacultyRepository interface {
 	FindAllFaculties(ctx context.Context) ([]facultymodel.Faculty, error)
 	FindFacultyByID(ctx context.Context, facultyID string) (*facultymodel.Faculty, error)
 	CreateFaculty(ctx context.Context, facultyName string, image []byte) (facultymodel.Faculty, error)
-	UpdateFaculty(ctx context.Context, facultyID string, faculty facultymodel.Faculty, image []byte) (facultymodel.Faculty, error)
+	UpdateFaculty(ctx context.Context, facultyID string, facultyName string, image []byte) (facultymodel.Faculty, error)
 	DeleteFaculty(ctx context.Context, facultyID string) error
 	AddMajorToFaculty(ctx context.Context, facultyId string, majorId string) error
 	RemoveMajorFromFaculty(ctx context.Context, majorId primitive.ObjectID) error
@@ -60,12 +61,12 @@ func (r *FacultyRepository) FindFacultyByID(ctx context.Context, facultyID strin
 
 	objID, err := primitive.ObjectIDFromHex(facultyID)
 	if err != nil {
-		return nil, err
+		return nil, apperror.InvalidID("faculty", err)
 	}
 
 	err = collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&faculty)
 	if err != nil {
-		return nil, err
+		return nil, apperror.FromMongo(err, "faculty")
 	}
 	return &faculty, nil
 }
@@ -80,31 +81,30 @@ func (r FacultyRepository) CreateFaculty(ctx context.Context, facultyName string
 	}
 	_, err := collection.InsertOne(ctx, Faculty)
 	if err != nil {
-		return facultymodel.Faculty{}, err
+		return facultymodel.Faculty{}, apperror.FromMongo(err, "faculty")
 	}
 
 	return Faculty, nil
 }
 
-func (r FacultyRepository) UpdateFaculty(ctx context.Context, facultyID string, faculty facultymodel.Faculty, image []byte) (facultymodel.Faculty, error) {
+// UpdateFaculty renames the faculty and replaces its image if one is given,
+// and returns the updated faculty. Its majors are left alone.
+func (r FacultyRepository) UpdateFaculty(ctx context.Context, facultyID string, facultyName string, image []byte) (facultymodel.Faculty, error) {
 	collection := db.GetCollection("faculties")
 	objID, err := primitive.ObjectIDFromHex(facultyID)
 	if err != nil {
-		return facultymodel.Faculty{}, err
+		return facultymodel.Faculty{}, apperror.InvalidID("faculty", err)
 	}
 
-	updateData := bson.M{"$set": faculty}
+	set := bson.M{"facultyName": facultyName}
 	if image != nil {
-		updateData["$set"].(bson.M)["image"] = image
+		set["image"] = image
 	}
 
-	filter := bson.M{"_id": objID}
-	result, err := collection.UpdateOne(ctx, filter, updateData)
-	if err != nil {
-		return facultymodel.Faculty{}, err
-	}
-	if result.MatchedCount == 0 {
-		return facultymodel.Faculty{}, errors.New("no faculty found with given ID")
+	var faculty facultymodel.Faculty
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	if err := collection.FindOneAndUpdate(ctx, bson.M{"_id": objID}, bson.M{"$set": set}, opts).Decode(&faculty); err != nil {
+		return facultymodel.Faculty{}, apperror.FromMongo(err, "faculty")
 	}
 
 	return faculty, nil
@@ -114,7 +114,7 @@ func (r FacultyRepository) DeleteFaculty(ctx context.Context, facultyID string)
 	collection := db.GetCollection("faculties")
 	objID, err := primitive.ObjectIDFromHex(facultyID)
 	if err != nil {
-		return err
+		return apperror.InvalidID("faculty", err)
 	}
 	filter := bson.M{"_id": objID}
 	result, err := collection.DeleteOne(ctx, filter)
@@ -122,7 +122,7 @@ func (r FacultyRepository) DeleteFaculty(ctx context.Context, facultyID string)
 		return err
 	}
 	if result.DeletedCount == 0 {
-		return errors.New("no faculty found with given ID")
+		return apperror.NotFound("faculty not found")
 	}
 
 	return nil
@@ -133,11 +133,11 @@ func (r *FacultyRepository) AddMajorToFaculty(ctx context.Context, facultyId str
 
 	fid, err := primitive.ObjectIDFromHex(facultyId)
 	if err != nil {
-		return err
+		return apperror.InvalidID("faculty", err)
 	}
 	mid, err := primitive.ObjectIDFromHex(majorId)
 	if err != nil {
-		return err
+		return apperror.InvalidID("major", err)
 	}
 
 	filter := bson.M{"_id": fid}
@@ -148,16 +148,20 @@ func (r *FacultyRepository) AddMajorToFaculty(ctx context.Context, facultyId str
 	}
 
 	if result.MatchedCount == 0 {
-		return mongo.ErrNoDocuments
+		return apperror.NotFound("faculty not found")
 	}
 
 	return nil
 }
 
+// RemoveMajorFromFaculty pulls the major from every faculty that lists it.
+// A major should have one faculty, but legacy data and failed moves can
+// leave it in several. Deleting a major or pruning a dangling one must
+// clear all of those references, not just the first one found.
 func (r *FacultyRepository) RemoveMajorFromFaculty(ctx context.Context, majorId primitive.ObjectID) error {
 	collection := db.GetCollection("faculties")
 
-	_, err := collection.UpdateOne(
+	_, err := collection.UpdateMany(
 		ctx,
 		bson.M{"majorIDs": majorId},
 		bson.M{"$pull": bson.M{"majorIDs": majorId}},
@@ -172,7 +176,7 @@ func (r *FacultyRepository) FindFacultyByMajorId(ctx context.Context, majorId pr
 	filter := bson.M{"majorIDs": majorId}
 	err := collection.FindOne(ctx, filter).Decode(&faculty)
 	if err != nil {
-		return facultymodel.Faculty{}, err
+		return facultymodel.Faculty{}, apperror.FromMongo(err, "faculty")
 	}
 
 	return faculty, nil
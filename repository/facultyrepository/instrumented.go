@@ -0,0 +1,82 @@
+package facultyrepository
+
+import (
+	"BackendCoursyclopedia/instrumentation"
+	"BackendCoursyclopedia/model/facultymodel"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type instrumentedFacultyRepository struct {
+	next IFacultyRepository
+}
+
+// NewInstrumentedFacultyRepository wraps next so every call records MongoDB
+// latency and errors under the "faculties" repository label.
+func NewInstrumentedFacultyRepository(next IFacultyRepository) IFacultyRepository {
+	return &instrumentedFacultyRepository{next: next}
+}
+
+func (r *instrumentedFacultyRepository) FindAllFaculties(ctx context.Context) ([]facultymodel.Faculty, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "faculties", "FindAllFaculties")
+	result, err := r.next.FindAllFaculties(ctx)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedFacultyRepository) FindFacultyByID(ctx context.Context, facultyID string) (*facultymodel.Faculty, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "faculties", "FindFacultyByID")
+	result, err := r.next.FindFacultyByID(ctx, facultyID)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedFacultyRepository) CreateFaculty(ctx context.Context, facultyName string, image []byte) (facultymodel.Faculty, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "faculties", "CreateFaculty")
+	result, err := r.next.CreateFaculty(ctx, facultyName, image)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedFacultyRepository) UpdateFaculty(ctx context.Context, facultyID string, facultyName string, image []byte) (facultymodel.Faculty, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "faculties", "UpdateFaculty")
+	result, err := r.next.UpdateFaculty(ctx, facultyID, facultyName, image)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedFacultyRepository) DeleteFaculty(ctx context.Context, facultyID string) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "faculties", "DeleteFaculty")
+	err := r.next.DeleteFaculty(ctx, facultyID)
+	done(err)
+	return err
+}
+
+func (r *instrumentedFacultyRepository) AddMajorToFaculty(ctx context.Context, facultyId string, majorId string) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "faculties", "AddMajorToFaculty")
+	err := r.next.AddMajorToFaculty(ctx, facultyId, majorId)
+	done(err)
+	return err
+}
+
+func (r *instrumentedFacultyRepository) RemoveMajorFromFaculty(ctx context.Context, majorId primitive.ObjectID) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "faculties", "RemoveMajorFromFaculty")
+	err := r.next.RemoveMajorFromFaculty(ctx, majorId)
+	done(err)
+	return err
+}
+
+func (r *instrumentedFacultyRepository) FindFacultyByMajorId(ctx context.Context, majorId primitive.ObjectID) (facultymodel.Faculty, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "faculties", "FindFacultyByMajorId")
+	result, err := r.next.FindFacultyByMajorId(ctx, majorId)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedFacultyRepository) UpdateFacultyForMajor(ctx context.Context, majorId primitive.ObjectID, currentFacultyId primitive.ObjectID, newFacultyId primitive.ObjectID) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "faculties", "UpdateFacultyForMajor")
+	err := r.next.UpdateFacultyForMajor(ctx, majorId, currentFacultyId, newFacultyId)
+	done(err)
+	return err
+}
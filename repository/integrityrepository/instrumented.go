@@ -0,0 +1,40 @@
+package integrityrepository
+
+import (
+	"BackendCoursyclopedia/instrumentation"
+	"BackendCoursyclopedia/model/integritymodel"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type instrumentedIntegrityRepository struct {
+	next IIntegrityRepository
+}
+
+// NewInstrumentedIntegrityRepository wraps next so every call records MongoDB
+// latency and errors under the "integrity" repository label.
+func NewInstrumentedIntegrityRepository(next IIntegrityRepository) IIntegrityRepository {
+	return &instrumentedIntegrityRepository{next: next}
+}
+
+func (r *instrumentedIntegrityRepository) SaveReport(ctx context.Context, report integritymodel.Report) (primitive.ObjectID, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "integrity", "SaveReport")
+	result, err := r.next.SaveReport(ctx, report)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedIntegrityRepository) FindLatestReport(ctx context.Context) (*integritymodel.Report, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "integrity", "FindLatestReport")
+	result, err := r.next.FindLatestReport(ctx)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedIntegrityRepository) PullReference(ctx context.Context, collection string, parentID primitive.ObjectID, field string, refID primitive.ObjectID) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "integrity", "PullReference")
+	err := r.next.PullReference(ctx, collection, parentID, field, refID)
+	done(err)
+	return err
+}
@@ -0,0 +1,60 @@
+package integrityrepository
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/db"
+	"BackendCoursyclopedia/model/integritymodel"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IIntegrityRepository interface {
+	SaveReport(ctx context.Context, report integritymodel.Report) (primitive.ObjectID, error)
+	FindLatestReport(ctx context.Context) (*integritymodel.Report, error)
+	PullReference(ctx context.Context, collection string, parentID primitive.ObjectID, field string, refID primitive.ObjectID) error
+}
+
+type IntegrityRepository struct {
+	DB *mongo.Client
+}
+
+func NewIntegrityRepository(db *mongo.Client) IIntegrityRepository {
+	return &IntegrityRepository{
+		DB: db,
+	}
+}
+
+func (r *IntegrityRepository) SaveReport(ctx context.Context, report integritymodel.Report) (primitive.ObjectID, error) {
+	collection := db.GetCollection("integrityreports")
+
+	result, err := collection.InsertOne(ctx, report)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	return result.InsertedID.(primitive.ObjectID), nil
+}
+
+func (r *IntegrityRepository) FindLatestReport(ctx context.Context) (*integritymodel.Report, error) {
+	collection := db.GetCollection("integrityreports")
+	var report integritymodel.Report
+
+	opts := options.FindOne().SetSort(bson.D{{Key: "finishedAt", Value: -1}})
+	if err := collection.FindOne(ctx, bson.M{}, opts).Decode(&report); err != nil {
+		return nil, apperror.FromMongo(err, "integrity report")
+	}
+	return &report, nil
+}
+
+// PullReference removes refID from the array field of one parent document.
+func (r *IntegrityRepository) PullReference(ctx context.Context, collection string, parentID primitive.ObjectID, field string, refID primitive.ObjectID) error {
+	_, err := db.GetCollection(collection).UpdateOne(
+		ctx,
+		bson.M{"_id": parentID},
+		bson.M{"$pull": bson.M{field: refID}},
+	)
+	return err
+}
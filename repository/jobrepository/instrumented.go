@@ -0,0 +1,104 @@
+package jobrepository
+
+import (
+	"BackendCoursyclopedia/instrumentation"
+	"BackendCoursyclopedia/model/jobmodel"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type instrumentedJobRepository struct {
+	next IJobRepository
+}
+
+// NewInstrumentedJobRepository wraps next so every call records MongoDB
+// latency and errors under the "jobruns" repository label.
+func NewInstrumentedJobRepository(next IJobRepository) IJobRepository {
+	return &instrumentedJobRepository{next: next}
+}
+
+func (r *instrumentedJobRepository) EnqueueRun(ctx context.Context, run jobmodel.Run) (primitive.ObjectID, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "jobruns", "EnqueueRun")
+	result, err := r.next.EnqueueRun(ctx, run)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedJobRepository) LeaseRun(ctx context.Context, jobs []string, owner string, now time.Time, ttl time.Duration) (*jobmodel.Run, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "jobruns", "LeaseRun")
+	result, err := r.next.LeaseRun(ctx, jobs, owner, now, ttl)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedJobRepository) ExtendLease(ctx context.Context, runID primitive.ObjectID, owner string, until time.Time) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "jobruns", "ExtendLease")
+	err := r.next.ExtendLease(ctx, runID, owner, until)
+	done(err)
+	return err
+}
+
+func (r *instrumentedJobRepository) CompleteRun(ctx context.Context, runID primitive.ObjectID, owner string, at time.Time) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "jobruns", "CompleteRun")
+	err := r.next.CompleteRun(ctx, runID, owner, at)
+	done(err)
+	return err
+}
+
+func (r *instrumentedJobRepository) RescheduleRun(ctx context.Context, runID primitive.ObjectID, owner string, runAt time.Time, lastError string) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "jobruns", "RescheduleRun")
+	err := r.next.RescheduleRun(ctx, runID, owner, runAt, lastError)
+	done(err)
+	return err
+}
+
+func (r *instrumentedJobRepository) BuryRun(ctx context.Context, runID primitive.ObjectID, owner string, at time.Time, lastError string) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "jobruns", "BuryRun")
+	err := r.next.BuryRun(ctx, runID, owner, at, lastError)
+	done(err)
+	return err
+}
+
+func (r *instrumentedJobRepository) ReleaseRun(ctx context.Context, runID primitive.ObjectID, owner string) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "jobruns", "ReleaseRun")
+	err := r.next.ReleaseRun(ctx, runID, owner)
+	done(err)
+	return err
+}
+
+func (r *instrumentedJobRepository) RequeueRun(ctx context.Context, runID primitive.ObjectID, now time.Time) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "jobruns", "RequeueRun")
+	err := r.next.RequeueRun(ctx, runID, now)
+	done(err)
+	return err
+}
+
+func (r *instrumentedJobRepository) FindRunByID(ctx context.Context, runID string) (*jobmodel.Run, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "jobruns", "FindRunByID")
+	result, err := r.next.FindRunByID(ctx, runID)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedJobRepository) FindRuns(ctx context.Context, filter jobmodel.Filter) ([]jobmodel.Run, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "jobruns", "FindRuns")
+	result, err := r.next.FindRuns(ctx, filter)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedJobRepository) FindLastRun(ctx context.Context, job string) (*jobmodel.Run, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "jobruns", "FindLastRun")
+	result, err := r.next.FindLastRun(ctx, job)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedJobRepository) DeleteRun(ctx context.Context, runID primitive.ObjectID) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "jobruns", "DeleteRun")
+	err := r.next.DeleteRun(ctx, runID)
+	done(err)
+	return err
+}
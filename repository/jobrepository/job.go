@@ -0,0 +1,231 @@
+package jobrepository
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/db"
+	"BackendCoursyclopedia/model/jobmodel"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IJobRepository interface {
+	EnqueueRun(ctx context.Context, run jobmodel.Run) (primitive.ObjectID, error)
+	LeaseRun(ctx context.Context, jobs []string, owner string, now time.Time, ttl time.Duration) (*jobmodel.Run, error)
+	ExtendLease(ctx context.Context, runID primitive.ObjectID, owner string, until time.Time) error
+	CompleteRun(ctx context.Context, runID primitive.ObjectID, owner string, at time.Time) error
+	RescheduleRun(ctx context.Context, runID primitive.ObjectID, owner string, runAt time.Time, lastError string) error
+	BuryRun(ctx context.Context, runID primitive.ObjectID, owner string, at time.Time, lastError string) error
+	ReleaseRun(ctx context.Context, runID primitive.ObjectID, owner string) error
+	RequeueRun(ctx context.Context, runID primitive.ObjectID, now time.Time) error
+	FindRunByID(ctx context.Context, runID string) (*jobmodel.Run, error)
+	FindRuns(ctx context.Context, filter jobmodel.Filter) ([]jobmodel.Run, error)
+	FindLastRun(ctx context.Context, job string) (*jobmodel.Run, error)
+	DeleteRun(ctx context.Context, runID primitive.ObjectID) error
+}
+
+type JobRepository struct {
+	DB *mongo.Client
+}
+
+func NewJobRepository(db *mongo.Client) IJobRepository {
+	return &JobRepository{
+		DB: db,
+	}
+}
+
+// EnqueueRun inserts a queued run. A run whose DedupKey is already taken is
+// rejected as a conflict.
+func (r *JobRepository) EnqueueRun(ctx context.Context, run jobmodel.Run) (primitive.ObjectID, error) {
+	collection := db.GetCollection("jobruns")
+
+	result, err := collection.InsertOne(ctx, run)
+	if err != nil {
+		return primitive.NilObjectID, apperror.FromMongo(err, "job run")
+	}
+	return result.InsertedID.(primitive.ObjectID), nil
+}
+
+// LeaseRun claims the oldest due run of one of jobs for owner until now+ttl
+// and counts the attempt. Runs left running by a worker whose lease lapsed
+// are claimed as well. It returns NotFound when nothing is due.
+func (r *JobRepository) LeaseRun(ctx context.Context, jobs []string, owner string, now time.Time, ttl time.Duration) (*jobmodel.Run, error) {
+	collection := db.GetCollection("jobruns")
+	var run jobmodel.Run
+
+	filter := bson.M{
+		"job": bson.M{"$in": jobs},
+		"$or": bson.A{
+			bson.M{"status": jobmodel.StatusQueued, "runAt": bson.M{"$lte": now}},
+			bson.M{"status": jobmodel.StatusRunning, "leaseExpiresAt": bson.M{"$lt": now}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":         jobmodel.StatusRunning,
+			"leaseOwner":     owner,
+			"leaseExpiresAt": now.Add(ttl),
+			"startedAt":      now,
+		},
+		"$inc":   bson.M{"attempts": 1},
+		"$unset": bson.M{"finishedAt": ""},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "runAt", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	if err := collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&run); err != nil {
+		return nil, apperror.FromMongo(err, "due job run")
+	}
+	return &run, nil
+}
+
+// ExtendLease keeps a run leased to owner until until. It returns NotFound
+// once the lease has passed to another worker.
+func (r *JobRepository) ExtendLease(ctx context.Context, runID primitive.ObjectID, owner string, until time.Time) error {
+	return r.updateLeased(ctx, runID, owner, bson.M{"$set": bson.M{"leaseExpiresAt": until}})
+}
+
+func (r *JobRepository) CompleteRun(ctx context.Context, runID primitive.ObjectID, owner string, at time.Time) error {
+	return r.updateLeased(ctx, runID, owner, bson.M{
+		"$set":   bson.M{"status": jobmodel.StatusSucceeded, "finishedAt": at},
+		"$unset": bson.M{"leaseOwner": "", "leaseExpiresAt": "", "lastError": ""},
+	})
+}
+
+// RescheduleRun queues a failed run again at runAt.
+func (r *JobRepository) RescheduleRun(ctx context.Context, runID primitive.ObjectID, owner string, runAt time.Time, lastError string) error {
+	return r.updateLeased(ctx, runID, owner, bson.M{
+		"$set":   bson.M{"status": jobmodel.StatusQueued, "runAt": runAt, "lastError": lastError},
+		"$unset": bson.M{"leaseOwner": "", "leaseExpiresAt": ""},
+	})
+}
+
+// BuryRun moves a run that has used up its attempts to the dead-letter list.
+func (r *JobRepository) BuryRun(ctx context.Context, runID primitive.ObjectID, owner string, at time.Time, lastError string) error {
+	return r.updateLeased(ctx, runID, owner, bson.M{
+		"$set":   bson.M{"status": jobmodel.StatusDead, "finishedAt": at, "lastError": lastError},
+		"$unset": bson.M{"leaseOwner": "", "leaseExpiresAt": ""},
+	})
+}
+
+// ReleaseRun hands an interrupted run back to the queue without counting
+// the attempt, so another worker can take it straight away.
+func (r *JobRepository) ReleaseRun(ctx context.Context, runID primitive.ObjectID, owner string) error {
+	return r.updateLeased(ctx, runID, owner, bson.M{
+		"$set":   bson.M{"status": jobmodel.StatusQueued},
+		"$inc":   bson.M{"attempts": -1},
+		"$unset": bson.M{"leaseOwner": "", "leaseExpiresAt": "", "startedAt": ""},
+	})
+}
+
+func (r *JobRepository) updateLeased(ctx context.Context, runID primitive.ObjectID, owner string, update bson.M) error {
+	collection := db.GetCollection("jobruns")
+
+	result, err := collection.UpdateOne(ctx, bson.M{
+		"_id":        runID,
+		"status":     jobmodel.StatusRunning,
+		"leaseOwner": owner,
+	}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return apperror.NotFound("job run is no longer leased by this worker")
+	}
+	return nil
+}
+
+// RequeueRun takes a run off the dead-letter list and queues it with a
+// fresh set of attempts.
+func (r *JobRepository) RequeueRun(ctx context.Context, runID primitive.ObjectID, now time.Time) error {
+	collection := db.GetCollection("jobruns")
+
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"_id": runID, "status": jobmodel.StatusDead},
+		bson.M{
+			"$set":   bson.M{"status": jobmodel.StatusQueued, "trigger": jobmodel.TriggerRetry, "attempts": 0, "runAt": now},
+			"$unset": bson.M{"startedAt": "", "finishedAt": ""},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return apperror.Conflict("only dead job runs can be retried")
+	}
+	return nil
+}
+
+func (r *JobRepository) FindRunByID(ctx context.Context, runID string) (*jobmodel.Run, error) {
+	collection := db.GetCollection("jobruns")
+	var run jobmodel.Run
+
+	objID, err := primitive.ObjectIDFromHex(runID)
+	if err != nil {
+		return nil, apperror.InvalidID("job run", err)
+	}
+
+	if err := collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&run); err != nil {
+		return nil, apperror.FromMongo(err, "job run")
+	}
+	return &run, nil
+}
+
+// FindRuns returns the runs matching filter, newest first.
+func (r *JobRepository) FindRuns(ctx context.Context, filter jobmodel.Filter) ([]jobmodel.Run, error) {
+	collection := db.GetCollection("jobruns")
+	runs := []jobmodel.Run{}
+
+	query := bson.M{}
+	if filter.Job != "" {
+		query["job"] = filter.Job
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(filter.Limit)
+	}
+	cursor, err := collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &runs); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+func (r *JobRepository) FindLastRun(ctx context.Context, job string) (*jobmodel.Run, error) {
+	collection := db.GetCollection("jobruns")
+	var run jobmodel.Run
+
+	opts := options.FindOne().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	if err := collection.FindOne(ctx, bson.M{"job": job}, opts).Decode(&run); err != nil {
+		return nil, apperror.FromMongo(err, "job run")
+	}
+	return &run, nil
+}
+
+// DeleteRun discards a dead run.
+func (r *JobRepository) DeleteRun(ctx context.Context, runID primitive.ObjectID) error {
+	collection := db.GetCollection("jobruns")
+
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": runID, "status": jobmodel.StatusDead})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return apperror.Conflict("only dead job runs can be discarded")
+	}
+	return nil
+}
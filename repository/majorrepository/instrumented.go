@@ -0,0 +1,89 @@
+package majorrepository
+
+import (
+	"BackendCoursyclopedia/instrumentation"
+	"BackendCoursyclopedia/model/majormodel"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type instrumentedMajorRepository struct {
+	next IMajorRepository
+}
+
+// NewInstrumentedMajorRepository wraps next so every call records MongoDB
+// latency and errors under the "majors" repository label.
+func NewInstrumentedMajorRepository(next IMajorRepository) IMajorRepository {
+	return &instrumentedMajorRepository{next: next}
+}
+
+func (r *instrumentedMajorRepository) FindAllMajors(ctx context.Context) ([]majormodel.Major, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "majors", "FindAllMajors")
+	result, err := r.next.FindAllMajors(ctx)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedMajorRepository) FindmajorbyID(ctx context.Context, majorId string) (*majormodel.Major, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "majors", "FindmajorbyID")
+	result, err := r.next.FindmajorbyID(ctx, majorId)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedMajorRepository) FindMajorsByIDs(ctx context.Context, majorIDs []primitive.ObjectID) ([]majormodel.Major, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "majors", "FindMajorsByIDs")
+	result, err := r.next.FindMajorsByIDs(ctx, majorIDs)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedMajorRepository) CreateMajor(ctx context.Context, majorName string) (string, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "majors", "CreateMajor")
+	result, err := r.next.CreateMajor(ctx, majorName)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedMajorRepository) DeleteMajor(ctx context.Context, majorId primitive.ObjectID) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "majors", "DeleteMajor")
+	err := r.next.DeleteMajor(ctx, majorId)
+	done(err)
+	return err
+}
+
+func (r *instrumentedMajorRepository) UpdateMajor(ctx context.Context, majorId primitive.ObjectID, newName string) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "majors", "UpdateMajor")
+	err := r.next.UpdateMajor(ctx, majorId, newName)
+	done(err)
+	return err
+}
+
+func (r *instrumentedMajorRepository) AddSubjectToMajor(ctx context.Context, majorId string, subjectId string) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "majors", "AddSubjectToMajor")
+	err := r.next.AddSubjectToMajor(ctx, majorId, subjectId)
+	done(err)
+	return err
+}
+
+func (r *instrumentedMajorRepository) RemoveSubjectFromMajors(ctx context.Context, subjectId primitive.ObjectID) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "majors", "RemoveSubjectFromMajors")
+	err := r.next.RemoveSubjectFromMajors(ctx, subjectId)
+	done(err)
+	return err
+}
+
+func (r *instrumentedMajorRepository) FindMajorBySubjectId(ctx context.Context, subjectId primitive.ObjectID) (majormodel.Major, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "majors", "FindMajorBySubjectId")
+	result, err := r.next.FindMajorBySubjectId(ctx, subjectId)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedMajorRepository) UpdatemajorforSubject(ctx context.Context, subjectId primitive.ObjectID, currentmajorId primitive.ObjectID, newmajorId primitive.ObjectID) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "majors", "UpdatemajorforSubject")
+	err := r.next.UpdatemajorforSubject(ctx, subjectId, currentmajorId, newmajorId)
+	done(err)
+	return err
+}
@@ -1,6 +1,7 @@
 package majorrepository
 
 import (
+	"BackendCoursyclopedia/apperror"
 	"BackendCoursyclopedia/db"
 	"BackendCoursyclopedia/model/majormodel"
 	"context"
@@ -60,12 +61,12 @@ func (r *MajorRepository) FindmajorbyID(ctx context.Context, majorId string) (*m
 
 	objID, err := primitive.ObjectIDFromHex(majorId)
 	if err != nil {
-		return nil, err
+		return nil, apperror.InvalidID("major", err)
 	}
 
 	err = collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&major)
 	if err != nil {
-		return nil, err
+		return nil, apperror.FromMongo(err, "major")
 	}
 	return &major, nil
 }
@@ -105,7 +106,7 @@ func (r *MajorRepository) CreateMajor(ctx context.Context, majorName string) (st
 	}
 	_, err := collection.InsertOne(ctx, major)
 	if err != nil {
-		return "", err
+		return "", apperror.FromMongo(err, "major")
 	}
 	return major.ID.Hex(), nil
 }
@@ -113,21 +114,33 @@ func (r *MajorRepository) CreateMajor(ctx context.Context, majorName string) (st
 func (r *MajorRepository) DeleteMajor(ctx context.Context, majorId primitive.ObjectID) error {
 	collection := db.GetCollection("majors")
 
-	_, err := collection.DeleteOne(ctx, bson.M{"_id": majorId})
-	return err
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": majorId})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return apperror.NotFound("major not found")
+	}
+	return nil
 }
 
 func (r *MajorRepository) UpdateMajor(ctx context.Context, majorId primitive.ObjectID, newName string) error {
 	collection := db.GetCollection("majors")
 	update := bson.M{"$set": bson.M{"majorName": newName}}
 
-	_, err := collection.UpdateOne(
+	result, err := collection.UpdateOne(
 		ctx,
 		bson.M{"_id": majorId},
 		update,
 	)
+	if err != nil {
+		return apperror.FromMongo(err, "major")
+	}
+	if result.MatchedCount == 0 {
+		return apperror.NotFound("major not found")
+	}
 
-	return err
+	return nil
 }
 
 func (r *MajorRepository) AddSubjectToMajor(ctx context.Context, majorId string, subjectId string) error {
@@ -136,12 +149,12 @@ func (r *MajorRepository) AddSubjectToMajor(ctx context.Context, majorId string,
 	mid, err := primitive.ObjectIDFromHex(majorId)
 	if err != nil {
 
-		return err
+		return apperror.InvalidID("major", err)
 	}
 
 	sid, err := primitive.ObjectIDFromHex(subjectId)
 	if err != nil {
-		return err
+		return apperror.InvalidID("subject", err)
 	}
 
 	filter := bson.M{"_id": mid}
@@ -151,7 +164,7 @@ func (r *MajorRepository) AddSubjectToMajor(ctx context.Context, majorId string,
 		return err
 	}
 	if result.MatchedCount == 0 {
-		return mongo.ErrNoDocuments
+		return apperror.NotFound("major not found")
 	}
 
 	return nil
@@ -176,7 +189,7 @@ func (r *MajorRepository) FindMajorBySubjectId(ctx context.Context, subjectId pr
 	filter := bson.M{"subjectIDs": subjectId}
 	err := collection.FindOne(ctx, filter).Decode(&major)
 	if err != nil {
-		return majormodel.Major{}, err
+		return majormodel.Major{}, apperror.FromMongo(err, "major")
 	}
 	return major, nil
 }
@@ -0,0 +1,62 @@
+package notificationrepository
+
+import (
+	"BackendCoursyclopedia/instrumentation"
+	"BackendCoursyclopedia/model/notificationmodel"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type instrumentedNotificationRepository struct {
+	next INotificationRepository
+}
+
+// NewInstrumentedNotificationRepository wraps next so every call records MongoDB
+// latency and errors under the "notifications" repository label.
+func NewInstrumentedNotificationRepository(next INotificationRepository) INotificationRepository {
+	return &instrumentedNotificationRepository{next: next}
+}
+
+func (r *instrumentedNotificationRepository) CreateNotifications(ctx context.Context, notifications []notificationmodel.Notification) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "notifications", "CreateNotifications")
+	err := r.next.CreateNotifications(ctx, notifications)
+	done(err)
+	return err
+}
+
+func (r *instrumentedNotificationRepository) ClaimNotification(ctx context.Context, now time.Time, leaseUntil time.Time) (*notificationmodel.Notification, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "notifications", "ClaimNotification")
+	result, err := r.next.ClaimNotification(ctx, now, leaseUntil)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedNotificationRepository) MarkSent(ctx context.Context, notificationID primitive.ObjectID, at time.Time) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "notifications", "MarkSent")
+	err := r.next.MarkSent(ctx, notificationID, at)
+	done(err)
+	return err
+}
+
+func (r *instrumentedNotificationRepository) RescheduleNotification(ctx context.Context, notificationID primitive.ObjectID, nextAttemptAt time.Time, lastError string) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "notifications", "RescheduleNotification")
+	err := r.next.RescheduleNotification(ctx, notificationID, nextAttemptAt, lastError)
+	done(err)
+	return err
+}
+
+func (r *instrumentedNotificationRepository) FailNotification(ctx context.Context, notificationID primitive.ObjectID, lastError string) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "notifications", "FailNotification")
+	err := r.next.FailNotification(ctx, notificationID, lastError)
+	done(err)
+	return err
+}
+
+func (r *instrumentedNotificationRepository) RedactNotification(ctx context.Context, notificationID primitive.ObjectID) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "notifications", "RedactNotification")
+	err := r.next.RedactNotification(ctx, notificationID)
+	done(err)
+	return err
+}
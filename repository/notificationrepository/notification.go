@@ -0,0 +1,115 @@
+package notificationrepository
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/db"
+	"BackendCoursyclopedia/model/notificationmodel"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type INotificationRepository interface {
+	CreateNotifications(ctx context.Context, notifications []notificationmodel.Notification) error
+	ClaimNotification(ctx context.Context, now time.Time, leaseUntil time.Time) (*notificationmodel.Notification, error)
+	MarkSent(ctx context.Context, notificationID primitive.ObjectID, at time.Time) error
+	RescheduleNotification(ctx context.Context, notificationID primitive.ObjectID, nextAttemptAt time.Time, lastError string) error
+	FailNotification(ctx context.Context, notificationID primitive.ObjectID, lastError string) error
+	RedactNotification(ctx context.Context, notificationID primitive.ObjectID) error
+}
+
+type NotificationRepository struct {
+	DB *mongo.Client
+}
+
+func NewNotificationRepository(db *mongo.Client) INotificationRepository {
+	return &NotificationRepository{
+		DB: db,
+	}
+}
+
+func (r *NotificationRepository) CreateNotifications(ctx context.Context, notifications []notificationmodel.Notification) error {
+	collection := db.GetCollection("notifications")
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(notifications))
+	for i, n := range notifications {
+		docs[i] = n
+	}
+	_, err := collection.InsertMany(ctx, docs)
+	return err
+}
+
+// ClaimNotification moves the oldest due notification to sending until
+// leaseUntil and counts the attempt. It returns NotFound when nothing is
+// due.
+func (r *NotificationRepository) ClaimNotification(ctx context.Context, now time.Time, leaseUntil time.Time) (*notificationmodel.Notification, error) {
+	collection := db.GetCollection("notifications")
+	var notification notificationmodel.Notification
+
+	filter := bson.M{"$or": bson.A{
+		bson.M{"status": notificationmodel.StatusPending, "nextAttemptAt": bson.M{"$lte": now}},
+		bson.M{"status": notificationmodel.StatusSending, "leaseExpiresAt": bson.M{"$lt": now}},
+	}}
+	update := bson.M{
+		"$set": bson.M{"status": notificationmodel.StatusSending, "leaseExpiresAt": leaseUntil},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "nextAttemptAt", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	if err := collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&notification); err != nil {
+		return nil, apperror.FromMongo(err, "due notification")
+	}
+	return &notification, nil
+}
+
+func (r *NotificationRepository) MarkSent(ctx context.Context, notificationID primitive.ObjectID, at time.Time) error {
+	return r.update(ctx, notificationID, bson.M{
+		"$set":   bson.M{"status": notificationmodel.StatusSent, "sentAt": at},
+		"$unset": bson.M{"leaseExpiresAt": "", "lastError": ""},
+	})
+}
+
+func (r *NotificationRepository) RescheduleNotification(ctx context.Context, notificationID primitive.ObjectID, nextAttemptAt time.Time, lastError string) error {
+	return r.update(ctx, notificationID, bson.M{
+		"$set":   bson.M{"status": notificationmodel.StatusPending, "nextAttemptAt": nextAttemptAt, "lastError": lastError},
+		"$unset": bson.M{"leaseExpiresAt": ""},
+	})
+}
+
+func (r *NotificationRepository) FailNotification(ctx context.Context, notificationID primitive.ObjectID, lastError string) error {
+	return r.update(ctx, notificationID, bson.M{
+		"$set":   bson.M{"status": notificationmodel.StatusFailed, "lastError": lastError},
+		"$unset": bson.M{"leaseExpiresAt": ""},
+	})
+}
+
+// RedactNotification removes the bodies of a notification, keeping the
+// record of its delivery.
+func (r *NotificationRepository) RedactNotification(ctx context.Context, notificationID primitive.ObjectID) error {
+	collection := db.GetCollection("notifications")
+
+	_, err := collection.UpdateOne(ctx, bson.M{"_id": notificationID}, bson.M{"$unset": bson.M{"text": "", "html": ""}})
+	return err
+}
+
+func (r *NotificationRepository) update(ctx context.Context, notificationID primitive.ObjectID, update bson.M) error {
+	collection := db.GetCollection("notifications")
+
+	result, err := collection.UpdateOne(ctx, bson.M{"_id": notificationID, "status": notificationmodel.StatusSending}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return apperror.NotFound("notification is no longer being sent")
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package offeringrepository
+
+import (
+	"BackendCoursyclopedia/instrumentation"
+	"BackendCoursyclopedia/model/offeringmodel"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type instrumentedOfferingRepository struct {
+	next IOfferingRepository
+}
+
+// NewInstrumentedOfferingRepository wraps next so every call records MongoDB
+// latency and errors under the "offerings" repository label.
+func NewInstrumentedOfferingRepository(next IOfferingRepository) IOfferingRepository {
+	return &instrumentedOfferingRepository{next: next}
+}
+
+func (r *instrumentedOfferingRepository) CreateOffering(ctx context.Context, offering offeringmodel.Offering) (primitive.ObjectID, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "offerings", "CreateOffering")
+	result, err := r.next.CreateOffering(ctx, offering)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedOfferingRepository) FindOfferingByID(ctx context.Context, offeringID string) (*offeringmodel.Offering, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "offerings", "FindOfferingByID")
+	result, err := r.next.FindOfferingByID(ctx, offeringID)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedOfferingRepository) FindOfferingsByIDs(ctx context.Context, offeringIDs []primitive.ObjectID) ([]offeringmodel.Offering, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "offerings", "FindOfferingsByIDs")
+	result, err := r.next.FindOfferingsByIDs(ctx, offeringIDs)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedOfferingRepository) FindOfferings(ctx context.Context, filter offeringmodel.Filter) ([]offeringmodel.Offering, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "offerings", "FindOfferings")
+	result, err := r.next.FindOfferings(ctx, filter)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedOfferingRepository) CountOfferingsByTerm(ctx context.Context, termID primitive.ObjectID) (int64, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "offerings", "CountOfferingsByTerm")
+	result, err := r.next.CountOfferingsByTerm(ctx, termID)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedOfferingRepository) CountOfferingsBySubject(ctx context.Context, subjectID primitive.ObjectID) (int64, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "offerings", "CountOfferingsBySubject")
+	result, err := r.next.CountOfferingsBySubject(ctx, subjectID)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedOfferingRepository) UpdateOffering(ctx context.Context, offering offeringmodel.Offering) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "offerings", "UpdateOffering")
+	err := r.next.UpdateOffering(ctx, offering)
+	done(err)
+	return err
+}
+
+func (r *instrumentedOfferingRepository) DeleteOffering(ctx context.Context, offeringID primitive.ObjectID) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "offerings", "DeleteOffering")
+	err := r.next.DeleteOffering(ctx, offeringID)
+	done(err)
+	return err
+}
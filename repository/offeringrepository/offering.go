@@ -0,0 +1,141 @@
+package offeringrepository
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/db"
+	"BackendCoursyclopedia/model/offeringmodel"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IOfferingRepository interface {
+	CreateOffering(ctx context.Context, offering offeringmodel.Offering) (primitive.ObjectID, error)
+	FindOfferingByID(ctx context.Context, offeringID string) (*offeringmodel.Offering, error)
+	FindOfferingsByIDs(ctx context.Context, offeringIDs []primitive.ObjectID) ([]offeringmodel.Offering, error)
+	FindOfferings(ctx context.Context, filter offeringmodel.Filter) ([]offeringmodel.Offering, error)
+	CountOfferingsByTerm(ctx context.Context, termID primitive.ObjectID) (int64, error)
+	CountOfferingsBySubject(ctx context.Context, subjectID primitive.ObjectID) (int64, error)
+	UpdateOffering(ctx context.Context, offering offeringmodel.Offering) error
+	DeleteOffering(ctx context.Context, offeringID primitive.ObjectID) error
+}
+
+type OfferingRepository struct {
+	DB *mongo.Client
+}
+
+func NewOfferingRepository(db *mongo.Client) IOfferingRepository {
+	return &OfferingRepository{
+		DB: db,
+	}
+}
+
+func (r *OfferingRepository) CreateOffering(ctx context.Context, offering offeringmodel.Offering) (primitive.ObjectID, error) {
+	collection := db.GetCollection("offerings")
+
+	result, err := collection.InsertOne(ctx, offering)
+	if err != nil {
+		return primitive.NilObjectID, apperror.FromMongo(err, "offering")
+	}
+	return result.InsertedID.(primitive.ObjectID), nil
+}
+
+func (r *OfferingRepository) FindOfferingByID(ctx context.Context, offeringID string) (*offeringmodel.Offering, error) {
+	collection := db.GetCollection("offerings")
+	var offering offeringmodel.Offering
+
+	objID, err := primitive.ObjectIDFromHex(offeringID)
+	if err != nil {
+		return nil, apperror.InvalidID("offering", err)
+	}
+
+	if err := collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&offering); err != nil {
+		return nil, apperror.FromMongo(err, "offering")
+	}
+	return &offering, nil
+}
+
+func (r *OfferingRepository) FindOfferingsByIDs(ctx context.Context, offeringIDs []primitive.ObjectID) ([]offeringmodel.Offering, error) {
+	collection := db.GetCollection("offerings")
+	offerings := []offeringmodel.Offering{}
+
+	cursor, err := collection.Find(ctx, bson.M{"_id": bson.M{"$in": offeringIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &offerings); err != nil {
+		return nil, err
+	}
+	return offerings, nil
+}
+
+// FindOfferings returns the offerings matching filter, grouped by term and
+// then by subject.
+func (r *OfferingRepository) FindOfferings(ctx context.Context, filter offeringmodel.Filter) ([]offeringmodel.Offering, error) {
+	collection := db.GetCollection("offerings")
+	offerings := []offeringmodel.Offering{}
+
+	query := bson.M{}
+	if !filter.TermID.IsZero() {
+		query["termId"] = filter.TermID
+	}
+	if filter.SubjectIDs != nil {
+		query["subjectId"] = bson.M{"$in": filter.SubjectIDs}
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "termId", Value: -1}, {Key: "subjectId", Value: 1}, {Key: "campus", Value: 1}})
+	cursor, err := collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &offerings); err != nil {
+		return nil, err
+	}
+	return offerings, nil
+}
+
+func (r *OfferingRepository) CountOfferingsByTerm(ctx context.Context, termID primitive.ObjectID) (int64, error) {
+	collection := db.GetCollection("offerings")
+	return collection.CountDocuments(ctx, bson.M{"termId": termID})
+}
+
+func (r *OfferingRepository) CountOfferingsBySubject(ctx context.Context, subjectID primitive.ObjectID) (int64, error) {
+	collection := db.GetCollection("offerings")
+	return collection.CountDocuments(ctx, bson.M{"subjectId": subjectID})
+}
+
+func (r *OfferingRepository) UpdateOffering(ctx context.Context, offering offeringmodel.Offering) error {
+	collection := db.GetCollection("offerings")
+
+	result, err := collection.ReplaceOne(ctx, bson.M{"_id": offering.ID}, offering)
+	if err != nil {
+		return apperror.FromMongo(err, "offering")
+	}
+	if result.MatchedCount == 0 {
+		return apperror.NotFound("offering not found")
+	}
+	return nil
+}
+
+func (r *OfferingRepository) DeleteOffering(ctx context.Context, offeringID primitive.ObjectID) error {
+	collection := db.GetCollection("offerings")
+
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": offeringID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return apperror.NotFound("offering not found")
+	}
+	return nil
+}
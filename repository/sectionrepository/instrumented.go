@@ -0,0 +1,68 @@
+package sectionrepository
+
+import (
+	"BackendCoursyclopedia/instrumentation"
+	"BackendCoursyclopedia/model/sectionmodel"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type instrumentedSectionRepository struct {
+	next ISectionRepository
+}
+
+// NewInstrumentedSectionRepository wraps next so every call records MongoDB
+// latency and errors under the "sections" repository label.
+func NewInstrumentedSectionRepository(next ISectionRepository) ISectionRepository {
+	return &instrumentedSectionRepository{next: next}
+}
+
+func (r *instrumentedSectionRepository) CreateSection(ctx context.Context, section sectionmodel.Section) (primitive.ObjectID, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "sections", "CreateSection")
+	result, err := r.next.CreateSection(ctx, section)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedSectionRepository) FindSectionByID(ctx context.Context, sectionID string) (*sectionmodel.Section, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "sections", "FindSectionByID")
+	result, err := r.next.FindSectionByID(ctx, sectionID)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedSectionRepository) FindSectionsByIDs(ctx context.Context, sectionIDs []primitive.ObjectID) ([]sectionmodel.Section, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "sections", "FindSectionsByIDs")
+	result, err := r.next.FindSectionsByIDs(ctx, sectionIDs)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedSectionRepository) FindSectionsByOfferings(ctx context.Context, offeringIDs []primitive.ObjectID) ([]sectionmodel.Section, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "sections", "FindSectionsByOfferings")
+	result, err := r.next.FindSectionsByOfferings(ctx, offeringIDs)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedSectionRepository) UpdateSection(ctx context.Context, section sectionmodel.Section) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "sections", "UpdateSection")
+	err := r.next.UpdateSection(ctx, section)
+	done(err)
+	return err
+}
+
+func (r *instrumentedSectionRepository) DeleteSection(ctx context.Context, sectionID primitive.ObjectID) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "sections", "DeleteSection")
+	err := r.next.DeleteSection(ctx, sectionID)
+	done(err)
+	return err
+}
+
+func (r *instrumentedSectionRepository) DeleteSectionsByOffering(ctx context.Context, offeringID primitive.ObjectID) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "sections", "DeleteSectionsByOffering")
+	err := r.next.DeleteSectionsByOffering(ctx, offeringID)
+	done(err)
+	return err
+}
@@ -0,0 +1,126 @@
+package sectionrepository
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/db"
+	"BackendCoursyclopedia/model/sectionmodel"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ISectionRepository interface {
+	CreateSection(ctx context.Context, section sectionmodel.Section) (primitive.ObjectID, error)
+	FindSectionByID(ctx context.Context, sectionID string) (*sectionmodel.Section, error)
+	FindSectionsByIDs(ctx context.Context, sectionIDs []primitive.ObjectID) ([]sectionmodel.Section, error)
+	FindSectionsByOfferings(ctx context.Context, offeringIDs []primitive.ObjectID) ([]sectionmodel.Section, error)
+	UpdateSection(ctx context.Context, section sectionmodel.Section) error
+	DeleteSection(ctx context.Context, sectionID primitive.ObjectID) error
+	DeleteSectionsByOffering(ctx context.Context, offeringID primitive.ObjectID) error
+}
+
+type SectionRepository struct {
+	DB *mongo.Client
+}
+
+func NewSectionRepository(db *mongo.Client) ISectionRepository {
+	return &SectionRepository{
+		DB: db,
+	}
+}
+
+func (r *SectionRepository) CreateSection(ctx context.Context, section sectionmodel.Section) (primitive.ObjectID, error) {
+	collection := db.GetCollection("sections")
+
+	result, err := collection.InsertOne(ctx, section)
+	if err != nil {
+		return primitive.NilObjectID, apperror.FromMongo(err, "section")
+	}
+	return result.InsertedID.(primitive.ObjectID), nil
+}
+
+func (r *SectionRepository) FindSectionByID(ctx context.Context, sectionID string) (*sectionmodel.Section, error) {
+	collection := db.GetCollection("sections")
+	var section sectionmodel.Section
+
+	objID, err := primitive.ObjectIDFromHex(sectionID)
+	if err != nil {
+		return nil, apperror.InvalidID("section", err)
+	}
+
+	if err := collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&section); err != nil {
+		return nil, apperror.FromMongo(err, "section")
+	}
+	return &section, nil
+}
+
+func (r *SectionRepository) FindSectionsByIDs(ctx context.Context, sectionIDs []primitive.ObjectID) ([]sectionmodel.Section, error) {
+	collection := db.GetCollection("sections")
+	sections := []sectionmodel.Section{}
+
+	cursor, err := collection.Find(ctx, bson.M{"_id": bson.M{"$in": sectionIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &sections); err != nil {
+		return nil, err
+	}
+	return sections, nil
+}
+
+// FindSectionsByOfferings returns the sections of the given offerings,
+// ordered by offering and section number.
+func (r *SectionRepository) FindSectionsByOfferings(ctx context.Context, offeringIDs []primitive.ObjectID) ([]sectionmodel.Section, error) {
+	collection := db.GetCollection("sections")
+	sections := []sectionmodel.Section{}
+
+	opts := options.Find().SetSort(bson.D{{Key: "offeringId", Value: 1}, {Key: "number", Value: 1}})
+	cursor, err := collection.Find(ctx, bson.M{"offeringId": bson.M{"$in": offeringIDs}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &sections); err != nil {
+		return nil, err
+	}
+	return sections, nil
+}
+
+func (r *SectionRepository) UpdateSection(ctx context.Context, section sectionmodel.Section) error {
+	collection := db.GetCollection("sections")
+
+	result, err := collection.ReplaceOne(ctx, bson.M{"_id": section.ID}, section)
+	if err != nil {
+		return apperror.FromMongo(err, "section")
+	}
+	if result.MatchedCount == 0 {
+		return apperror.NotFound("section not found")
+	}
+	return nil
+}
+
+func (r *SectionRepository) DeleteSection(ctx context.Context, sectionID primitive.ObjectID) error {
+	collection := db.GetCollection("sections")
+
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": sectionID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return apperror.NotFound("section not found")
+	}
+	return nil
+}
+
+func (r *SectionRepository) DeleteSectionsByOffering(ctx context.Context, offeringID primitive.ObjectID) error {
+	collection := db.GetCollection("sections")
+
+	_, err := collection.DeleteMany(ctx, bson.M{"offeringId": offeringID})
+	return err
+}
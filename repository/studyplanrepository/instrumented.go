@@ -0,0 +1,61 @@
+package studyplanrepository
+
+import (
+	"BackendCoursyclopedia/instrumentation"
+	"BackendCoursyclopedia/model/studyplanmodel"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type instrumentedStudyPlanRepository struct {
+	next IStudyPlanRepository
+}
+
+// NewInstrumentedStudyPlanRepository wraps next so every call records MongoDB
+// latency and errors under the "studyplans" repository label.
+func NewInstrumentedStudyPlanRepository(next IStudyPlanRepository) IStudyPlanRepository {
+	return &instrumentedStudyPlanRepository{next: next}
+}
+
+func (r *instrumentedStudyPlanRepository) CreatePlan(ctx context.Context, plan studyplanmodel.Plan) (primitive.ObjectID, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "studyplans", "CreatePlan")
+	result, err := r.next.CreatePlan(ctx, plan)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedStudyPlanRepository) FindPlansByUser(ctx context.Context, userID primitive.ObjectID) ([]studyplanmodel.Plan, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "studyplans", "FindPlansByUser")
+	result, err := r.next.FindPlansByUser(ctx, userID)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedStudyPlanRepository) FindPlan(ctx context.Context, userID primitive.ObjectID, planID string) (*studyplanmodel.Plan, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "studyplans", "FindPlan")
+	result, err := r.next.FindPlan(ctx, userID, planID)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedStudyPlanRepository) ReplacePlan(ctx context.Context, plan studyplanmodel.Plan) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "studyplans", "ReplacePlan")
+	err := r.next.ReplacePlan(ctx, plan)
+	done(err)
+	return err
+}
+
+func (r *instrumentedStudyPlanRepository) DeletePlan(ctx context.Context, userID primitive.ObjectID, planID string) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "studyplans", "DeletePlan")
+	err := r.next.DeletePlan(ctx, userID, planID)
+	done(err)
+	return err
+}
+
+func (r *instrumentedStudyPlanRepository) CountPlansBySubject(ctx context.Context, subjectID primitive.ObjectID) (int64, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "studyplans", "CountPlansBySubject")
+	result, err := r.next.CountPlansBySubject(ctx, subjectID)
+	done(err)
+	return result, err
+}
@@ -0,0 +1,113 @@
+package studyplanrepository
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/db"
+	"BackendCoursyclopedia/model/studyplanmodel"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IStudyPlanRepository interface {
+	CreatePlan(ctx context.Context, plan studyplanmodel.Plan) (primitive.ObjectID, error)
+	FindPlansByUser(ctx context.Context, userID primitive.ObjectID) ([]studyplanmodel.Plan, error)
+	FindPlan(ctx context.Context, userID primitive.ObjectID, planID string) (*studyplanmodel.Plan, error)
+	ReplacePlan(ctx context.Context, plan studyplanmodel.Plan) error
+	DeletePlan(ctx context.Context, userID primitive.ObjectID, planID string) error
+	CountPlansBySubject(ctx context.Context, subjectID primitive.ObjectID) (int64, error)
+}
+
+type StudyPlanRepository struct {
+	DB *mongo.Client
+}
+
+func NewStudyPlanRepository(db *mongo.Client) IStudyPlanRepository {
+	return &StudyPlanRepository{
+		DB: db,
+	}
+}
+
+func (r *StudyPlanRepository) CreatePlan(ctx context.Context, plan studyplanmodel.Plan) (primitive.ObjectID, error) {
+	collection := db.GetCollection("studyplans")
+
+	result, err := collection.InsertOne(ctx, plan)
+	if err != nil {
+		return primitive.NilObjectID, apperror.FromMongo(err, "study plan")
+	}
+	return result.InsertedID.(primitive.ObjectID), nil
+}
+
+func (r *StudyPlanRepository) FindPlansByUser(ctx context.Context, userID primitive.ObjectID) ([]studyplanmodel.Plan, error) {
+	collection := db.GetCollection("studyplans")
+	plans := []studyplanmodel.Plan{}
+
+	opts := options.Find().SetSort(bson.D{{Key: "updatedAt", Value: -1}})
+	cursor, err := collection.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &plans); err != nil {
+		return nil, err
+	}
+	return plans, nil
+}
+
+// FindPlan returns a plan only if it belongs to userID, so one user cannot
+// read another's plans by guessing IDs.
+func (r *StudyPlanRepository) FindPlan(ctx context.Context, userID primitive.ObjectID, planID string) (*studyplanmodel.Plan, error) {
+	collection := db.GetCollection("studyplans")
+	var plan studyplanmodel.Plan
+
+	objID, err := primitive.ObjectIDFromHex(planID)
+	if err != nil {
+		return nil, apperror.InvalidID("study plan", err)
+	}
+
+	err = collection.FindOne(ctx, bson.M{"_id": objID, "userId": userID}).Decode(&plan)
+	if err != nil {
+		return nil, apperror.FromMongo(err, "study plan")
+	}
+	return &plan, nil
+}
+
+func (r *StudyPlanRepository) ReplacePlan(ctx context.Context, plan studyplanmodel.Plan) error {
+	collection := db.GetCollection("studyplans")
+
+	result, err := collection.ReplaceOne(ctx, bson.M{"_id": plan.ID, "userId": plan.UserID}, plan)
+	if err != nil {
+		return apperror.FromMongo(err, "study plan")
+	}
+	if result.MatchedCount == 0 {
+		return apperror.NotFound("study plan not found")
+	}
+	return nil
+}
+
+func (r *StudyPlanRepository) DeletePlan(ctx context.Context, userID primitive.ObjectID, planID string) error {
+	collection := db.GetCollection("studyplans")
+
+	objID, err := primitive.ObjectIDFromHex(planID)
+	if err != nil {
+		return apperror.InvalidID("study plan", err)
+	}
+
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": objID, "userId": userID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return apperror.NotFound("study plan not found")
+	}
+	return nil
+}
+
+func (r *StudyPlanRepository) CountPlansBySubject(ctx context.Context, subjectID primitive.ObjectID) (int64, error) {
+	collection := db.GetCollection("studyplans")
+	return collection.CountDocuments(ctx, bson.M{"semesters.subjects.subjectId": subjectID})
+}
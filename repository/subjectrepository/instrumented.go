@@ -0,0 +1,105 @@
+package subjectrepository
+
+import (
+	"BackendCoursyclopedia/instrumentation"
+	"BackendCoursyclopedia/model/subjectmodel"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type instrumentedSubjectRepository struct {
+	next ISubjectRepository
+}
+
+// NewInstrumentedSubjectRepository wraps next so every call records MongoDB
+// latency and errors under the "subjects" repository label.
+func NewInstrumentedSubjectRepository(next ISubjectRepository) ISubjectRepository {
+	return &instrumentedSubjectRepository{next: next}
+}
+
+func (r *instrumentedSubjectRepository) FindAllSubjects(ctx context.Context) ([]subjectmodel.Subject, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "subjects", "FindAllSubjects")
+	result, err := r.next.FindAllSubjects(ctx)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedSubjectRepository) FindSubjectbyID(ctx context.Context, subjectId string) (*subjectmodel.Subject, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "subjects", "FindSubjectbyID")
+	result, err := r.next.FindSubjectbyID(ctx, subjectId)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedSubjectRepository) FindSubjectsByIDs(ctx context.Context, subjectIDs []primitive.ObjectID) ([]subjectmodel.Subject, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "subjects", "FindSubjectsByIDs")
+	result, err := r.next.FindSubjectsByIDs(ctx, subjectIDs)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedSubjectRepository) CreateSubject(ctx context.Context, subject subjectmodel.Subject) (primitive.ObjectID, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "subjects", "CreateSubject")
+	result, err := r.next.CreateSubject(ctx, subject)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedSubjectRepository) DeleteSubject(ctx context.Context, subjectId primitive.ObjectID) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "subjects", "DeleteSubject")
+	err := r.next.DeleteSubject(ctx, subjectId)
+	done(err)
+	return err
+}
+
+func (r *instrumentedSubjectRepository) UpdateSubject(ctx context.Context, subjectId primitive.ObjectID, updates bson.M) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "subjects", "UpdateSubject")
+	err := r.next.UpdateSubject(ctx, subjectId, updates)
+	done(err)
+	return err
+}
+
+func (r *instrumentedSubjectRepository) UpdateLikes(ctx context.Context, subjectID primitive.ObjectID, likes int) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "subjects", "UpdateLikes")
+	err := r.next.UpdateLikes(ctx, subjectID, likes)
+	done(err)
+	return err
+}
+
+func (r *instrumentedSubjectRepository) AddEmailToLikeList(ctx context.Context, subjectID primitive.ObjectID, userEmail string) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "subjects", "AddEmailToLikeList")
+	err := r.next.AddEmailToLikeList(ctx, subjectID, userEmail)
+	done(err)
+	return err
+}
+
+func (r *instrumentedSubjectRepository) EachSubjectByIDs(ctx context.Context, subjectIDs []primitive.ObjectID, fn func(subjectmodel.Subject) error) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "subjects", "EachSubjectByIDs")
+	err := r.next.EachSubjectByIDs(ctx, subjectIDs, fn)
+	done(err)
+	return err
+}
+
+func (r *instrumentedSubjectRepository) FindSubjectCodes(ctx context.Context) (map[primitive.ObjectID]string, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "subjects", "FindSubjectCodes")
+	result, err := r.next.FindSubjectCodes(ctx)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedSubjectRepository) UpdateSubjectStatus(ctx context.Context, subjectID primitive.ObjectID, from, to string, at time.Time) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "subjects", "UpdateSubjectStatus")
+	err := r.next.UpdateSubjectStatus(ctx, subjectID, from, to, at)
+	done(err)
+	return err
+}
+
+func (r *instrumentedSubjectRepository) FindExpiredSubjects(ctx context.Context, now time.Time) ([]subjectmodel.Subject, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "subjects", "FindExpiredSubjects")
+	result, err := r.next.FindExpiredSubjects(ctx, now)
+	done(err)
+	return result, err
+}
@@ -1,9 +1,11 @@
 package subjectrepository
 
 import (
+	"BackendCoursyclopedia/apperror"
 	"BackendCoursyclopedia/db"
 	"BackendCoursyclopedia/model/subjectmodel"
 	"context"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -20,6 +22,10 @@ type ISubjectRepository interface {
 	UpdateSubject(ctx context.Context, subjectId primitive.ObjectID, updates bson.M) error
 	UpdateLikes(ctx context.Context, subjectID primitive.ObjectID, likes int) error
 	AddEmailToLikeList(ctx context.Context, subjectID primitive.ObjectID, userEmail string) error
+	EachSubjectByIDs(ctx context.Context, subjectIDs []primitive.ObjectID, fn func(subjectmodel.Subject) error) error
+	FindSubjectCodes(ctx context.Context) (map[primitive.ObjectID]string, error)
+	UpdateSubjectStatus(ctx context.Context, subjectID primitive.ObjectID, from, to string, at time.Time) error
+	FindExpiredSubjects(ctx context.Context, now time.Time) ([]subjectmodel.Subject, error)
 }
 
 type SubjectRepository struct {
@@ -57,12 +63,12 @@ func (r *SubjectRepository) FindSubjectbyID(ctx context.Context, subjectId strin
 
 	objID, err := primitive.ObjectIDFromHex(subjectId)
 	if err != nil {
-		return nil, err
+		return nil, apperror.InvalidID("subject", err)
 	}
 
 	err = collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&subject)
 	if err != nil {
-		return nil, err
+		return nil, apperror.FromMongo(err, "subject")
 	}
 	return &subject, nil
 }
@@ -93,11 +99,63 @@ func (r *SubjectRepository) FindSubjectsByIDs(ctx context.Context, subjectIDs []
 	return subjects, nil
 }
 
+// EachSubjectByIDs calls fn for every subject in subjectIDs, ordered by
+// subject code, without holding the whole result in memory. It stops at the
+// first error fn returns.
+func (r *SubjectRepository) EachSubjectByIDs(ctx context.Context, subjectIDs []primitive.ObjectID, fn func(subjectmodel.Subject) error) error {
+	collection := db.GetCollection("subjects")
+
+	filter := bson.M{"_id": bson.M{"$in": subjectIDs}}
+	opts := options.Find().SetSort(bson.D{{Key: "subjectCode", Value: 1}})
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var subject subjectmodel.Subject
+		if err := cursor.Decode(&subject); err != nil {
+			return err
+		}
+		if err := fn(subject); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// FindSubjectCodes maps every subject ID to its code.
+func (r *SubjectRepository) FindSubjectCodes(ctx context.Context) (map[primitive.ObjectID]string, error) {
+	collection := db.GetCollection("subjects")
+
+	opts := options.Find().SetProjection(bson.M{"subjectCode": 1})
+	cursor, err := collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	codes := map[primitive.ObjectID]string{}
+	for cursor.Next(ctx) {
+		var subject subjectmodel.Subject
+		if err := cursor.Decode(&subject); err != nil {
+			return nil, err
+		}
+		codes[subject.ID] = subject.SubjectCode
+	}
+
+	return codes, cursor.Err()
+}
+
+// CreateSubject inserts subject, stamping LastUpdated.
 func (r *SubjectRepository) CreateSubject(ctx context.Context, subject subjectmodel.Subject) (primitive.ObjectID, error) {
 	collection := db.GetCollection("subjects")
+	subject.LastUpdated = primitive.NewDateTimeFromTime(time.Now())
 	result, err := collection.InsertOne(ctx, subject)
 	if err != nil {
-		return primitive.NilObjectID, err
+		return primitive.NilObjectID, apperror.FromMongo(err, "subject")
 	}
 	return result.InsertedID.(primitive.ObjectID), nil
 }
@@ -105,20 +163,38 @@ func (r *SubjectRepository) CreateSubject(ctx context.Context, subject subjectmo
 func (r *SubjectRepository) DeleteSubject(ctx context.Context, subjectId primitive.ObjectID) error {
 	collection := db.GetCollection("subjects")
 
-	_, err := collection.DeleteOne(ctx, bson.M{"_id": subjectId})
-	return err
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": subjectId})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return apperror.NotFound("subject not found")
+	}
+	return nil
 }
 
+// UpdateSubject sets the given fields and stamps last_updated. Status
+// changes go through UpdateSubjectStatus.
 func (r *SubjectRepository) UpdateSubject(ctx context.Context, subjectId primitive.ObjectID, updates bson.M) error {
 	collection := db.GetCollection("subjects")
 
-	_, err := collection.UpdateOne(
+	set := bson.M{"last_updated": primitive.NewDateTimeFromTime(time.Now())}
+	for k, v := range updates {
+		set[k] = v
+	}
+	result, err := collection.UpdateOne(
 		ctx,
 		bson.M{"_id": subjectId},
-		bson.M{"$set": updates},
+		bson.M{"$set": set},
 	)
+	if err != nil {
+		return apperror.FromMongo(err, "subject")
+	}
+	if result.MatchedCount == 0 {
+		return apperror.NotFound("subject not found")
+	}
 
-	return err
+	return nil
 }
 
 func (r *SubjectRepository) UpdateLikes(ctx context.Context, subjectID primitive.ObjectID, likes int) error {
@@ -170,3 +246,54 @@ func (r *SubjectRepository) AddEmailToLikeList(ctx context.Context, subjectID pr
 
 	return nil
 }
+
+// UpdateSubjectStatus moves a subject from one status to another, failing
+// with a conflict if its status is no longer from. Becoming AVAILABLE
+// restarts the availability duration.
+func (r *SubjectRepository) UpdateSubjectStatus(ctx context.Context, subjectID primitive.ObjectID, from, to string, at time.Time) error {
+	collection := db.GetCollection("subjects")
+
+	set := bson.M{"subjectStatus": to, "last_updated": primitive.NewDateTimeFromTime(at)}
+	if to == subjectmodel.StatusAvailable {
+		set["available_since"] = primitive.NewDateTimeFromTime(at)
+	}
+	result, err := collection.UpdateOne(ctx, bson.M{"_id": subjectID, "subjectStatus": from}, bson.M{"$set": set})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		if _, err := r.FindSubjectbyID(ctx, subjectID.Hex()); err != nil {
+			return err
+		}
+		return apperror.Conflict("subject status was changed concurrently; reload and retry")
+	}
+	return nil
+}
+
+// FindExpiredSubjects returns the AVAILABLE subjects whose availability
+// duration, in days since they became available, has run out by now.
+func (r *SubjectRepository) FindExpiredSubjects(ctx context.Context, now time.Time) ([]subjectmodel.Subject, error) {
+	collection := db.GetCollection("subjects")
+	subjects := []subjectmodel.Subject{}
+
+	const day = 24 * 60 * 60 * 1000
+	filter := bson.M{
+		"subjectStatus":      subjectmodel.StatusAvailable,
+		"available_duration": bson.M{"$gt": 0},
+		"available_since":    bson.M{"$exists": true},
+		"$expr": bson.M{"$lte": bson.A{
+			bson.M{"$add": bson.A{"$available_since", bson.M{"$multiply": bson.A{"$available_duration", day}}}},
+			now,
+		}},
+	}
+	cursor, err := collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &subjects); err != nil {
+		return nil, err
+	}
+	return subjects, nil
+}
@@ -0,0 +1,61 @@
+package termrepository
+
+import (
+	"BackendCoursyclopedia/instrumentation"
+	"BackendCoursyclopedia/model/termmodel"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type instrumentedTermRepository struct {
+	next ITermRepository
+}
+
+// NewInstrumentedTermRepository wraps next so every call records MongoDB
+// latency and errors under the "terms" repository label.
+func NewInstrumentedTermRepository(next ITermRepository) ITermRepository {
+	return &instrumentedTermRepository{next: next}
+}
+
+func (r *instrumentedTermRepository) CreateTerm(ctx context.Context, term termmodel.Term) (primitive.ObjectID, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "terms", "CreateTerm")
+	result, err := r.next.CreateTerm(ctx, term)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedTermRepository) FindTerms(ctx context.Context) ([]termmodel.Term, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "terms", "FindTerms")
+	result, err := r.next.FindTerms(ctx)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedTermRepository) FindTermByID(ctx context.Context, termID string) (*termmodel.Term, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "terms", "FindTermByID")
+	result, err := r.next.FindTermByID(ctx, termID)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedTermRepository) FindTermsByIDs(ctx context.Context, termIDs []primitive.ObjectID) ([]termmodel.Term, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "terms", "FindTermsByIDs")
+	result, err := r.next.FindTermsByIDs(ctx, termIDs)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedTermRepository) UpdateTerm(ctx context.Context, term termmodel.Term) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "terms", "UpdateTerm")
+	err := r.next.UpdateTerm(ctx, term)
+	done(err)
+	return err
+}
+
+func (r *instrumentedTermRepository) DeleteTerm(ctx context.Context, termID primitive.ObjectID) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "terms", "DeleteTerm")
+	err := r.next.DeleteTerm(ctx, termID)
+	done(err)
+	return err
+}
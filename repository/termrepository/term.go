@@ -0,0 +1,117 @@
+package termrepository
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/db"
+	"BackendCoursyclopedia/model/termmodel"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ITermRepository interface {
+	CreateTerm(ctx context.Context, term termmodel.Term) (primitive.ObjectID, error)
+	FindTerms(ctx context.Context) ([]termmodel.Term, error)
+	FindTermByID(ctx context.Context, termID string) (*termmodel.Term, error)
+	FindTermsByIDs(ctx context.Context, termIDs []primitive.ObjectID) ([]termmodel.Term, error)
+	UpdateTerm(ctx context.Context, term termmodel.Term) error
+	DeleteTerm(ctx context.Context, termID primitive.ObjectID) error
+}
+
+type TermRepository struct {
+	DB *mongo.Client
+}
+
+func NewTermRepository(db *mongo.Client) ITermRepository {
+	return &TermRepository{
+		DB: db,
+	}
+}
+
+func (r *TermRepository) CreateTerm(ctx context.Context, term termmodel.Term) (primitive.ObjectID, error) {
+	collection := db.GetCollection("terms")
+
+	result, err := collection.InsertOne(ctx, term)
+	if err != nil {
+		return primitive.NilObjectID, apperror.FromMongo(err, "term")
+	}
+	return result.InsertedID.(primitive.ObjectID), nil
+}
+
+// FindTerms returns every term, most recent first.
+func (r *TermRepository) FindTerms(ctx context.Context) ([]termmodel.Term, error) {
+	collection := db.GetCollection("terms")
+	terms := []termmodel.Term{}
+
+	opts := options.Find().SetSort(bson.D{{Key: "academicYear", Value: -1}, {Key: "semester", Value: -1}})
+	cursor, err := collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &terms); err != nil {
+		return nil, err
+	}
+	return terms, nil
+}
+
+func (r *TermRepository) FindTermByID(ctx context.Context, termID string) (*termmodel.Term, error) {
+	collection := db.GetCollection("terms")
+	var term termmodel.Term
+
+	objID, err := primitive.ObjectIDFromHex(termID)
+	if err != nil {
+		return nil, apperror.InvalidID("term", err)
+	}
+
+	if err := collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&term); err != nil {
+		return nil, apperror.FromMongo(err, "term")
+	}
+	return &term, nil
+}
+
+func (r *TermRepository) FindTermsByIDs(ctx context.Context, termIDs []primitive.ObjectID) ([]termmodel.Term, error) {
+	collection := db.GetCollection("terms")
+	terms := []termmodel.Term{}
+
+	cursor, err := collection.Find(ctx, bson.M{"_id": bson.M{"$in": termIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &terms); err != nil {
+		return nil, err
+	}
+	return terms, nil
+}
+
+func (r *TermRepository) UpdateTerm(ctx context.Context, term termmodel.Term) error {
+	collection := db.GetCollection("terms")
+
+	result, err := collection.ReplaceOne(ctx, bson.M{"_id": term.ID}, term)
+	if err != nil {
+		return apperror.FromMongo(err, "term")
+	}
+	if result.MatchedCount == 0 {
+		return apperror.NotFound("term not found")
+	}
+	return nil
+}
+
+func (r *TermRepository) DeleteTerm(ctx context.Context, termID primitive.ObjectID) error {
+	collection := db.GetCollection("terms")
+
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": termID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return apperror.NotFound("term not found")
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package userrepo
+
+import (
+	"BackendCoursyclopedia/instrumentation"
+	"BackendCoursyclopedia/model/usermodel"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type instrumentedUserRepository struct {
+	next IUserRepository
+}
+
+// NewInstrumentedUserRepository wraps next so every call records MongoDB
+// latency and errors under the "users" repository label.
+func NewInstrumentedUserRepository(next IUserRepository) IUserRepository {
+	return &instrumentedUserRepository{next: next}
+}
+
+func (r *instrumentedUserRepository) FindAllUsers(ctx context.Context) ([]usermodel.User, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "users", "FindAllUsers")
+	result, err := r.next.FindAllUsers(ctx)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedUserRepository) FindUserByID(ctx context.Context, userID string) (*usermodel.User, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "users", "FindUserByID")
+	result, err := r.next.FindUserByID(ctx, userID)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedUserRepository) CreateUser(ctx context.Context, user usermodel.User) (*usermodel.User, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "users", "CreateUser")
+	result, err := r.next.CreateUser(ctx, user)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedUserRepository) DeleteUserByID(ctx context.Context, userID string) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "users", "DeleteUserByID")
+	err := r.next.DeleteUserByID(ctx, userID)
+	done(err)
+	return err
+}
+
+func (r *instrumentedUserRepository) UpdateUserByID(ctx context.Context, userID primitive.ObjectID, fields bson.M) (*usermodel.User, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "users", "UpdateUserByID")
+	result, err := r.next.UpdateUserByID(ctx, userID, fields)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedUserRepository) GetUserByEmail(ctx context.Context, email string) (*usermodel.User, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "users", "GetUserByEmail")
+	result, err := r.next.GetUserByEmail(ctx, email)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedUserRepository) DropAllUsers(ctx context.Context) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "users", "DropAllUsers")
+	err := r.next.DropAllUsers(ctx)
+	done(err)
+	return err
+}
+
+func (r *instrumentedUserRepository) GetUserByEmailLogin(ctx context.Context, email string) (*usermodel.User, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "users", "GetUserByEmailLogin")
+	result, err := r.next.GetUserByEmailLogin(ctx, email)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedUserRepository) UpdatePassword(ctx context.Context, userID primitive.ObjectID, hashedPassword string) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "users", "UpdatePassword")
+	err := r.next.UpdatePassword(ctx, userID, hashedPassword)
+	done(err)
+	return err
+}
+
+func (r *instrumentedUserRepository) UpdateProgram(ctx context.Context, userID primitive.ObjectID, majorID primitive.ObjectID, intakeYear int) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "users", "UpdateProgram")
+	err := r.next.UpdateProgram(ctx, userID, majorID, intakeYear)
+	done(err)
+	return err
+}
+
+func (r *instrumentedUserRepository) UpdateCompletedSubjects(ctx context.Context, userID primitive.ObjectID, subjects []usermodel.CompletedSubject) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "users", "UpdateCompletedSubjects")
+	err := r.next.UpdateCompletedSubjects(ctx, userID, subjects)
+	done(err)
+	return err
+}
+
+func (r *instrumentedUserRepository) UpdateTimetableSections(ctx context.Context, userID primitive.ObjectID, sectionIDs []primitive.ObjectID) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "users", "UpdateTimetableSections")
+	err := r.next.UpdateTimetableSections(ctx, userID, sectionIDs)
+	done(err)
+	return err
+}
+
+func (r *instrumentedUserRepository) SetCalendarTokenHash(ctx context.Context, userID primitive.ObjectID, tokenHash string) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "users", "SetCalendarTokenHash")
+	err := r.next.SetCalendarTokenHash(ctx, userID, tokenHash)
+	done(err)
+	return err
+}
+
+func (r *instrumentedUserRepository) FindUserByCalendarTokenHash(ctx context.Context, tokenHash string) (*usermodel.User, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "users", "FindUserByCalendarTokenHash")
+	result, err := r.next.FindUserByCalendarTokenHash(ctx, tokenHash)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedUserRepository) FindUsersByWishlist(ctx context.Context, subjectID primitive.ObjectID) ([]usermodel.User, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "users", "FindUsersByWishlist")
+	result, err := r.next.FindUsersByWishlist(ctx, subjectID)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedUserRepository) UpdateNotificationPreferences(ctx context.Context, userID primitive.ObjectID, prefs usermodel.NotificationPreferences) error {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "users", "UpdateNotificationPreferences")
+	err := r.next.UpdateNotificationPreferences(ctx, userID, prefs)
+	done(err)
+	return err
+}
+
+func (r *instrumentedUserRepository) ActivatePendingUser(ctx context.Context, userID primitive.ObjectID) (bool, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "users", "ActivatePendingUser")
+	result, err := r.next.ActivatePendingUser(ctx, userID)
+	done(err)
+	return result, err
+}
+
+func (r *instrumentedUserRepository) SetUserStatus(ctx context.Context, userID primitive.ObjectID, from []string, status string, change usermodel.StatusChange, revokeTokens bool) (*usermodel.User, error) {
+	ctx, done := instrumentation.StartRepositoryOp(ctx, "users", "SetUserStatus")
+	result, err := r.next.SetUserStatus(ctx, userID, from, status, change, revokeTokens)
+	done(err)
+	return result, err
+}
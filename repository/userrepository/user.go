@@ -1,14 +1,16 @@
 package userrepo
 
 import (
+	"BackendCoursyclopedia/apperror"
 	"BackendCoursyclopedia/db"
 	"BackendCoursyclopedia/model/usermodel"
 	"context"
-	"errors"
+	"strings"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type IUserRepository interface {
@@ -16,10 +18,37 @@ type IUserRepository interface {
 	FindUserByID(ctx context.Context, userID string) (*usermodel.User, error)
 	CreateUser(ctx context.Context, user usermodel.User) (*usermodel.User, error)
 	DeleteUserByID(ctx context.Context, userID string) error
-	UpdateUserByID(ctx context.Context, userID string, updateUser usermodel.User) (*usermodel.User, error)
+	UpdateUserByID(ctx context.Context, userID primitive.ObjectID, fields bson.M) (*usermodel.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*usermodel.User, error)
 	DropAllUsers(ctx context.Context) error
 	GetUserByEmailLogin(ctx context.Context, email string) (*usermodel.User, error)
+	UpdatePassword(ctx context.Context, userID primitive.ObjectID, hashedPassword string) error
+	UpdateProgram(ctx context.Context, userID primitive.ObjectID, majorID primitive.ObjectID, intakeYear int) error
+	UpdateCompletedSubjects(ctx context.Context, userID primitive.ObjectID, subjects []usermodel.CompletedSubject) error
+	UpdateTimetableSections(ctx context.Context, userID primitive.ObjectID, sectionIDs []primitive.ObjectID) error
+	SetCalendarTokenHash(ctx context.Context, userID primitive.ObjectID, tokenHash string) error
+	FindUserByCalendarTokenHash(ctx context.Context, tokenHash string) (*usermodel.User, error)
+	FindUsersByWishlist(ctx context.Context, subjectID primitive.ObjectID) ([]usermodel.User, error)
+	UpdateNotificationPreferences(ctx context.Context, userID primitive.ObjectID, prefs usermodel.NotificationPreferences) error
+	ActivatePendingUser(ctx context.Context, userID primitive.ObjectID) (bool, error)
+	SetUserStatus(ctx context.Context, userID primitive.ObjectID, from []string, status string, change usermodel.StatusChange, revokeTokens bool) (*usermodel.User, error)
+}
+
+// normalizeEmail is applied to every email stored or looked up, so addresses
+// differing only in case or surrounding spaces name the same account.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// emailFilter and emailLookup match an email through the case-insensitive
+// unique index, which also finds accounts stored before emails were
+// normalized.
+func emailFilter(email string) bson.M {
+	return bson.M{"email": normalizeEmail(email)}
+}
+
+func emailLookup() *options.FindOneOptions {
+	return options.FindOne().SetCollation(db.CaseInsensitive)
 }
 
 type UserRepository struct {
@@ -32,11 +61,12 @@ func NewUserRepository(db *mongo.Client) IUserRepository {
 	}
 }
 
+// FindAllUsers returns every user except deleted ones.
 func (r *UserRepository) FindAllUsers(ctx context.Context) ([]usermodel.User, error) {
 	collection := db.GetCollection("users")
 
 	var users []usermodel.User
-	cursor, err := collection.Find(ctx, bson.M{})
+	cursor, err := collection.Find(ctx, bson.M{"status": bson.M{"$ne": usermodel.StatusDeleted}})
 	if err != nil {
 		return nil, err
 	}
@@ -60,12 +90,12 @@ func (r *UserRepository) FindUserByID(ctx context.Context, userID string) (*user
 	// Convert string to ObjectID
 	objID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
-		return nil, err
+		return nil, apperror.InvalidID("user", err)
 	}
 
 	err = collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&user)
 	if err != nil {
-		return nil, err
+		return nil, apperror.FromMongo(err, "user")
 	}
 	return &user, nil
 }
@@ -73,19 +103,19 @@ func (r *UserRepository) FindUserByID(ctx context.Context, userID string) (*user
 func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*usermodel.User, error) {
 	collection := db.GetCollection("users")
 	var user usermodel.User
-	filter := bson.M{"email": email}
-	err := collection.FindOne(ctx, filter).Decode(&user)
+	err := collection.FindOne(ctx, emailFilter(email), emailLookup()).Decode(&user)
 	if err != nil {
-		return nil, err
+		return nil, apperror.FromMongo(err, "user")
 	}
 	return &user, nil
 }
 
 func (r *UserRepository) CreateUser(ctx context.Context, user usermodel.User) (*usermodel.User, error) {
 	collection := db.GetCollection("users")
+	user.Email = normalizeEmail(user.Email)
 	result, err := collection.InsertOne(ctx, user)
 	if err != nil {
-		return nil, err
+		return nil, apperror.FromMongo(err, "user")
 	}
 
 	user.ID = result.InsertedID.(primitive.ObjectID)
@@ -99,59 +129,207 @@ func (r *UserRepository) DeleteUserByID(ctx context.Context, userID string) erro
 	// Convert string to ObjectID
 	objID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
-		return err
+		return apperror.InvalidID("user", err)
 	}
 
-	_, err = collection.DeleteOne(ctx, bson.M{"_id": objID})
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": objID})
 	if err != nil {
 		return err
 	}
+	if result.DeletedCount == 0 {
+		return apperror.NotFound("user not found")
+	}
 
 	return nil
 }
 
-func (r *UserRepository) UpdateUserByID(ctx context.Context, userID string, updateUser usermodel.User) (*usermodel.User, error) {
+// UpdateUserByID sets the given fields and returns the updated user. The
+// caller decides which fields may be set.
+func (r *UserRepository) UpdateUserByID(ctx context.Context, userID primitive.ObjectID, fields bson.M) (*usermodel.User, error) {
 	collection := db.GetCollection("users")
 
-	// Convert string to ObjectID
-	objID, err := primitive.ObjectIDFromHex(userID)
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var user usermodel.User
+	err := collection.FindOneAndUpdate(ctx, bson.M{"_id": userID}, bson.M{"$set": fields}, opts).Decode(&user)
 	if err != nil {
-		return nil, err
+		return nil, apperror.FromMongo(err, "user")
 	}
+	return &user, nil
+}
+
+func (r *UserRepository) DropAllUsers(ctx context.Context) error {
+	collection := db.GetCollection("users")
 
-	// Create an update document with the fields to be updated
-	update := bson.M{
-		"$set": updateUser,
+	if err := collection.Drop(ctx); err != nil {
+		return err
 	}
 
-	result, err := collection.UpdateOne(ctx, bson.M{"_id": objID}, update)
+	return nil
+}
+
+func (r *UserRepository) GetUserByEmailLogin(ctx context.Context, email string) (*usermodel.User, error) {
+	collection := db.GetCollection("users")
+	var user usermodel.User
+	if err := collection.FindOne(ctx, emailFilter(email), emailLookup()).Decode(&user); err != nil {
+		return nil, apperror.FromMongo(err, "user")
+	}
+	return &user, nil
+}
+
+func (r *UserRepository) UpdatePassword(ctx context.Context, userID primitive.ObjectID, hashedPassword string) error {
+	collection := db.GetCollection("users")
+
+	update := bson.M{"$set": bson.M{"password": hashedPassword}, "$inc": bson.M{"tokenVersion": 1}}
+	result, err := collection.UpdateOne(ctx, bson.M{"_id": userID}, update)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return apperror.NotFound("user not found")
 	}
+	return nil
+}
 
+func (r *UserRepository) UpdateProgram(ctx context.Context, userID primitive.ObjectID, majorID primitive.ObjectID, intakeYear int) error {
+	collection := db.GetCollection("users")
+
+	update := bson.M{"$set": bson.M{"majorId": majorID, "intakeYear": intakeYear}}
+	result, err := collection.UpdateOne(ctx, bson.M{"_id": userID}, update)
+	if err != nil {
+		return err
+	}
 	if result.MatchedCount == 0 {
-		return nil, errors.New("no user found with the given ID")
+		return apperror.NotFound("user not found")
 	}
+	return nil
+}
 
-	return &updateUser, nil
+func (r *UserRepository) UpdateCompletedSubjects(ctx context.Context, userID primitive.ObjectID, subjects []usermodel.CompletedSubject) error {
+	collection := db.GetCollection("users")
+
+	update := bson.M{"$set": bson.M{"completedSubjects": subjects}}
+	result, err := collection.UpdateOne(ctx, bson.M{"_id": userID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return apperror.NotFound("user not found")
+	}
+	return nil
 }
 
-func (r *UserRepository) DropAllUsers(ctx context.Context) error {
+func (r *UserRepository) UpdateTimetableSections(ctx context.Context, userID primitive.ObjectID, sectionIDs []primitive.ObjectID) error {
 	collection := db.GetCollection("users")
 
-	if err := collection.Drop(ctx); err != nil {
+	update := bson.M{"$set": bson.M{"timetableSections": sectionIDs}}
+	result, err := collection.UpdateOne(ctx, bson.M{"_id": userID}, update)
+	if err != nil {
 		return err
 	}
+	if result.MatchedCount == 0 {
+		return apperror.NotFound("user not found")
+	}
+	return nil
+}
+
+// SetCalendarTokenHash stores the hash of the user's calendar subscription
+// token, replacing any previous one. An empty hash revokes the subscription.
+func (r *UserRepository) SetCalendarTokenHash(ctx context.Context, userID primitive.ObjectID, tokenHash string) error {
+	collection := db.GetCollection("users")
 
+	update := bson.M{"$set": bson.M{"calendarTokenHash": tokenHash}}
+	if tokenHash == "" {
+		update = bson.M{"$unset": bson.M{"calendarTokenHash": ""}}
+	}
+	result, err := collection.UpdateOne(ctx, bson.M{"_id": userID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return apperror.NotFound("user not found")
+	}
 	return nil
 }
 
-func (r *UserRepository) GetUserByEmailLogin(ctx context.Context, email string) (*usermodel.User, error) {
+func (r *UserRepository) FindUserByCalendarTokenHash(ctx context.Context, tokenHash string) (*usermodel.User, error) {
 	collection := db.GetCollection("users")
 	var user usermodel.User
-	filter := bson.M{"email": email}
-	if err := collection.FindOne(ctx, filter).Decode(&user); err != nil {
+
+	if err := collection.FindOne(ctx, bson.M{"calendarTokenHash": tokenHash}).Decode(&user); err != nil {
+		return nil, apperror.FromMongo(err, "calendar")
+	}
+	return &user, nil
+}
+
+// FindUsersByWishlist returns the users who have subjectID on their
+// wishlist.
+func (r *UserRepository) FindUsersByWishlist(ctx context.Context, subjectID primitive.ObjectID) ([]usermodel.User, error) {
+	collection := db.GetCollection("users")
+	users := []usermodel.User{}
+
+	cursor, err := collection.Find(ctx, bson.M{"wishlists": subjectID})
+	if err != nil {
 		return nil, err
 	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *UserRepository) UpdateNotificationPreferences(ctx context.Context, userID primitive.ObjectID, prefs usermodel.NotificationPreferences) error {
+	collection := db.GetCollection("users")
+
+	update := bson.M{"$set": bson.M{"notifications": prefs}}
+	result, err := collection.UpdateOne(ctx, bson.M{"_id": userID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return apperror.NotFound("user not found")
+	}
+	return nil
+}
+
+// ActivatePendingUser moves a pending user to active and reports whether it
+// did. Users in any other status are left alone.
+func (r *UserRepository) ActivatePendingUser(ctx context.Context, userID primitive.ObjectID) (bool, error) {
+	collection := db.GetCollection("users")
+
+	filter := bson.M{"_id": userID, "status": usermodel.StatusPending}
+	result, err := collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"status": usermodel.StatusActive}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// SetUserStatus moves the user to status if their current status is one of
+// from, and returns the updated user. It returns NotFound if the user does
+// not exist or is in another status. revokeTokens invalidates the tokens
+// issued until change.ChangedAt.
+func (r *UserRepository) SetUserStatus(ctx context.Context, userID primitive.ObjectID, from []string, status string, change usermodel.StatusChange, revokeTokens bool) (*usermodel.User, error) {
+	collection := db.GetCollection("users")
+	var user usermodel.User
+
+	update := bson.M{"$set": bson.M{"status": status, "statusChange": change}}
+	if revokeTokens {
+		update["$inc"] = bson.M{"tokenVersion": 1}
+	}
+	// An empty status in from also matches users stored without one.
+	statuses := bson.A{}
+	for _, s := range from {
+		statuses = append(statuses, s)
+		if s == "" {
+			statuses = append(statuses, nil)
+		}
+	}
+	filter := bson.M{"_id": userID, "status": bson.M{"$in": statuses}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	if err := collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&user); err != nil {
+		return nil, apperror.FromMongo(err, "user")
+	}
 	return &user, nil
 }
@@ -0,0 +1,90 @@
+// Package requisites resolves the PreRequisite and CoRequisite lists of
+// subjects, which may hold either subject codes or subject IDs, into a
+// graph keyed by subject code.
+package requisites
+
+import (
+	"BackendCoursyclopedia/model/subjectmodel"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Catalog struct {
+	byID   map[primitive.ObjectID]subjectmodel.Subject
+	byCode map[string]subjectmodel.Subject
+}
+
+func NewCatalog(subjects []subjectmodel.Subject) *Catalog {
+	c := &Catalog{
+		byID:   make(map[primitive.ObjectID]subjectmodel.Subject, len(subjects)),
+		byCode: make(map[string]subjectmodel.Subject, len(subjects)),
+	}
+	for _, s := range subjects {
+		c.byID[s.ID] = s
+		c.byCode[s.SubjectCode] = s
+	}
+	return c
+}
+
+func (c *Catalog) ByID(id primitive.ObjectID) (subjectmodel.Subject, bool) {
+	s, ok := c.byID[id]
+	return s, ok
+}
+
+func (c *Catalog) ByCode(code string) (subjectmodel.Subject, bool) {
+	s, ok := c.byCode[code]
+	return s, ok
+}
+
+// Code resolves a requisite reference to a subject code. References that are
+// neither a known ID nor a known code are returned unchanged.
+func (c *Catalog) Code(ref string) string {
+	if id, err := primitive.ObjectIDFromHex(ref); err == nil {
+		if s, ok := c.byID[id]; ok {
+			return s.SubjectCode
+		}
+	}
+	return ref
+}
+
+func (c *Catalog) Prerequisites(s subjectmodel.Subject) []string {
+	return c.codes(s.PreRequisite)
+}
+
+func (c *Catalog) CoRequisites(s subjectmodel.Subject) []string {
+	return c.codes(s.CoRequisite)
+}
+
+func (c *Catalog) codes(refs []string) []string {
+	codes := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		codes = append(codes, c.Code(ref))
+	}
+	return codes
+}
+
+// MissingChain returns every prerequisite of code, direct or indirect, that
+// is not in passed, ordered so that each subject comes after its own
+// prerequisites. Cycles are cut rather than reported.
+func (c *Catalog) MissingChain(code string, passed map[string]bool) []string {
+	var chain []string
+	visited := map[string]bool{code: true}
+
+	var visit func(code string)
+	visit = func(code string) {
+		s, ok := c.byCode[code]
+		if !ok {
+			return
+		}
+		for _, pre := range c.Prerequisites(s) {
+			if visited[pre] || passed[pre] {
+				continue
+			}
+			visited[pre] = true
+			visit(pre)
+			chain = append(chain, pre)
+		}
+	}
+	visit(code)
+	return chain
+}
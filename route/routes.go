@@ -1,21 +1,62 @@
 package route
 
 import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
 	"BackendCoursyclopedia/db"
 	"BackendCoursyclopedia/handler/auditloghandler"
+	"BackendCoursyclopedia/handler/curriculumhandler"
+	"BackendCoursyclopedia/handler/degreeaudithandler"
+	"BackendCoursyclopedia/handler/exporthandler"
 	"BackendCoursyclopedia/handler/facultyhandler"
+	"BackendCoursyclopedia/handler/healthhandler"
+	"BackendCoursyclopedia/handler/integrityhandler"
+	"BackendCoursyclopedia/handler/jobhandler"
 	"BackendCoursyclopedia/handler/majorhandler"
+	"BackendCoursyclopedia/handler/notificationhandler"
+	"BackendCoursyclopedia/handler/offeringhandler"
+	"BackendCoursyclopedia/handler/sectionhandler"
+	"BackendCoursyclopedia/handler/studyplanhandler"
 	"BackendCoursyclopedia/handler/subjecthandler"
+	"BackendCoursyclopedia/handler/termhandler"
+	"BackendCoursyclopedia/handler/timetablehandler"
 	"BackendCoursyclopedia/handler/userhandler"
 
+	"BackendCoursyclopedia/logger"
+	"BackendCoursyclopedia/mail"
+	"BackendCoursyclopedia/metrics"
 	"BackendCoursyclopedia/middleware"
+	"BackendCoursyclopedia/migrations"
+	"BackendCoursyclopedia/repository/authtokenrepository"
+	"BackendCoursyclopedia/repository/curriculumrepository"
 	"BackendCoursyclopedia/repository/facultyrepository"
+	"BackendCoursyclopedia/repository/integrityrepository"
+	"BackendCoursyclopedia/repository/jobrepository"
 	"BackendCoursyclopedia/repository/majorrepository"
+	"BackendCoursyclopedia/repository/notificationrepository"
+	"BackendCoursyclopedia/repository/offeringrepository"
+	"BackendCoursyclopedia/repository/sectionrepository"
+	"BackendCoursyclopedia/repository/studyplanrepository"
 	"BackendCoursyclopedia/repository/subjectrepository"
+	"BackendCoursyclopedia/repository/termrepository"
 	userrepo "BackendCoursyclopedia/repository/userrepository"
+	"BackendCoursyclopedia/service/curriculumservice"
+	"BackendCoursyclopedia/service/degreeauditservice"
+	"BackendCoursyclopedia/service/exportservice"
 	"BackendCoursyclopedia/service/facultyservice"
+	"BackendCoursyclopedia/service/integrityservice"
+	"BackendCoursyclopedia/service/jobservice"
 	"BackendCoursyclopedia/service/majorservice"
+	"BackendCoursyclopedia/service/notificationservice"
+	"BackendCoursyclopedia/service/offeringservice"
+	"BackendCoursyclopedia/service/sectionservice"
+	"BackendCoursyclopedia/service/studyplanservice"
 	"BackendCoursyclopedia/service/subjectservice"
+	"BackendCoursyclopedia/service/termservice"
+	"BackendCoursyclopedia/service/timetableservice"
 
 	auditlogrepo "BackendCoursyclopedia/repository/auditlogrepository"
 
@@ -23,39 +64,143 @@ import (
 	usersvc "BackendCoursyclopedia/service/userservice"
 
 	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 func Setup(app *fiber.App) {
 	db.ConnectDB()
 
-	userRepository := userrepo.NewUserRepository(db.DB)
-	majorRepository := majorrepository.NewMajorRepository(db.DB)
-	facultyRepository := facultyrepository.NewFacultyRepository(db.DB)
-	auditlogRepository := auditlogrepo.NewAuditLogRepository(db.DB)
-	subjectRepository := subjectrepository.NewSubjectRepository(db.DB)
+	startupCtx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	if os.Getenv("MIGRATE_ON_START") == "true" {
+		applied, err := migrations.NewRunner(db.GetDB()).Up(startupCtx)
+		switch {
+		case errors.Is(err, migrations.ErrLocked):
+			logger.L().Info("another instance is running migrations; none applied here")
+		case err != nil:
+			logger.Fatal("failed to run migrations", "error", err)
+		default:
+			logger.L().Info("migrations applied", "count", len(applied))
+		}
+	}
+	if err := db.EnsureIndexes(startupCtx); err != nil {
+		logger.L().Error("some indexes could not be created; duplicates may exist", "error", err)
+	}
+	cancel()
+
+	userRepository := userrepo.NewInstrumentedUserRepository(userrepo.NewUserRepository(db.DB))
+	majorRepository := majorrepository.NewInstrumentedMajorRepository(majorrepository.NewMajorRepository(db.DB))
+	facultyRepository := facultyrepository.NewInstrumentedFacultyRepository(facultyrepository.NewFacultyRepository(db.DB))
+	auditlogRepository := auditlogrepo.NewInstrumentedAuditLogRepository(auditlogrepo.NewAuditLogRepository(db.DB))
+	subjectRepository := subjectrepository.NewInstrumentedSubjectRepository(subjectrepository.NewSubjectRepository(db.DB))
+	curriculumRepository := curriculumrepository.NewInstrumentedCurriculumRepository(curriculumrepository.NewCurriculumRepository(db.DB))
+	studyPlanRepository := studyplanrepository.NewInstrumentedStudyPlanRepository(studyplanrepository.NewStudyPlanRepository(db.DB))
+	termRepository := termrepository.NewInstrumentedTermRepository(termrepository.NewTermRepository(db.DB))
+	offeringRepository := offeringrepository.NewInstrumentedOfferingRepository(offeringrepository.NewOfferingRepository(db.DB))
+	sectionRepository := sectionrepository.NewInstrumentedSectionRepository(sectionrepository.NewSectionRepository(db.DB))
+	integrityRepository := integrityrepository.NewInstrumentedIntegrityRepository(integrityrepository.NewIntegrityRepository(db.DB))
+	jobRepository := jobrepository.NewInstrumentedJobRepository(jobrepository.NewJobRepository(db.DB))
+	notificationRepository := notificationrepository.NewInstrumentedNotificationRepository(notificationrepository.NewNotificationRepository(db.DB))
+	authTokenRepository := authtokenrepository.NewInstrumentedAuthTokenRepository(authtokenrepository.NewAuthTokenRepository(db.DB))
+
+	transport, err := mail.FromEnv()
+	if err != nil {
+		logger.Fatal("failed to configure mail transport", "error", err)
+	}
 
-	userService := usersvc.NewUserService(userRepository)
+	notificationService := notificationservice.NewNotificationService(notificationRepository, userRepository, transport)
+	userService := usersvc.NewUserService(userRepository, authTokenRepository, notificationService)
 	facultyService := facultyservice.NewFacultyService(facultyRepository, majorRepository)
 	majorService := majorservice.NewMajorService(majorRepository, facultyRepository, subjectRepository)
 	auditlogService := auditlogsvc.NewAuditLogService(auditlogRepository)
-	subjectService := subjectservice.NewSubjectService(subjectRepository, majorRepository)
+	subjectService := subjectservice.NewSubjectService(subjectRepository, majorRepository, offeringRepository, curriculumRepository, studyPlanRepository, notificationService)
+	curriculumService := curriculumservice.NewCurriculumService(curriculumRepository, majorRepository, subjectRepository)
+	degreeAuditService := degreeauditservice.NewDegreeAuditService(userRepository, majorRepository, subjectRepository, curriculumRepository)
+	studyPlanService := studyplanservice.NewStudyPlanService(studyPlanRepository, userRepository, subjectRepository, curriculumRepository)
+	termService := termservice.NewTermService(termRepository, offeringRepository)
+	offeringService := offeringservice.NewOfferingService(offeringRepository, sectionRepository, termRepository, subjectRepository, majorRepository)
+	sectionService := sectionservice.NewSectionService(sectionRepository, offeringRepository)
+	timetableService := timetableservice.NewTimetableService(sectionRepository, offeringRepository, termRepository, subjectRepository, userRepository)
+	exportService := exportservice.NewExportService(facultyRepository, majorRepository, subjectRepository)
+	integrityService := integrityservice.NewIntegrityService(integrityRepository, facultyRepository, majorRepository, subjectRepository, userRepository)
+	jobService := jobservice.NewJobService(jobRepository, timetableservice.Location())
 
 	userHandler := userhandler.NewUserHandler(userService)
 	facultyHandler := facultyhandler.NewFacultyHandler(facultyService)
 	majorHandler := majorhandler.NewMajorHandler(majorService)
 	auditlogHandler := auditloghandler.NewAuditLogHandler(auditlogService)
 	subjectHandler := subjecthandler.NewSubjectHandler(subjectService)
+	curriculumHandler := curriculumhandler.NewCurriculumHandler(curriculumService)
+	degreeAuditHandler := degreeaudithandler.NewDegreeAuditHandler(degreeAuditService)
+	studyPlanHandler := studyplanhandler.NewStudyPlanHandler(studyPlanService)
+	termHandler := termhandler.NewTermHandler(termService)
+	offeringHandler := offeringhandler.NewOfferingHandler(offeringService)
+	sectionHandler := sectionhandler.NewSectionHandler(sectionService)
+	timetableHandler := timetablehandler.NewTimetableHandler(timetableService)
+	exportHandler := exporthandler.NewExportHandler(exportService)
+	integrityHandler := integrityhandler.NewIntegrityHandler(integrityService)
+	notificationHandler := notificationhandler.NewNotificationHandler(notificationService)
+	healthHandler := healthhandler.NewHealthHandler(db.DB)
+
+	jobHandler := jobhandler.NewJobHandler(jobService)
+
+	// Background jobs stop when the server shuts down; runs still in
+	// progress get a few seconds to hand their lease back.
+	registerJobs(jobService, subjectService, integrityService, notificationService)
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	jobsDone := make(chan struct{})
+	go func() {
+		jobService.Run(jobsCtx)
+		close(jobsDone)
+	}()
+	app.Hooks().OnShutdown(func() error {
+		stopJobs()
+		select {
+		case <-jobsDone:
+		case <-time.After(5 * time.Second):
+			logger.L().Warn("background jobs did not stop in time")
+		}
+		return nil
+	})
+
+	// Requests have finished by the time shutdown hooks run, so no more
+	// password reset or verification emails are started.
+	app.Hooks().OnShutdown(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := userService.WaitBackground(ctx); err != nil {
+			logger.L().Warn("password reset and verification emails did not finish in time")
+		}
+		return nil
+	})
+
+	// Metrics and Tracing must wrap AccessLog, which renders handler errors into the response.
+	app.Use(middleware.RequestID)
+	app.Use(middleware.Tracing)
+	app.Use(middleware.Metrics)
+	app.Use(middleware.AccessLog)
 
 	app.Get("/", func(c *fiber.Ctx) error {
 		return c.SendString("Welcome to the API")
 	})
 
+	//Probes
+	app.Get("/healthz", healthHandler.Healthz)
+	app.Get("/readyz", healthHandler.Readyz)
+	app.Get("/version", healthHandler.Version)
+	app.Get("/metrics", metrics.Handler())
+
 	//Auth
-	app.Post("/api/auth/login", userHandler.Login)
-	app.Post("/api/auth/googlelogin", userHandler.GoogleLogin)
-	app.Post("/api/auth/createoneuser", userHandler.CreateOneUser)
+	authGroup := app.Group("/api/auth", middleware.Timeout("auth"))
+	authGroup.Post("/login", userHandler.Login)
+	authGroup.Post("/googlelogin", userHandler.GoogleLogin)
+	authGroup.Post("/createoneuser", userHandler.CreateOneUser)
+	// These send email or take emailed tokens, so each client IP is limited.
+	authGroup.Post("/forgot-password", middleware.RateLimit("forgot_password", 5, 15*time.Minute), userHandler.ForgotPassword)
+	authGroup.Post("/reset-password", middleware.RateLimit("reset_password", 10, 15*time.Minute), userHandler.ResetPassword)
+	authGroup.Post("/verify-email", middleware.RateLimit("verify_email", 10, 15*time.Minute), userHandler.VerifyEmail)
+	authGroup.Post("/resend-verification", middleware.RateLimit("resend_verification", 5, 15*time.Minute), userHandler.ResendVerification)
 
-	protectedUserGroup := app.Group("/api/users", middleware.JWTMiddleware)
+	protectedUserGroup := app.Group("/api/users", middleware.Timeout("users"), middleware.JWTMiddleware(userRepository))
 	protectedUserGroup.Get("/getallusers", userHandler.GetUsers)
 	protectedUserGroup.Get("/getoneuser/:id", userHandler.GetOneUser)
 	protectedUserGroup.Get("/getuserbyemail/:email", userHandler.GetUserByEmail)
@@ -64,7 +209,7 @@ func Setup(app *fiber.App) {
 	protectedUserGroup.Put("/updateoneuser/:id", userHandler.UpdateOneUser)
 	protectedUserGroup.Delete("/dropallusers", userHandler.DropAllUsers)
 
-	protectedFacultyGroup := app.Group("/api/faculties", middleware.JWTMiddleware)
+	protectedFacultyGroup := app.Group("/api/faculties", middleware.Timeout("faculties"), middleware.JWTMiddleware(userRepository))
 	protectedFacultyGroup.Get("/getallfaculties", facultyHandler.GetFaculties)
 	protectedFacultyGroup.Get("/geteachfaculty/:id", facultyHandler.GetEachFaculty)
 	protectedFacultyGroup.Get("/getamjorforfaculty/:id", facultyHandler.GetMajorsForeachFaculty)
@@ -72,23 +217,137 @@ func Setup(app *fiber.App) {
 	protectedFacultyGroup.Put("/updatefaculty/:id", facultyHandler.UpdateFaculty)
 	protectedFacultyGroup.Delete("/deletefaculty/:id", facultyHandler.DeleteFaculty)
 
-	protectedMajorGroup := app.Group("/api/majors", middleware.JWTMiddleware)
+	protectedMajorGroup := app.Group("/api/majors", middleware.Timeout("majors"), middleware.JWTMiddleware(userRepository))
 	protectedMajorGroup.Get("/getallmajors", majorHandler.GetMajors)
 	protectedMajorGroup.Get("/geteachmajor/:id", majorHandler.Geteachmajor)
 	protectedMajorGroup.Get("getsubjectsforeachmajor/:id", majorHandler.GetSubjectsForeachMajor)
 	protectedMajorGroup.Post("/createmajor", majorHandler.CreateMajor)
 	protectedMajorGroup.Delete("/deletemajor/:id", majorHandler.DeleteMajor)
 	protectedMajorGroup.Put("/updatemajor/:id", majorHandler.UpdateMajor)
+	protectedMajorGroup.Get("/:id/curriculum", curriculumHandler.GetApplicableCurriculum)
+	protectedMajorGroup.Get("/:id/curricula", curriculumHandler.GetCurricula)
+	protectedMajorGroup.Get("/:id/curricula/:intakeYear", curriculumHandler.GetCurriculum)
+	protectedMajorGroup.Put("/:id/curricula/:intakeYear", middleware.RequireRole(userRepository, "admin"), curriculumHandler.SaveCurriculum)
+	protectedMajorGroup.Get("/:id/offerings", offeringHandler.GetOfferingsByMajor)
 
-	protectedAuditlogGroup := app.Group("/api/auditlogs", middleware.JWTMiddleware)
+	protectedAuditlogGroup := app.Group("/api/auditlogs", middleware.Timeout("auditlogs"), middleware.JWTMiddleware(userRepository))
 	protectedAuditlogGroup.Get("/getallauditlogs", auditlogHandler.GetAuditLogs)
 
-	protectedSubjectGroup := app.Group("/api/subjects", middleware.JWTMiddleware)
+	protectedSubjectGroup := app.Group("/api/subjects", middleware.Timeout("subjects"), middleware.JWTMiddleware(userRepository))
 	protectedSubjectGroup.Get("/getallsubjects", subjectHandler.GetSubjects)
 	protectedSubjectGroup.Get("/geteachsubject/:id", subjectHandler.GetEachSubject)
 	protectedSubjectGroup.Post("/createsubject", subjectHandler.CreateSubject)
 	protectedSubjectGroup.Delete("/deletesubject/:id", subjectHandler.DeleteSubject)
 	protectedSubjectGroup.Put("/updatesubject/:id", subjectHandler.UpdateSubject)
 	protectedSubjectGroup.Put("/updatelikes/:id", subjectHandler.AddLikeByEmailHandler)
+	protectedSubjectGroup.Post("/import", middleware.RequireRole(userRepository, "admin"), subjectHandler.ImportSubjects)
+	protectedSubjectGroup.Get("/:id/offerings", offeringHandler.GetOfferingsBySubject)
+
+	protectedTermGroup := app.Group("/api/terms", middleware.Timeout("terms"), middleware.JWTMiddleware(userRepository))
+	protectedTermGroup.Get("/", termHandler.GetTerms)
+	protectedTermGroup.Get("/:id", termHandler.GetTerm)
+	protectedTermGroup.Get("/:id/offerings", offeringHandler.GetOfferingsByTerm)
+	protectedTermGroup.Post("/", middleware.RequireRole(userRepository, "admin"), termHandler.CreateTerm)
+	protectedTermGroup.Put("/:id", middleware.RequireRole(userRepository, "admin"), termHandler.UpdateTerm)
+	protectedTermGroup.Delete("/:id", middleware.RequireRole(userRepository, "admin"), termHandler.DeleteTerm)
+
+	protectedOfferingGroup := app.Group("/api/offerings", middleware.Timeout("offerings"), middleware.JWTMiddleware(userRepository))
+	protectedOfferingGroup.Get("/:id", offeringHandler.GetOffering)
+	protectedOfferingGroup.Post("/", middleware.RequireRole(userRepository, "admin"), offeringHandler.CreateOffering)
+	protectedOfferingGroup.Put("/:id", middleware.RequireRole(userRepository, "admin"), offeringHandler.UpdateOffering)
+	protectedOfferingGroup.Delete("/:id", middleware.RequireRole(userRepository, "admin"), offeringHandler.DeleteOffering)
+	protectedOfferingGroup.Get("/:id/sections", sectionHandler.GetSectionsByOffering)
+	protectedOfferingGroup.Post("/:id/sections", middleware.RequireRole(userRepository, "admin"), sectionHandler.CreateSection)
 
+	protectedSectionGroup := app.Group("/api/sections", middleware.Timeout("sections"), middleware.JWTMiddleware(userRepository))
+	protectedSectionGroup.Get("/:id", sectionHandler.GetSection)
+	protectedSectionGroup.Put("/:id", middleware.RequireRole(userRepository, "admin"), sectionHandler.UpdateSection)
+	protectedSectionGroup.Delete("/:id", middleware.RequireRole(userRepository, "admin"), sectionHandler.DeleteSection)
+
+	protectedTimetableGroup := app.Group("/api/timetable", middleware.Timeout("timetable"), middleware.JWTMiddleware(userRepository))
+	protectedTimetableGroup.Post("/check", timetableHandler.Check)
+	protectedTimetableGroup.Post("/generate", timetableHandler.Generate)
+
+	calendarGroup := app.Group("/api/calendar", middleware.Timeout("calendar"))
+	calendarGroup.Get("/:token.ics", timetableHandler.SubscribedCalendar)
+
+	protectedMeGroup := app.Group("/api/me", middleware.Timeout("me"), middleware.JWTMiddleware(userRepository))
+	protectedMeGroup.Get("/record", degreeAuditHandler.GetRecord)
+	protectedMeGroup.Put("/program", degreeAuditHandler.SetProgram)
+	protectedMeGroup.Put("/completed-subjects", degreeAuditHandler.SetCompletedSubjects)
+	protectedMeGroup.Get("/audit", degreeAuditHandler.Audit)
+	protectedMeGroup.Post("/plans/generate", studyPlanHandler.Generate)
+	protectedMeGroup.Get("/plans", studyPlanHandler.GetPlans)
+	protectedMeGroup.Post("/plans", studyPlanHandler.SavePlan)
+	protectedMeGroup.Get("/plans/:id", studyPlanHandler.GetPlan)
+	protectedMeGroup.Put("/plans/:id", studyPlanHandler.UpdatePlan)
+	protectedMeGroup.Post("/plans/:id/validate", studyPlanHandler.RevalidatePlan)
+	protectedMeGroup.Delete("/plans/:id", studyPlanHandler.DeletePlan)
+	protectedMeGroup.Get("/timetable", timetableHandler.GetMyTimetable)
+	protectedMeGroup.Put("/timetable", timetableHandler.SaveMyTimetable)
+	protectedMeGroup.Get("/timetable.ics", timetableHandler.Calendar)
+	protectedMeGroup.Post("/timetable/subscription", timetableHandler.CreateSubscription)
+	protectedMeGroup.Delete("/timetable/subscription", timetableHandler.RevokeSubscription)
+	protectedMeGroup.Get("/notifications", notificationHandler.GetPreferences)
+	protectedMeGroup.Put("/notifications", notificationHandler.UpdatePreferences)
+
+	protectedExportGroup := app.Group("/api/export", middleware.Timeout("export"), middleware.JWTMiddleware(userRepository))
+	protectedExportGroup.Get("/", exportHandler.Export)
+
+	adminGroup := app.Group("/api/admin", middleware.Timeout("admin"), middleware.JWTMiddleware(userRepository), middleware.RequireRole(userRepository, "admin"))
+	adminGroup.Get("/integrity/report", integrityHandler.GetLatestReport)
+	adminGroup.Post("/integrity/check", integrityHandler.RunCheck)
+	adminGroup.Get("/jobs", jobHandler.GetJobs)
+	adminGroup.Post("/jobs/:name/trigger", jobHandler.TriggerJob)
+	adminGroup.Get("/jobs/runs", jobHandler.GetRuns)
+	adminGroup.Get("/jobs/runs/:id", jobHandler.GetRun)
+	adminGroup.Post("/jobs/runs/:id/retry", jobHandler.RetryRun)
+	adminGroup.Delete("/jobs/runs/:id", jobHandler.DiscardRun)
+	adminGroup.Get("/jobs/dead-letters", jobHandler.GetDeadLetters)
+	adminGroup.Post("/users/:id/suspend", userHandler.SuspendUser)
+	adminGroup.Post("/users/:id/reactivate", userHandler.ReactivateUser)
+
+}
+
+// registerJobs declares the background jobs the API runs.
+func registerJobs(jobService jobservice.IJobService, subjectService subjectservice.ISubjectService, integrityService integrityservice.IIntegrityService, notificationService notificationservice.INotificationService) {
+	jobService.Register(jobservice.Job{
+		Name:        "subjects.expire",
+		Description: "Archive subjects whose availability duration has elapsed",
+		Schedule:    "@hourly",
+		Run: func(ctx context.Context, _ bson.M) error {
+			_, err := subjectService.ArchiveExpiredSubjects(ctx)
+			return err
+		},
+	})
+	jobService.Register(jobservice.Job{
+		Name:        "notifications.deliver",
+		Description: "Send the emails waiting in the notification outbox",
+		Schedule:    "* * * * *",
+		MaxAttempts: 1,
+		Run: func(ctx context.Context, _ bson.M) error {
+			_, err := notificationService.DeliverPending(ctx)
+			return err
+		},
+	})
+	jobService.Register(jobservice.Job{
+		Name:        "integrity.check",
+		Description: "Scan for dangling references and orphans and store a report; payload {\"fix\": true} repairs them",
+		Schedule:    "0 3 * * *",
+		Timeout:     30 * time.Minute,
+		Run: func(ctx context.Context, payload bson.M) error {
+			fix, _ := payload["fix"].(bool)
+			_, err := integrityService.Check(ctx, fix)
+			return err
+		},
+	})
+	jobService.Register(jobservice.Job{
+		Name:        "indexes.ensure",
+		Description: "Create any missing index declared in db/indexes.go",
+		MaxAttempts: 1,
+		Timeout:     time.Hour,
+		Run: func(ctx context.Context, _ bson.M) error {
+			return db.EnsureIndexes(ctx)
+		},
+	})
 }
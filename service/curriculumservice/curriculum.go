@@ -0,0 +1,185 @@
+package curriculumservice
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/logger"
+	"BackendCoursyclopedia/model/curriculummodel"
+	"BackendCoursyclopedia/model/subjectmodel"
+	"BackendCoursyclopedia/repository/curriculumrepository"
+	"BackendCoursyclopedia/repository/majorrepository"
+	"BackendCoursyclopedia/repository/subjectrepository"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	minIntakeYear = 1950
+	maxIntakeYear = 2200
+)
+
+type ICurriculumService interface {
+	GetCurricula(ctx context.Context, majorID string) ([]curriculummodel.Curriculum, error)
+	GetCurriculum(ctx context.Context, majorID string, intakeYear, version int) (*curriculummodel.Curriculum, error)
+	GetApplicableCurriculum(ctx context.Context, majorID string, intakeYear int) (*curriculummodel.Curriculum, error)
+	SaveCurriculum(ctx context.Context, majorID string, intakeYear int, request curriculummodel.CurriculumRequest, userID string) (*curriculummodel.Curriculum, error)
+}
+
+type CurriculumService struct {
+	CurriculumRepository curriculumrepository.ICurriculumRepository
+	MajorRepository      majorrepository.IMajorRepository
+	SubjectRepository    subjectrepository.ISubjectRepository
+}
+
+func NewCurriculumService(curriculumRepo curriculumrepository.ICurriculumRepository, majorRepo majorrepository.IMajorRepository, subjectRepo subjectrepository.ISubjectRepository) ICurriculumService {
+	return &CurriculumService{
+		CurriculumRepository: curriculumRepo,
+		MajorRepository:      majorRepo,
+		SubjectRepository:    subjectRepo,
+	}
+}
+
+func (s *CurriculumService) GetCurricula(ctx context.Context, majorID string) ([]curriculummodel.Curriculum, error) {
+	id, err := primitive.ObjectIDFromHex(majorID)
+	if err != nil {
+		return nil, apperror.InvalidID("major", err)
+	}
+	return s.CurriculumRepository.FindCurricula(ctx, id)
+}
+
+func (s *CurriculumService) GetCurriculum(ctx context.Context, majorID string, intakeYear, version int) (*curriculummodel.Curriculum, error) {
+	id, err := primitive.ObjectIDFromHex(majorID)
+	if err != nil {
+		return nil, apperror.InvalidID("major", err)
+	}
+	if err := checkIntakeYear(intakeYear); err != nil {
+		return nil, err
+	}
+	return s.CurriculumRepository.FindCurriculum(ctx, id, intakeYear, version)
+}
+
+func (s *CurriculumService) GetApplicableCurriculum(ctx context.Context, majorID string, intakeYear int) (*curriculummodel.Curriculum, error) {
+	id, err := primitive.ObjectIDFromHex(majorID)
+	if err != nil {
+		return nil, apperror.InvalidID("major", err)
+	}
+	if err := checkIntakeYear(intakeYear); err != nil {
+		return nil, err
+	}
+	return s.CurriculumRepository.FindApplicableCurriculum(ctx, id, intakeYear)
+}
+
+// SaveCurriculum stores request as the next version of the major's
+// curriculum for intakeYear, after checking that every subject exists, no
+// subject is listed twice and every elective rule can be met.
+func (s *CurriculumService) SaveCurriculum(ctx context.Context, majorID string, intakeYear int, request curriculummodel.CurriculumRequest, userID string) (*curriculummodel.Curriculum, error) {
+	if err := checkIntakeYear(intakeYear); err != nil {
+		return nil, err
+	}
+	major, err := s.MajorRepository.FindmajorbyID(ctx, majorID)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := request.ToGroups()
+	if err != nil {
+		return nil, apperror.InvalidArgument("invalid subject ID")
+	}
+	curriculum := curriculummodel.Curriculum{
+		MajorID:      major.ID,
+		IntakeYear:   intakeYear,
+		TotalCredits: request.TotalCredits,
+		Groups:       groups,
+		Note:         request.Note,
+		CreatedAt:    time.Now().UTC(),
+	}
+	if createdBy, err := primitive.ObjectIDFromHex(userID); err == nil {
+		curriculum.CreatedBy = createdBy
+	}
+
+	subjects, err := s.SubjectRepository.FindSubjectsByIDs(ctx, curriculum.SubjectIDs())
+	if err != nil {
+		return nil, err
+	}
+	if fields := checkGroups(curriculum.Groups, subjects); len(fields) > 0 {
+		return nil, apperror.Validation(fields)
+	}
+
+	latest, err := s.CurriculumRepository.FindCurriculum(ctx, major.ID, intakeYear, 0)
+	switch {
+	case apperror.Is(err, apperror.KindNotFound):
+		curriculum.Version = 1
+	case err != nil:
+		return nil, err
+	default:
+		curriculum.Version = latest.Version + 1
+	}
+
+	id, err := s.CurriculumRepository.CreateCurriculum(ctx, curriculum)
+	if apperror.Is(err, apperror.KindConflict) {
+		return nil, apperror.Conflict("version %d was saved concurrently; reload and retry", curriculum.Version)
+	}
+	if err != nil {
+		return nil, err
+	}
+	curriculum.ID = id
+
+	logger.FromContext(ctx).Info("curriculum saved",
+		"major_id", majorID, "intake_year", intakeYear, "version", curriculum.Version)
+	return &curriculum, nil
+}
+
+func checkGroups(groups []curriculummodel.RequirementGroup, subjects []subjectmodel.Subject) []apperror.FieldError {
+	credits := map[primitive.ObjectID]int{}
+	for _, sub := range subjects {
+		credits[sub.ID] = sub.Credit
+	}
+
+	var fields []apperror.FieldError
+	listedIn := map[primitive.ObjectID]string{}
+	for i, g := range groups {
+		groupField := fmt.Sprintf("groups[%d]", i)
+		total := 0
+		for j, cs := range g.Subjects {
+			field := fmt.Sprintf("%s.subjects[%d].subjectId", groupField, j)
+			credit, ok := credits[cs.SubjectID]
+			if !ok {
+				fields = append(fields, apperror.FieldError{Field: field, Rule: "exists", Message: "subject does not exist"})
+				continue
+			}
+			if other, dup := listedIn[cs.SubjectID]; dup {
+				fields = append(fields, apperror.FieldError{Field: field, Rule: "unique", Message: "subject is already listed in " + other})
+				continue
+			}
+			listedIn[cs.SubjectID] = fmt.Sprintf("group %q", g.Name)
+			total += credit
+		}
+
+		switch g.Kind {
+		case curriculummodel.GroupCore:
+			if g.MinCredits != 0 || g.MinCount != 0 {
+				fields = append(fields, apperror.FieldError{Field: groupField, Rule: "core", Message: "core groups require every subject and take no minCredits or minCount"})
+			}
+		case curriculummodel.GroupElective:
+			if g.MinCredits == 0 && g.MinCount == 0 {
+				fields = append(fields, apperror.FieldError{Field: groupField, Rule: "elective", Message: "elective groups need minCredits or minCount"})
+			}
+			if g.MinCount > len(g.Subjects) {
+				fields = append(fields, apperror.FieldError{Field: groupField + ".minCount", Rule: "max", Message: fmt.Sprintf("must be at most %d, the number of subjects in the group", len(g.Subjects))})
+			}
+			if g.MinCredits > total {
+				fields = append(fields, apperror.FieldError{Field: groupField + ".minCredits", Rule: "max", Message: fmt.Sprintf("must be at most %d, the credits of the subjects in the group", total)})
+			}
+		}
+	}
+	return fields
+}
+
+func checkIntakeYear(year int) error {
+	if year < minIntakeYear || year > maxIntakeYear {
+		return apperror.InvalidArgument("intake year must be between %d and %d", minIntakeYear, maxIntakeYear)
+	}
+	return nil
+}
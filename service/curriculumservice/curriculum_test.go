@@ -0,0 +1,103 @@
+package curriculumservice
+
+import (
+	"BackendCoursyclopedia/model/curriculummodel"
+	"BackendCoursyclopedia/model/subjectmodel"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCheckGroups(t *testing.T) {
+	a, b, c, missing := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	subjects := []subjectmodel.Subject{{ID: a, Credit: 3}, {ID: b, Credit: 3}, {ID: c, Credit: 1}}
+	listed := func(ids ...primitive.ObjectID) []curriculummodel.CurriculumSubject {
+		out := make([]curriculummodel.CurriculumSubject, len(ids))
+		for i, id := range ids {
+			out[i] = curriculummodel.CurriculumSubject{SubjectID: id, Year: 1, Semester: 1}
+		}
+		return out
+	}
+
+	tests := []struct {
+		name   string
+		groups []curriculummodel.RequirementGroup
+		want   []string
+	}{
+		{
+			name: "valid core and elective",
+			groups: []curriculummodel.RequirementGroup{
+				{Name: "Core", Kind: curriculummodel.GroupCore, Subjects: listed(a)},
+				{Name: "Electives", Kind: curriculummodel.GroupElective, MinCredits: 4, MinCount: 2, Subjects: listed(b, c)},
+			},
+		},
+		{
+			name: "missing subject",
+			groups: []curriculummodel.RequirementGroup{
+				{Name: "Core", Kind: curriculummodel.GroupCore, Subjects: listed(a, missing)},
+			},
+			want: []string{"groups[0].subjects[1].subjectId"},
+		},
+		{
+			name: "subject listed twice across groups",
+			groups: []curriculummodel.RequirementGroup{
+				{Name: "Core", Kind: curriculummodel.GroupCore, Subjects: listed(a)},
+				{Name: "Electives", Kind: curriculummodel.GroupElective, MinCount: 1, Subjects: listed(b, a)},
+			},
+			want: []string{"groups[1].subjects[1].subjectId"},
+		},
+		{
+			name: "core with a minimum",
+			groups: []curriculummodel.RequirementGroup{
+				{Name: "Core", Kind: curriculummodel.GroupCore, MinCount: 1, Subjects: listed(a)},
+			},
+			want: []string{"groups[0]"},
+		},
+		{
+			name: "elective without a rule",
+			groups: []curriculummodel.RequirementGroup{
+				{Name: "Electives", Kind: curriculummodel.GroupElective, Subjects: listed(a)},
+			},
+			want: []string{"groups[0]"},
+		},
+		{
+			name: "elective rules that cannot be met",
+			groups: []curriculummodel.RequirementGroup{
+				{Name: "Electives", Kind: curriculummodel.GroupElective, MinCredits: 5, MinCount: 3, Subjects: listed(a, c)},
+			},
+			want: []string{"groups[0].minCount", "groups[0].minCredits"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, fe := range checkGroups(tt.groups, subjects) {
+				got = append(got, fe.Field)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkGroups() fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckIntakeYear(t *testing.T) {
+	tests := []struct {
+		year    int
+		wantErr bool
+	}{
+		{year: minIntakeYear - 1, wantErr: true},
+		{year: minIntakeYear},
+		{year: 2024},
+		{year: maxIntakeYear},
+		{year: maxIntakeYear + 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		if err := checkIntakeYear(tt.year); (err != nil) != tt.wantErr {
+			t.Errorf("checkIntakeYear(%d) error = %v, wantErr %v", tt.year, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package degreeauditservice
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/model/curriculummodel"
+	"BackendCoursyclopedia/model/degreeauditmodel"
+	"BackendCoursyclopedia/model/subjectmodel"
+	"BackendCoursyclopedia/model/usermodel"
+	"BackendCoursyclopedia/repository/curriculumrepository"
+	"BackendCoursyclopedia/repository/majorrepository"
+	"BackendCoursyclopedia/repository/subjectrepository"
+	userrepo "BackendCoursyclopedia/repository/userrepository"
+	"BackendCoursyclopedia/requisites"
+	"context"
+	"fmt"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IDegreeAuditService interface {
+	GetRecord(ctx context.Context, userID string) (*degreeauditmodel.Record, error)
+	SetProgram(ctx context.Context, userID string, request usermodel.ProgramRequest) (*degreeauditmodel.Record, error)
+	SetCompletedSubjects(ctx context.Context, userID string, request usermodel.CompletedSubjectsRequest) (*degreeauditmodel.Record, error)
+	Audit(ctx context.Context, userID string, majorID string, intakeYear int) (*degreeauditmodel.Report, error)
+}
+
+type DegreeAuditService struct {
+	UserRepository       userrepo.IUserRepository
+	MajorRepository      majorrepository.IMajorRepository
+	SubjectRepository    subjectrepository.ISubjectRepository
+	CurriculumRepository curriculumrepository.ICurriculumRepository
+}
+
+func NewDegreeAuditService(userRepo userrepo.IUserRepository, majorRepo majorrepository.IMajorRepository, subjectRepo subjectrepository.ISubjectRepository, curriculumRepo curriculumrepository.ICurriculumRepository) IDegreeAuditService {
+	return &DegreeAuditService{
+		UserRepository:       userRepo,
+		MajorRepository:      majorRepo,
+		SubjectRepository:    subjectRepo,
+		CurriculumRepository: curriculumRepo,
+	}
+}
+
+func (s *DegreeAuditService) GetRecord(ctx context.Context, userID string) (*degreeauditmodel.Record, error) {
+	user, err := s.UserRepository.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return recordOf(user), nil
+}
+
+func (s *DegreeAuditService) SetProgram(ctx context.Context, userID string, request usermodel.ProgramRequest) (*degreeauditmodel.Record, error) {
+	user, err := s.UserRepository.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	major, err := s.MajorRepository.FindmajorbyID(ctx, request.MajorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.UserRepository.UpdateProgram(ctx, user.ID, major.ID, request.IntakeYear); err != nil {
+		return nil, err
+	}
+	user.MajorID, user.IntakeYear = major.ID, request.IntakeYear
+	return recordOf(user), nil
+}
+
+// SetCompletedSubjects replaces the user's completed subjects after checking
+// that every subject exists.
+func (s *DegreeAuditService) SetCompletedSubjects(ctx context.Context, userID string, request usermodel.CompletedSubjectsRequest) (*degreeauditmodel.Record, error) {
+	user, err := s.UserRepository.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	completed, err := request.ToCompletedSubjects()
+	if err != nil {
+		return nil, apperror.InvalidArgument("invalid subject ID")
+	}
+
+	ids := make([]primitive.ObjectID, len(completed))
+	for i, c := range completed {
+		ids[i] = c.SubjectID
+	}
+	subjects, err := s.SubjectRepository.FindSubjectsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	exists := map[primitive.ObjectID]bool{}
+	for _, sub := range subjects {
+		exists[sub.ID] = true
+	}
+	var fields []apperror.FieldError
+	for i, c := range completed {
+		if !exists[c.SubjectID] {
+			fields = append(fields, apperror.FieldError{
+				Field: fmt.Sprintf("subjects[%d].subjectId", i), Rule: "exists", Message: "subject does not exist",
+			})
+		}
+	}
+	if len(fields) > 0 {
+		return nil, apperror.Validation(fields)
+	}
+
+	if err := s.UserRepository.UpdateCompletedSubjects(ctx, user.ID, completed); err != nil {
+		return nil, err
+	}
+	user.CompletedSubjects = completed
+	return recordOf(user), nil
+}
+
+// Audit evaluates the user's completed subjects against the curriculum that
+// applies to their major and intake year. majorID and intakeYear, when set,
+// override the user's own so students can see how they would fare in
+// another programme.
+func (s *DegreeAuditService) Audit(ctx context.Context, userID string, majorID string, intakeYear int) (*degreeauditmodel.Report, error) {
+	user, err := s.UserRepository.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	major := user.MajorID
+	if majorID != "" {
+		if major, err = primitive.ObjectIDFromHex(majorID); err != nil {
+			return nil, apperror.InvalidID("major", err)
+		}
+	}
+	if intakeYear == 0 {
+		intakeYear = user.IntakeYear
+	}
+	if major.IsZero() || intakeYear == 0 {
+		return nil, apperror.InvalidArgument("set a major and intake year before running an audit")
+	}
+
+	curriculum, err := s.CurriculumRepository.FindApplicableCurriculum(ctx, major, intakeYear)
+	if err != nil {
+		return nil, err
+	}
+	subjects, err := s.SubjectRepository.FindAllSubjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := Evaluate(*curriculum, requisites.NewCatalog(subjects), user.CompletedSubjects)
+	report.UserID = user.ID
+	report.IntakeYear = intakeYear
+	return report, nil
+}
+
+// Evaluate audits completed against curriculum. Subjects are passed when any
+// attempt has a passing grade, and each passed subject's credits count once
+// towards the total whether or not the curriculum lists it.
+func Evaluate(curriculum curriculummodel.Curriculum, catalog *requisites.Catalog, completed []usermodel.CompletedSubject) *degreeauditmodel.Report {
+	report := &degreeauditmodel.Report{
+		MajorID:           curriculum.MajorID,
+		IntakeYear:        curriculum.IntakeYear,
+		CurriculumID:      curriculum.ID,
+		CurriculumVersion: curriculum.Version,
+		RequiredCredits:   curriculum.TotalCredits,
+		Requirements:      []degreeauditmodel.RequirementResult{},
+		Eligible:          []degreeauditmodel.EligibleSubject{},
+		Blocked:           []degreeauditmodel.BlockedSubject{},
+	}
+
+	passed := PassedCodes(catalog, completed)
+	for code := range passed {
+		sub, _ := catalog.ByCode(code)
+		report.EarnedCredits += sub.Credit
+	}
+	seenUnknown := map[primitive.ObjectID]bool{}
+	for _, c := range completed {
+		if _, ok := catalog.ByID(c.SubjectID); !ok && !seenUnknown[c.SubjectID] {
+			seenUnknown[c.SubjectID] = true
+			report.Unknown = append(report.Unknown, c.SubjectID)
+		}
+	}
+
+	report.Satisfied = true
+	shortfall := 0
+	var candidates []degreeauditmodel.SubjectRef
+	for _, g := range curriculum.Groups {
+		result := evaluateGroup(g, catalog, passed)
+		report.Requirements = append(report.Requirements, result)
+		if result.Satisfied {
+			continue
+		}
+		report.Satisfied = false
+		candidates = append(candidates, result.Remaining...)
+		switch g.Kind {
+		case curriculummodel.GroupCore:
+			shortfall += result.RequiredCredits - result.EarnedCredits
+		case curriculummodel.GroupElective:
+			shortfall += max(0, g.MinCredits-result.EarnedCredits)
+		}
+	}
+
+	// Groups may need more than the total, for example when completed
+	// subjects outside the curriculum already cover the total.
+	report.RemainingCredits = max(0, curriculum.TotalCredits-report.EarnedCredits, shortfall)
+	if report.RemainingCredits > 0 {
+		report.Satisfied = false
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Year != b.Year {
+			return a.Year < b.Year
+		}
+		if a.Semester != b.Semester {
+			return a.Semester < b.Semester
+		}
+		return a.SubjectCode < b.SubjectCode
+	})
+	for _, ref := range candidates {
+		sub, _ := catalog.ByCode(ref.SubjectCode)
+		if !IsOffered(sub) {
+			continue
+		}
+		if missing := catalog.MissingChain(ref.SubjectCode, passed); len(missing) > 0 {
+			report.Blocked = append(report.Blocked, degreeauditmodel.BlockedSubject{SubjectRef: ref, MissingPrerequisites: missing})
+			continue
+		}
+		eligible := degreeauditmodel.EligibleSubject{SubjectRef: ref}
+		for _, co := range catalog.CoRequisites(sub) {
+			if !passed[co] {
+				eligible.TakeWith = append(eligible.TakeWith, co)
+			}
+		}
+		report.Eligible = append(report.Eligible, eligible)
+	}
+
+	return report
+}
+
+func evaluateGroup(g curriculummodel.RequirementGroup, catalog *requisites.Catalog, passed map[string]bool) degreeauditmodel.RequirementResult {
+	result := degreeauditmodel.RequirementResult{
+		Group:     g.Name,
+		Kind:      g.Kind,
+		Completed: []degreeauditmodel.SubjectRef{},
+		Remaining: []degreeauditmodel.SubjectRef{},
+	}
+
+	total := 0
+	for _, cs := range g.Subjects {
+		sub, ok := catalog.ByID(cs.SubjectID)
+		if !ok {
+			continue
+		}
+		ref := degreeauditmodel.SubjectRef{
+			SubjectID: sub.ID, SubjectCode: sub.SubjectCode, Name: sub.Name, Credit: sub.Credit,
+			Year: cs.Year, Semester: cs.Semester,
+		}
+		total += sub.Credit
+		if passed[sub.SubjectCode] {
+			result.Completed = append(result.Completed, ref)
+			result.EarnedCredits += sub.Credit
+			result.CompletedCount++
+		} else {
+			result.Remaining = append(result.Remaining, ref)
+		}
+	}
+
+	switch g.Kind {
+	case curriculummodel.GroupCore:
+		result.RequiredCredits = total
+		result.RequiredCount = len(result.Completed) + len(result.Remaining)
+		result.Satisfied = len(result.Remaining) == 0
+	case curriculummodel.GroupElective:
+		result.RequiredCredits = g.MinCredits
+		result.RequiredCount = g.MinCount
+		result.Satisfied = result.EarnedCredits >= g.MinCredits && result.CompletedCount >= g.MinCount
+	}
+	return result
+}
+
+// PassedCodes returns the codes of subjects with at least one passing
+// attempt.
+func PassedCodes(catalog *requisites.Catalog, completed []usermodel.CompletedSubject) map[string]bool {
+	passed := map[string]bool{}
+	for _, c := range completed {
+		if !usermodel.IsPassingGrade(c.Grade) {
+			continue
+		}
+		if sub, ok := catalog.ByID(c.SubjectID); ok {
+			passed[sub.SubjectCode] = true
+		}
+	}
+	return passed
+}
+
+// IsOffered reports whether students can currently enrol in sub. Subjects
+// saved before statuses were introduced have none and count as offered.
+func IsOffered(sub subjectmodel.Subject) bool {
+	return sub.IsAvailable()
+}
+
+func recordOf(user *usermodel.User) *degreeauditmodel.Record {
+	completed := user.CompletedSubjects
+	if completed == nil {
+		completed = []usermodel.CompletedSubject{}
+	}
+	return &degreeauditmodel.Record{
+		MajorID:           user.MajorID,
+		IntakeYear:        user.IntakeYear,
+		CompletedSubjects: completed,
+	}
+}
@@ -0,0 +1,225 @@
+package degreeauditservice
+
+import (
+	"BackendCoursyclopedia/model/curriculummodel"
+	"BackendCoursyclopedia/model/degreeauditmodel"
+	"BackendCoursyclopedia/model/subjectmodel"
+	"BackendCoursyclopedia/model/usermodel"
+	"BackendCoursyclopedia/requisites"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestEvaluate(t *testing.T) {
+	var (
+		cs1, cs2, cs3 = primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+		el1, el2, el3 = primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+		outside       = primitive.NewObjectID()
+		deleted       = primitive.NewObjectID()
+	)
+	catalog := requisites.NewCatalog([]subjectmodel.Subject{
+		{ID: cs1, SubjectCode: "CS1", Credit: 3},
+		{ID: cs2, SubjectCode: "CS2", Credit: 3, PreRequisite: []string{cs1.Hex()}},
+		{ID: cs3, SubjectCode: "CS3", Credit: 3, PreRequisite: []string{"CS2"}},
+		{ID: el1, SubjectCode: "EL1", Credit: 3, SubjectStatus: subjectmodel.StatusAvailable},
+		{ID: el2, SubjectCode: "EL2", Credit: 2, CoRequisite: []string{"EL1"}},
+		{ID: el3, SubjectCode: "EL3", Credit: 3, SubjectStatus: subjectmodel.StatusSuspended},
+		{ID: outside, SubjectCode: "GE1", Credit: 3},
+	})
+	curriculum := curriculummodel.Curriculum{
+		TotalCredits: 15,
+		Groups: []curriculummodel.RequirementGroup{
+			{Name: "Core", Kind: curriculummodel.GroupCore, Subjects: []curriculummodel.CurriculumSubject{
+				{SubjectID: cs1, Year: 1, Semester: 1},
+				{SubjectID: cs2, Year: 1, Semester: 2},
+				{SubjectID: cs3, Year: 2, Semester: 1},
+			}},
+			{Name: "Electives", Kind: curriculummodel.GroupElective, MinCredits: 3, MinCount: 1, Subjects: []curriculummodel.CurriculumSubject{
+				{SubjectID: el1, Year: 2, Semester: 2},
+				{SubjectID: el2, Year: 2, Semester: 2},
+				{SubjectID: el3, Year: 2, Semester: 2},
+			}},
+		},
+	}
+	passed := func(ids ...primitive.ObjectID) []usermodel.CompletedSubject {
+		out := make([]usermodel.CompletedSubject, len(ids))
+		for i, id := range ids {
+			out[i] = usermodel.CompletedSubject{SubjectID: id, Term: "2024/1", Grade: "B"}
+		}
+		return out
+	}
+
+	tests := []struct {
+		name          string
+		completed     []usermodel.CompletedSubject
+		wantSatisfied bool
+		wantEarned    int
+		wantRemaining int
+		wantEligible  []string
+		wantBlocked   map[string][]string
+		wantUnknown   int
+	}{
+		{
+			name:          "nothing completed",
+			wantRemaining: 15,
+			wantEligible:  []string{"CS1", "EL1", "EL2"},
+			wantBlocked:   map[string][]string{"CS2": {"CS1"}, "CS3": {"CS1", "CS2"}},
+		},
+		{
+			name: "failed attempt then passed counts once",
+			completed: []usermodel.CompletedSubject{
+				{SubjectID: cs1, Term: "2024/1", Grade: "F"},
+				{SubjectID: cs1, Term: "2024/2", Grade: "C"},
+			},
+			wantEarned:    3,
+			wantRemaining: 12,
+			wantEligible:  []string{"CS2", "EL1", "EL2"},
+			wantBlocked:   map[string][]string{"CS3": {"CS2"}},
+		},
+		{
+			name:          "co-requisite to take in the same term",
+			completed:     passed(cs1, cs2, cs3),
+			wantEarned:    9,
+			wantRemaining: 6,
+			wantEligible:  []string{"EL1", "EL2"},
+		},
+		{
+			name:          "groups met but total short",
+			completed:     passed(cs1, cs2, cs3, el1),
+			wantEarned:    12,
+			wantRemaining: 3,
+		},
+		{
+			name:          "credits outside the curriculum complete the total",
+			completed:     passed(cs1, cs2, cs3, el1, outside),
+			wantSatisfied: true,
+			wantEarned:    15,
+		},
+		{
+			name:          "deleted subjects are reported and earn nothing",
+			completed:     append(passed(cs1, cs2, cs3, el1, outside), passed(deleted, deleted)...),
+			wantSatisfied: true,
+			wantEarned:    15,
+			wantUnknown:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Evaluate(curriculum, catalog, tt.completed)
+
+			if report.Satisfied != tt.wantSatisfied || report.EarnedCredits != tt.wantEarned || report.RemainingCredits != tt.wantRemaining {
+				t.Errorf("Evaluate() satisfied=%v earned=%d remaining=%d, want %v %d %d",
+					report.Satisfied, report.EarnedCredits, report.RemainingCredits, tt.wantSatisfied, tt.wantEarned, tt.wantRemaining)
+			}
+			if got := eligibleCodes(report.Eligible); !reflect.DeepEqual(got, tt.wantEligible) {
+				t.Errorf("Evaluate() eligible = %v, want %v", got, tt.wantEligible)
+			}
+			if got := blockedCodes(report.Blocked); !reflect.DeepEqual(got, tt.wantBlocked) {
+				t.Errorf("Evaluate() blocked = %v, want %v", got, tt.wantBlocked)
+			}
+			if len(report.Unknown) != tt.wantUnknown {
+				t.Errorf("Evaluate() unknown = %v, want %d IDs", report.Unknown, tt.wantUnknown)
+			}
+		})
+	}
+}
+
+func TestEvaluateTakeWith(t *testing.T) {
+	el1, el2 := primitive.NewObjectID(), primitive.NewObjectID()
+	catalog := requisites.NewCatalog([]subjectmodel.Subject{
+		{ID: el1, SubjectCode: "EL1", Credit: 3},
+		{ID: el2, SubjectCode: "EL2", Credit: 2, CoRequisite: []string{el1.Hex()}},
+	})
+	curriculum := curriculummodel.Curriculum{
+		TotalCredits: 2,
+		Groups: []curriculummodel.RequirementGroup{
+			{Name: "Electives", Kind: curriculummodel.GroupElective, MinCount: 1, Subjects: []curriculummodel.CurriculumSubject{{SubjectID: el2}}},
+		},
+	}
+
+	report := Evaluate(curriculum, catalog, nil)
+	if len(report.Eligible) != 1 || !reflect.DeepEqual(report.Eligible[0].TakeWith, []string{"EL1"}) {
+		t.Errorf("Evaluate() eligible = %+v, want EL2 taken with EL1", report.Eligible)
+	}
+}
+
+func TestEvaluateGroup(t *testing.T) {
+	a, b := primitive.NewObjectID(), primitive.NewObjectID()
+	catalog := requisites.NewCatalog([]subjectmodel.Subject{
+		{ID: a, SubjectCode: "A", Credit: 3},
+		{ID: b, SubjectCode: "B", Credit: 2},
+	})
+	subjects := []curriculummodel.CurriculumSubject{{SubjectID: a}, {SubjectID: b}, {SubjectID: primitive.NewObjectID()}}
+
+	tests := []struct {
+		name          string
+		group         curriculummodel.RequirementGroup
+		passed        map[string]bool
+		wantSatisfied bool
+		wantRequired  int
+		wantEarned    int
+	}{
+		{
+			name:         "core needs every known subject",
+			group:        curriculummodel.RequirementGroup{Kind: curriculummodel.GroupCore, Subjects: subjects},
+			passed:       map[string]bool{"A": true},
+			wantRequired: 5,
+			wantEarned:   3,
+		},
+		{
+			name:          "core complete ignores deleted subjects",
+			group:         curriculummodel.RequirementGroup{Kind: curriculummodel.GroupCore, Subjects: subjects},
+			passed:        map[string]bool{"A": true, "B": true},
+			wantSatisfied: true,
+			wantRequired:  5,
+			wantEarned:    5,
+		},
+		{
+			name:          "elective by credits",
+			group:         curriculummodel.RequirementGroup{Kind: curriculummodel.GroupElective, MinCredits: 3, Subjects: subjects},
+			passed:        map[string]bool{"A": true},
+			wantSatisfied: true,
+			wantRequired:  3,
+			wantEarned:    3,
+		},
+		{
+			name:         "elective needs both credits and count",
+			group:        curriculummodel.RequirementGroup{Kind: curriculummodel.GroupElective, MinCredits: 3, MinCount: 2, Subjects: subjects},
+			passed:       map[string]bool{"A": true},
+			wantRequired: 3,
+			wantEarned:   3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluateGroup(tt.group, catalog, tt.passed)
+			if got.Satisfied != tt.wantSatisfied || got.RequiredCredits != tt.wantRequired || got.EarnedCredits != tt.wantEarned {
+				t.Errorf("evaluateGroup() satisfied=%v required=%d earned=%d, want %v %d %d",
+					got.Satisfied, got.RequiredCredits, got.EarnedCredits, tt.wantSatisfied, tt.wantRequired, tt.wantEarned)
+			}
+		})
+	}
+}
+
+func eligibleCodes(subjects []degreeauditmodel.EligibleSubject) []string {
+	var codes []string
+	for _, s := range subjects {
+		codes = append(codes, s.SubjectCode)
+	}
+	return codes
+}
+
+func blockedCodes(subjects []degreeauditmodel.BlockedSubject) map[string][]string {
+	var codes map[string][]string
+	for _, s := range subjects {
+		if codes == nil {
+			codes = map[string][]string{}
+		}
+		codes[s.SubjectCode] = s.MissingPrerequisites
+	}
+	return codes
+}
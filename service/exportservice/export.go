@@ -0,0 +1,175 @@
+package exportservice
+
+import (
+	"BackendCoursyclopedia/catalogio"
+	"BackendCoursyclopedia/model/facultymodel"
+	"BackendCoursyclopedia/model/majormodel"
+	"BackendCoursyclopedia/model/subjectmodel"
+	"BackendCoursyclopedia/repository/facultyrepository"
+	"BackendCoursyclopedia/repository/majorrepository"
+	"BackendCoursyclopedia/repository/subjectrepository"
+	"context"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ExportFilter narrows an export to one faculty and/or one major. Empty
+// fields match everything.
+type ExportFilter struct {
+	FacultyID string
+	MajorID   string
+}
+
+type IExportService interface {
+	Prepare(ctx context.Context, filter ExportFilter) (*Export, error)
+}
+
+type ExportService struct {
+	FacultyRepository facultyrepository.IFacultyRepository
+	MajorRepository   majorrepository.IMajorRepository
+	SubjectRepository subjectrepository.ISubjectRepository
+}
+
+func NewExportService(facultyRepo facultyrepository.IFacultyRepository, majorRepo majorrepository.IMajorRepository, subjectRepo subjectrepository.ISubjectRepository) IExportService {
+	return &ExportService{
+		FacultyRepository: facultyRepo,
+		MajorRepository:   majorRepo,
+		SubjectRepository: subjectRepo,
+	}
+}
+
+// Export is a resolved faculty→major plan whose subjects are read and
+// written one at a time by WriteTo.
+type Export struct {
+	groups            []exportGroup
+	codes             map[primitive.ObjectID]string
+	subjectRepository subjectrepository.ISubjectRepository
+}
+
+type exportGroup struct {
+	faculty facultymodel.Faculty
+	major   majormodel.Major
+}
+
+// Prepare resolves the filter against faculties and majors so that unknown
+// IDs fail before any output is sent. Only faculties and majors are loaded;
+// subjects are streamed later.
+func (s *ExportService) Prepare(ctx context.Context, filter ExportFilter) (*Export, error) {
+	var faculties []facultymodel.Faculty
+	if filter.FacultyID != "" {
+		faculty, err := s.FacultyRepository.FindFacultyByID(ctx, filter.FacultyID)
+		if err != nil {
+			return nil, err
+		}
+		faculties = []facultymodel.Faculty{*faculty}
+	} else {
+		var err error
+		faculties, err = s.FacultyRepository.FindAllFaculties(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var onlyMajor primitive.ObjectID
+	if filter.MajorID != "" {
+		major, err := s.MajorRepository.FindmajorbyID(ctx, filter.MajorID)
+		if err != nil {
+			return nil, err
+		}
+		onlyMajor = major.ID
+	}
+
+	majors, err := s.MajorRepository.FindAllMajors(ctx)
+	if err != nil {
+		return nil, err
+	}
+	majorsByID := map[primitive.ObjectID]majormodel.Major{}
+	for _, m := range majors {
+		majorsByID[m.ID] = m
+	}
+
+	codes, err := s.SubjectRepository.FindSubjectCodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(faculties, func(i, j int) bool {
+		return strings.ToLower(faculties[i].FacultyName) < strings.ToLower(faculties[j].FacultyName)
+	})
+
+	export := &Export{codes: codes, subjectRepository: s.SubjectRepository}
+	for _, f := range faculties {
+		var groups []exportGroup
+		for _, id := range f.MajorIDs {
+			major, ok := majorsByID[id]
+			if !ok || (!onlyMajor.IsZero() && id != onlyMajor) {
+				continue
+			}
+			f.Image = nil
+			groups = append(groups, exportGroup{faculty: f, major: major})
+		}
+		sort.Slice(groups, func(i, j int) bool {
+			return strings.ToLower(groups[i].major.MajorName) < strings.ToLower(groups[j].major.MajorName)
+		})
+		export.groups = append(export.groups, groups...)
+	}
+	return export, nil
+}
+
+// WriteTo writes every subject of the planned majors to w and closes it.
+func (e *Export) WriteTo(ctx context.Context, w catalogio.SubjectWriter) error {
+	for _, g := range e.groups {
+		err := e.subjectRepository.EachSubjectByIDs(ctx, g.major.SubjectIDs, func(subject subjectmodel.Subject) error {
+			return w.Write(e.row(g, subject))
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
+
+func (e *Export) row(g exportGroup, subject subjectmodel.Subject) subjectmodel.SubjectExportRow {
+	credit := subject.Credit
+	professors := make([]string, len(subject.Professors))
+	for i, p := range subject.Professors {
+		professors[i] = p.Hex()
+	}
+
+	return subjectmodel.SubjectExportRow{
+		Faculty:   g.faculty.FacultyName,
+		FacultyID: g.faculty.ID.Hex(),
+		SubjectID: subject.ID.Hex(),
+		SubjectImportRow: subjectmodel.SubjectImportRow{
+			SubjectCode:        subject.SubjectCode,
+			Name:               subject.Name,
+			Major:              g.major.MajorName,
+			MajorID:            g.major.ID.Hex(),
+			SubjectDescription: subject.SubjectDescription,
+			Campus:             subject.Campus,
+			Credit:             &credit,
+			Professors:         professors,
+			PreRequisite:       e.resolveCodes(subject.PreRequisite),
+			CoRequisite:        e.resolveCodes(subject.CoRequisite),
+			SubjectStatus:      subject.SubjectStatus,
+			AvailableDuration:  subject.AvailableDuration,
+		},
+	}
+}
+
+// resolveCodes replaces requisites stored as subject IDs with the subject's
+// code. Values that are already codes, or IDs of deleted subjects, are kept.
+func (e *Export) resolveCodes(refs []string) []string {
+	codes := make([]string, len(refs))
+	for i, ref := range refs {
+		codes[i] = ref
+		if id, err := primitive.ObjectIDFromHex(ref); err == nil {
+			if code, ok := e.codes[id]; ok {
+				codes[i] = code
+			}
+		}
+	}
+	return codes
+}
@@ -12,8 +12,8 @@ type IFacultyService interface {
 	GetAllFaculties(ctx context.Context) ([]facultymodel.Faculty, error)
 	GetFacultyByID(ctx context.Context, facultyID string) (*facultymodel.Faculty, error)
 	GetMajorsForFaculty(ctx context.Context, facultyId string) ([]majormodel.Major, error)
-	CreateFaculty(ctx context.Context, faculty facultymodel.Faculty, image []byte) (facultymodel.Faculty, error)
-	UpdateFaculty(ctx context.Context, facultyID string, faculty facultymodel.Faculty, image []byte) (facultymodel.Faculty, error)
+	CreateFaculty(ctx context.Context, request facultymodel.FacultyCreateRequest, image []byte) (facultymodel.Faculty, error)
+	UpdateFaculty(ctx context.Context, facultyID string, request facultymodel.FacultyUpdateRequest, image []byte) (facultymodel.Faculty, error)
 	DeleteFaculty(ctx context.Context, facultyID string) error
 }
 
@@ -51,14 +51,12 @@ func (s *FacultyService) GetMajorsForFaculty(ctx context.Context, facultyId stri
 	return majors, nil
 }
 
-func (s *FacultyService) CreateFaculty(ctx context.Context, faculty facultymodel.Faculty, image []byte) (facultymodel.Faculty, error) {
-	facultyName := faculty.FacultyName
-
-	return s.FacultyRepository.CreateFaculty(ctx, facultyName, image)
+func (s *FacultyService) CreateFaculty(ctx context.Context, request facultymodel.FacultyCreateRequest, image []byte) (facultymodel.Faculty, error) {
+	return s.FacultyRepository.CreateFaculty(ctx, request.FacultyName, image)
 }
 
-func (s *FacultyService) UpdateFaculty(ctx context.Context, facultyID string, faculty facultymodel.Faculty, image []byte) (facultymodel.Faculty, error) {
-	return s.FacultyRepository.UpdateFaculty(ctx, facultyID, faculty, image)
+func (s *FacultyService) UpdateFaculty(ctx context.Context, facultyID string, request facultymodel.FacultyUpdateRequest, image []byte) (facultymodel.Faculty, error) {
+	return s.FacultyRepository.UpdateFaculty(ctx, facultyID, request.FacultyName, image)
 }
 
 func (s FacultyService) DeleteFaculty(ctx context.Context, facultyID string) error {
@@ -0,0 +1,235 @@
+package integrityservice
+
+import (
+	"BackendCoursyclopedia/logger"
+	"BackendCoursyclopedia/model/integritymodel"
+	"BackendCoursyclopedia/repository/facultyrepository"
+	"BackendCoursyclopedia/repository/integrityrepository"
+	"BackendCoursyclopedia/repository/majorrepository"
+	"BackendCoursyclopedia/repository/subjectrepository"
+	userrepo "BackendCoursyclopedia/repository/userrepository"
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IIntegrityService interface {
+	Scan(ctx context.Context) (*integritymodel.Report, error)
+	Check(ctx context.Context, fix bool) (*integritymodel.Report, error)
+	GetLatestReport(ctx context.Context) (*integritymodel.Report, error)
+}
+
+type IntegrityService struct {
+	IntegrityRepository integrityrepository.IIntegrityRepository
+	FacultyRepository   facultyrepository.IFacultyRepository
+	MajorRepository     majorrepository.IMajorRepository
+	SubjectRepository   subjectrepository.ISubjectRepository
+	UserRepository      userrepo.IUserRepository
+}
+
+func NewIntegrityService(integrityRepo integrityrepository.IIntegrityRepository, facultyRepo facultyrepository.IFacultyRepository, majorRepo majorrepository.IMajorRepository, subjectRepo subjectrepository.ISubjectRepository, userRepo userrepo.IUserRepository) IIntegrityService {
+	return &IntegrityService{
+		IntegrityRepository: integrityRepo,
+		FacultyRepository:   facultyRepo,
+		MajorRepository:     majorRepo,
+		SubjectRepository:   subjectRepo,
+		UserRepository:      userRepo,
+	}
+}
+
+func (s *IntegrityService) GetLatestReport(ctx context.Context) (*integritymodel.Report, error) {
+	return s.IntegrityRepository.FindLatestReport(ctx)
+}
+
+// Check scans the database like Scan and stores the report. With fix set,
+// dangling references are pulled from their parent and a document with
+// several parents is kept only in the oldest one. Orphans are reported
+// but never changed since there is no safe parent to pick.
+func (s *IntegrityService) Check(ctx context.Context, fix bool) (*integritymodel.Report, error) {
+	report, err := s.Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report.FixMode = fix
+
+	if fix {
+		for i := range report.Anomalies {
+			fixed, err := s.fix(ctx, report.Anomalies[i])
+			if err != nil {
+				return nil, err
+			}
+			report.Anomalies[i].Fixed = fixed
+		}
+	}
+
+	report.FinishedAt = time.Now().UTC()
+	id, err := s.IntegrityRepository.SaveReport(ctx, *report)
+	if err != nil {
+		return nil, err
+	}
+	report.ID = id
+
+	logger.FromContext(ctx).Info("integrity check finished", "fix", fix, "anomalies", len(report.Anomalies))
+	return report, nil
+}
+
+// Scan reads faculties, majors, subjects and users and reports references
+// that do not resolve, majors and subjects without a parent, and majors or
+// subjects with more than one parent. It changes nothing and stores nothing.
+func (s *IntegrityService) Scan(ctx context.Context) (*integritymodel.Report, error) {
+	report := &integritymodel.Report{
+		StartedAt: time.Now().UTC(),
+		Counts:    map[string]int{},
+		Anomalies: []integritymodel.Anomaly{},
+	}
+
+	faculties, err := s.FacultyRepository.FindAllFaculties(ctx)
+	if err != nil {
+		return nil, err
+	}
+	majors, err := s.MajorRepository.FindAllMajors(ctx)
+	if err != nil {
+		return nil, err
+	}
+	subjects, err := s.SubjectRepository.FindAllSubjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.UserRepository.FindAllUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	facultyExists := map[primitive.ObjectID]bool{}
+	for _, f := range faculties {
+		facultyExists[f.ID] = true
+	}
+	majorExists := map[primitive.ObjectID]bool{}
+	for _, m := range majors {
+		majorExists[m.ID] = true
+	}
+	subjectExists := map[primitive.ObjectID]bool{}
+	for _, sub := range subjects {
+		subjectExists[sub.ID] = true
+	}
+
+	add := func(a integritymodel.Anomaly) {
+		report.Anomalies = append(report.Anomalies, a)
+		report.Counts[a.Kind]++
+	}
+
+	majorParents := map[primitive.ObjectID][]primitive.ObjectID{}
+	for _, f := range faculties {
+		for _, id := range f.MajorIDs {
+			if !majorExists[id] {
+				add(integritymodel.Anomaly{
+					Kind: integritymodel.KindDanglingMajor, Collection: "faculties", DocumentID: f.ID, Field: "majorIDs", RefID: id,
+					Detail: fmt.Sprintf("faculty %q references missing major %s", f.FacultyName, id.Hex()),
+				})
+				continue
+			}
+			majorParents[id] = append(majorParents[id], f.ID)
+		}
+	}
+
+	subjectParents := map[primitive.ObjectID][]primitive.ObjectID{}
+	for _, m := range majors {
+		for _, id := range m.SubjectIDs {
+			if !subjectExists[id] {
+				add(integritymodel.Anomaly{
+					Kind: integritymodel.KindDanglingSubject, Collection: "majors", DocumentID: m.ID, Field: "subjectIDs", RefID: id,
+					Detail: fmt.Sprintf("major %q references missing subject %s", m.MajorName, id.Hex()),
+				})
+				continue
+			}
+			subjectParents[id] = append(subjectParents[id], m.ID)
+		}
+
+		switch parents := majorParents[m.ID]; {
+		case len(parents) == 0:
+			add(integritymodel.Anomaly{
+				Kind: integritymodel.KindOrphanMajor, Collection: "majors", DocumentID: m.ID,
+				Detail: fmt.Sprintf("major %q belongs to no faculty", m.MajorName),
+			})
+		case len(parents) > 1:
+			sortOldestFirst(parents)
+			add(integritymodel.Anomaly{
+				Kind: integritymodel.KindMultiParentMajor, Collection: "majors", DocumentID: m.ID, Parents: parents,
+				Detail: fmt.Sprintf("major %q belongs to %d faculties", m.MajorName, len(parents)),
+			})
+		}
+	}
+
+	for _, sub := range subjects {
+		switch parents := subjectParents[sub.ID]; {
+		case len(parents) == 0:
+			add(integritymodel.Anomaly{
+				Kind: integritymodel.KindOrphanSubject, Collection: "subjects", DocumentID: sub.ID,
+				Detail: fmt.Sprintf("subject %s belongs to no major", sub.SubjectCode),
+			})
+		case len(parents) > 1:
+			sortOldestFirst(parents)
+			add(integritymodel.Anomaly{
+				Kind: integritymodel.KindMultiParentSubject, Collection: "subjects", DocumentID: sub.ID, Parents: parents,
+				Detail: fmt.Sprintf("subject %s belongs to %d majors", sub.SubjectCode, len(parents)),
+			})
+		}
+	}
+
+	for _, u := range users {
+		for _, id := range u.Wishlists {
+			if !subjectExists[id] {
+				add(integritymodel.Anomaly{
+					Kind: integritymodel.KindDanglingSubject, Collection: "users", DocumentID: u.ID, Field: "wishlists", RefID: id,
+					Detail: fmt.Sprintf("user %s wishlists missing subject %s", u.Email, id.Hex()),
+				})
+			}
+		}
+		if !u.FacultyID.IsZero() && !facultyExists[u.FacultyID] {
+			add(integritymodel.Anomaly{
+				Kind: integritymodel.KindDanglingFaculty, Collection: "users", DocumentID: u.ID, Field: "facultyId", RefID: u.FacultyID,
+				Detail: fmt.Sprintf("user %s belongs to missing faculty %s", u.Email, u.FacultyID.Hex()),
+			})
+		}
+	}
+
+	report.FinishedAt = time.Now().UTC()
+	return report, nil
+}
+
+// sortOldestFirst orders ids by creation, which ObjectIDs encode in their
+// leading bytes, so the parent kept by a fix does not depend on scan order.
+func sortOldestFirst(ids []primitive.ObjectID) {
+	sort.Slice(ids, func(i, j int) bool {
+		return bytes.Compare(ids[i][:], ids[j][:]) < 0
+	})
+}
+
+func (s *IntegrityService) fix(ctx context.Context, a integritymodel.Anomaly) (bool, error) {
+	switch a.Kind {
+	case integritymodel.KindDanglingMajor, integritymodel.KindDanglingSubject:
+		if err := s.IntegrityRepository.PullReference(ctx, a.Collection, a.DocumentID, a.Field, a.RefID); err != nil {
+			return false, err
+		}
+		return true, nil
+	case integritymodel.KindMultiParentMajor:
+		for _, facultyID := range a.Parents[1:] {
+			if err := s.IntegrityRepository.PullReference(ctx, "faculties", facultyID, "majorIDs", a.DocumentID); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	case integritymodel.KindMultiParentSubject:
+		for _, majorID := range a.Parents[1:] {
+			if err := s.IntegrityRepository.PullReference(ctx, "majors", majorID, "subjectIDs", a.DocumentID); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,252 @@
+package integrityservice
+
+import (
+	"BackendCoursyclopedia/model/facultymodel"
+	"BackendCoursyclopedia/model/integritymodel"
+	"BackendCoursyclopedia/model/majormodel"
+	"BackendCoursyclopedia/model/subjectmodel"
+	"BackendCoursyclopedia/model/usermodel"
+	"BackendCoursyclopedia/repository/facultyrepository"
+	"BackendCoursyclopedia/repository/integrityrepository"
+	"BackendCoursyclopedia/repository/majorrepository"
+	"BackendCoursyclopedia/repository/subjectrepository"
+	userrepo "BackendCoursyclopedia/repository/userrepository"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type fakeFacultyRepository struct {
+	facultyrepository.IFacultyRepository
+	faculties []facultymodel.Faculty
+}
+
+func (r *fakeFacultyRepository) FindAllFaculties(context.Context) ([]facultymodel.Faculty, error) {
+	return r.faculties, nil
+}
+
+type fakeMajorRepository struct {
+	majorrepository.IMajorRepository
+	majors []majormodel.Major
+}
+
+func (r *fakeMajorRepository) FindAllMajors(context.Context) ([]majormodel.Major, error) {
+	return r.majors, nil
+}
+
+type fakeSubjectRepository struct {
+	subjectrepository.ISubjectRepository
+	subjects []subjectmodel.Subject
+}
+
+func (r *fakeSubjectRepository) FindAllSubjects(context.Context) ([]subjectmodel.Subject, error) {
+	return r.subjects, nil
+}
+
+type fakeUserRepository struct {
+	userrepo.IUserRepository
+	users []usermodel.User
+}
+
+func (r *fakeUserRepository) FindAllUsers(context.Context) ([]usermodel.User, error) {
+	return r.users, nil
+}
+
+type pull struct {
+	collection string
+	parentID   primitive.ObjectID
+	field      string
+	refID      primitive.ObjectID
+}
+
+type fakeIntegrityRepository struct {
+	integrityrepository.IIntegrityRepository
+	pulls []pull
+	saved []integritymodel.Report
+}
+
+func (r *fakeIntegrityRepository) PullReference(_ context.Context, collection string, parentID primitive.ObjectID, field string, refID primitive.ObjectID) error {
+	r.pulls = append(r.pulls, pull{collection, parentID, field, refID})
+	return nil
+}
+
+func (r *fakeIntegrityRepository) SaveReport(_ context.Context, report integritymodel.Report) (primitive.ObjectID, error) {
+	r.saved = append(r.saved, report)
+	return primitive.NewObjectID(), nil
+}
+
+// idAt returns an ObjectID created n minutes after a fixed time, so tests can
+// order documents by age.
+func idAt(n int) primitive.ObjectID {
+	return primitive.NewObjectIDFromTimestamp(time.Date(2024, 1, 1, 0, n, 0, 0, time.UTC))
+}
+
+type catalog struct {
+	faculties []facultymodel.Faculty
+	majors    []majormodel.Major
+	subjects  []subjectmodel.Subject
+	users     []usermodel.User
+}
+
+func newService(c catalog) (*IntegrityService, *fakeIntegrityRepository) {
+	integrityRepo := &fakeIntegrityRepository{}
+	return &IntegrityService{
+		IntegrityRepository: integrityRepo,
+		FacultyRepository:   &fakeFacultyRepository{faculties: c.faculties},
+		MajorRepository:     &fakeMajorRepository{majors: c.majors},
+		SubjectRepository:   &fakeSubjectRepository{subjects: c.subjects},
+		UserRepository:      &fakeUserRepository{users: c.users},
+	}, integrityRepo
+}
+
+func TestScan(t *testing.T) {
+	var (
+		oldFaculty, newFaculty = idAt(1), idAt(2)
+		majorA, majorB         = idAt(3), idAt(4)
+		subject, otherSubject  = idAt(5), idAt(6)
+		missing                = idAt(7)
+		user                   = idAt(8)
+	)
+
+	tests := []struct {
+		name    string
+		catalog catalog
+		want    map[string]int
+	}{
+		{
+			name: "consistent catalog",
+			catalog: catalog{
+				faculties: []facultymodel.Faculty{{ID: oldFaculty, MajorIDs: []primitive.ObjectID{majorA}}},
+				majors:    []majormodel.Major{{ID: majorA, SubjectIDs: []primitive.ObjectID{subject}}},
+				subjects:  []subjectmodel.Subject{{ID: subject}},
+				users:     []usermodel.User{{ID: user, FacultyID: oldFaculty, Wishlists: []primitive.ObjectID{subject}}},
+			},
+			want: map[string]int{},
+		},
+		{
+			name: "dangling references",
+			catalog: catalog{
+				faculties: []facultymodel.Faculty{{ID: oldFaculty, MajorIDs: []primitive.ObjectID{majorA, missing}}},
+				majors:    []majormodel.Major{{ID: majorA, SubjectIDs: []primitive.ObjectID{subject, missing}}},
+				subjects:  []subjectmodel.Subject{{ID: subject}},
+				users:     []usermodel.User{{ID: user, FacultyID: missing, Wishlists: []primitive.ObjectID{missing}}},
+			},
+			want: map[string]int{
+				integritymodel.KindDanglingMajor:   1,
+				integritymodel.KindDanglingSubject: 2,
+				integritymodel.KindDanglingFaculty: 1,
+			},
+		},
+		{
+			name: "orphans",
+			catalog: catalog{
+				majors:   []majormodel.Major{{ID: majorA}},
+				subjects: []subjectmodel.Subject{{ID: subject}},
+			},
+			want: map[string]int{
+				integritymodel.KindOrphanMajor:   1,
+				integritymodel.KindOrphanSubject: 1,
+			},
+		},
+		{
+			name: "several parents",
+			catalog: catalog{
+				faculties: []facultymodel.Faculty{
+					{ID: oldFaculty, MajorIDs: []primitive.ObjectID{majorA}},
+					{ID: newFaculty, MajorIDs: []primitive.ObjectID{majorA, majorB}},
+				},
+				majors: []majormodel.Major{
+					{ID: majorA, SubjectIDs: []primitive.ObjectID{subject, otherSubject}},
+					{ID: majorB, SubjectIDs: []primitive.ObjectID{subject}},
+				},
+				subjects: []subjectmodel.Subject{{ID: subject}, {ID: otherSubject}},
+			},
+			want: map[string]int{
+				integritymodel.KindMultiParentMajor:   1,
+				integritymodel.KindMultiParentSubject: 1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, integrityRepo := newService(tt.catalog)
+			report, err := service.Scan(context.Background())
+			if err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+			if !reflect.DeepEqual(report.Counts, tt.want) {
+				t.Errorf("Scan() counts = %v, want %v", report.Counts, tt.want)
+			}
+			if len(integrityRepo.pulls) != 0 || len(integrityRepo.saved) != 0 {
+				t.Errorf("Scan() wrote to the repository")
+			}
+		})
+	}
+}
+
+func TestCheckFixKeepsOldestParent(t *testing.T) {
+	var (
+		oldest, middle, newest = idAt(1), idAt(2), idAt(3)
+		major, subject         = idAt(4), idAt(5)
+		missing                = idAt(6)
+		oldMajor, newMajor     = idAt(7), idAt(8)
+	)
+
+	// Parents are listed newest first so the scan order differs from age.
+	c := catalog{
+		faculties: []facultymodel.Faculty{
+			{ID: newest, MajorIDs: []primitive.ObjectID{major, missing}},
+			{ID: middle, MajorIDs: []primitive.ObjectID{major, oldMajor}},
+			{ID: oldest, MajorIDs: []primitive.ObjectID{major, newMajor}},
+		},
+		majors: []majormodel.Major{
+			{ID: major},
+			{ID: newMajor, SubjectIDs: []primitive.ObjectID{subject}},
+			{ID: oldMajor, SubjectIDs: []primitive.ObjectID{subject}},
+		},
+		subjects: []subjectmodel.Subject{{ID: subject}},
+	}
+
+	tests := []struct {
+		name      string
+		fix       bool
+		wantPulls []pull
+	}{
+		{name: "report only", fix: false},
+		{
+			name: "fix",
+			fix:  true,
+			wantPulls: []pull{
+				{"faculties", newest, "majorIDs", missing},
+				{"faculties", middle, "majorIDs", major},
+				{"faculties", newest, "majorIDs", major},
+				{"majors", newMajor, "subjectIDs", subject},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, integrityRepo := newService(c)
+			report, err := service.Check(context.Background(), tt.fix)
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if !reflect.DeepEqual(integrityRepo.pulls, tt.wantPulls) {
+				t.Errorf("Check() pulls = %v, want %v", integrityRepo.pulls, tt.wantPulls)
+			}
+			if len(integrityRepo.saved) != 1 || report.FixMode != tt.fix {
+				t.Errorf("Check() saved %d reports with FixMode %v", len(integrityRepo.saved), report.FixMode)
+			}
+			for _, a := range report.Anomalies {
+				if a.Fixed != tt.fix {
+					t.Errorf("anomaly %s Fixed = %v, want %v", a.Kind, a.Fixed, tt.fix)
+				}
+			}
+		})
+	}
+}
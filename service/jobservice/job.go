@@ -0,0 +1,305 @@
+package jobservice
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/cron"
+	"BackendCoursyclopedia/logger"
+	"BackendCoursyclopedia/model/jobmodel"
+	"BackendCoursyclopedia/repository/jobrepository"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultMaxAttempts  = 3
+	defaultTimeout      = 10 * time.Minute
+	defaultWorkers      = 2
+	defaultPollInterval = 5 * time.Second
+	defaultRunsLimit    = 50
+	maxRunsLimit        = 500
+)
+
+// Job is a kind of background work. Run must be safe to call again after a
+// failure or a crash, because a run is retried until it succeeds or has used
+// MaxAttempts attempts.
+type Job struct {
+	Name        string
+	Description string
+	// Schedule is a cron expression (see package cron). Jobs without one
+	// only run when triggered or enqueued.
+	Schedule    string
+	MaxAttempts int
+	Timeout     time.Duration
+	Run         func(ctx context.Context, payload bson.M) error
+}
+
+type IJobService interface {
+	Register(job Job)
+	Run(ctx context.Context)
+	Enqueue(ctx context.Context, name string, payload bson.M) (*jobmodel.Run, error)
+	GetJobs(ctx context.Context) ([]jobmodel.Info, error)
+	TriggerJob(ctx context.Context, name string, payload bson.M, userID string) (*jobmodel.Run, error)
+	GetRuns(ctx context.Context, filter jobmodel.Filter) ([]jobmodel.Run, error)
+	GetRun(ctx context.Context, runID string) (*jobmodel.Run, error)
+	GetDeadLetters(ctx context.Context, limit int64) ([]jobmodel.Run, error)
+	RetryRun(ctx context.Context, runID string) (*jobmodel.Run, error)
+	DiscardRun(ctx context.Context, runID string) error
+}
+
+type JobService struct {
+	JobRepository jobrepository.IJobRepository
+	// Location is the time zone cron schedules are evaluated in.
+	Location *time.Location
+	// Owner identifies this instance on the leases it takes.
+	Owner string
+	// Workers is how many runs this instance executes at once; 0 leaves all
+	// jobs to other instances.
+	Workers      int
+	PollInterval time.Duration
+
+	jobs  map[string]*registeredJob
+	names []string
+	wake  chan struct{}
+}
+
+type registeredJob struct {
+	Job
+	schedule cron.Schedule
+}
+
+// NewJobService reads JOB_WORKERS and JOB_POLL_INTERVAL from the
+// environment. Jobs must be registered before Run is called.
+func NewJobService(jobRepo jobrepository.IJobRepository, location *time.Location) IJobService {
+	hostname, _ := os.Hostname()
+	return &JobService{
+		JobRepository: jobRepo,
+		Location:      location,
+		Owner:         hostname + "/" + uuid.NewString(),
+		Workers:       envInt("JOB_WORKERS", defaultWorkers),
+		PollInterval:  envDuration("JOB_POLL_INTERVAL", defaultPollInterval),
+		jobs:          map[string]*registeredJob{},
+		wake:          make(chan struct{}, 1),
+	}
+}
+
+// Register adds job to the set this instance can run. JOB_SCHEDULE_<NAME>,
+// with the name upper-cased and dots and dashes turned into underscores,
+// replaces the job's schedule; "off" removes it. It panics on a duplicate
+// name or an invalid built-in schedule, which are programming errors.
+func (s *JobService) Register(job Job) {
+	if _, ok := s.jobs[job.Name]; ok {
+		panic(fmt.Sprintf("duplicate job %q", job.Name))
+	}
+	if job.MaxAttempts <= 0 {
+		job.MaxAttempts = defaultMaxAttempts
+	}
+	if job.Timeout <= 0 {
+		job.Timeout = defaultTimeout
+	}
+
+	registered := &registeredJob{Job: job}
+	if job.Schedule != "" {
+		schedule, err := cron.Parse(job.Schedule)
+		if err != nil {
+			panic(fmt.Sprintf("job %q: %v", job.Name, err))
+		}
+		registered.schedule = schedule
+	}
+	s.overrideSchedule(registered)
+
+	s.jobs[job.Name] = registered
+	s.names = append(s.names, job.Name)
+}
+
+func (s *JobService) overrideSchedule(job *registeredJob) {
+	key := "JOB_SCHEDULE_" + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(job.Name))
+	spec, ok := os.LookupEnv(key)
+	if !ok {
+		return
+	}
+	if strings.EqualFold(spec, "off") {
+		job.Schedule, job.schedule = "", nil
+		return
+	}
+	schedule, err := cron.Parse(spec)
+	if err != nil {
+		logger.L().Error("invalid job schedule, keeping the default", "variable", key, "value", spec, "default", job.Schedule, "error", err)
+		return
+	}
+	job.Schedule, job.schedule = spec, schedule
+}
+
+// Enqueue queues a run of the named job to start as soon as a worker is
+// free.
+func (s *JobService) Enqueue(ctx context.Context, name string, payload bson.M) (*jobmodel.Run, error) {
+	return s.enqueue(ctx, name, jobmodel.Run{Trigger: jobmodel.TriggerManual, Payload: payload})
+}
+
+func (s *JobService) enqueue(ctx context.Context, name string, run jobmodel.Run) (*jobmodel.Run, error) {
+	job, ok := s.jobs[name]
+	if !ok {
+		return nil, apperror.NotFound("job %q not found", name)
+	}
+
+	now := time.Now().UTC()
+	run.Job = name
+	run.Status = jobmodel.StatusQueued
+	run.MaxAttempts = job.MaxAttempts
+	run.CreatedAt = now
+	if run.RunAt.IsZero() {
+		run.RunAt = now
+	}
+
+	id, err := s.JobRepository.EnqueueRun(ctx, run)
+	if err != nil {
+		return nil, err
+	}
+	run.ID = id
+	s.wakeUp()
+	return &run, nil
+}
+
+// GetJobs lists the registered jobs with their next scheduled time and
+// their most recent run.
+func (s *JobService) GetJobs(ctx context.Context) ([]jobmodel.Info, error) {
+	now := time.Now().In(s.Location)
+	infos := make([]jobmodel.Info, 0, len(s.names))
+	for _, name := range s.names {
+		job := s.jobs[name]
+		info := jobmodel.Info{
+			Name:        job.Name,
+			Description: job.Description,
+			Schedule:    job.Schedule,
+			MaxAttempts: job.MaxAttempts,
+			Timeout:     job.Timeout.String(),
+		}
+		if job.schedule != nil {
+			if next := job.schedule.Next(now); !next.IsZero() {
+				info.NextRun = &next
+			}
+		}
+
+		last, err := s.JobRepository.FindLastRun(ctx, name)
+		if err != nil && !apperror.Is(err, apperror.KindNotFound) {
+			return nil, err
+		}
+		info.LastRun = last
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// TriggerJob queues a run of the named job on behalf of an admin.
+func (s *JobService) TriggerJob(ctx context.Context, name string, payload bson.M, userID string) (*jobmodel.Run, error) {
+	run := jobmodel.Run{Trigger: jobmodel.TriggerManual, Payload: payload}
+	if id, err := primitive.ObjectIDFromHex(userID); err == nil {
+		run.TriggeredBy = id
+	}
+
+	queued, err := s.enqueue(ctx, name, run)
+	if err != nil {
+		return nil, err
+	}
+	logger.FromContext(ctx).Info("job triggered", "job", name, "run", queued.ID.Hex(), "user", userID)
+	return queued, nil
+}
+
+func (s *JobService) GetRuns(ctx context.Context, filter jobmodel.Filter) ([]jobmodel.Run, error) {
+	if filter.Status != "" && !isValidStatus(filter.Status) {
+		return nil, apperror.InvalidArgument("status must be one of %s", strings.Join(jobmodel.Statuses, ", "))
+	}
+	switch {
+	case filter.Limit <= 0:
+		filter.Limit = defaultRunsLimit
+	case filter.Limit > maxRunsLimit:
+		filter.Limit = maxRunsLimit
+	}
+	return s.JobRepository.FindRuns(ctx, filter)
+}
+
+func (s *JobService) GetRun(ctx context.Context, runID string) (*jobmodel.Run, error) {
+	return s.JobRepository.FindRunByID(ctx, runID)
+}
+
+// GetDeadLetters lists the runs that failed on every attempt.
+func (s *JobService) GetDeadLetters(ctx context.Context, limit int64) ([]jobmodel.Run, error) {
+	return s.GetRuns(ctx, jobmodel.Filter{Status: jobmodel.StatusDead, Limit: limit})
+}
+
+// RetryRun queues a dead run again with a fresh set of attempts.
+func (s *JobService) RetryRun(ctx context.Context, runID string) (*jobmodel.Run, error) {
+	run, err := s.JobRepository.FindRunByID(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := s.jobs[run.Job]; !ok {
+		return nil, apperror.Conflict("job %q is no longer registered", run.Job)
+	}
+	if err := s.JobRepository.RequeueRun(ctx, run.ID, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	s.wakeUp()
+
+	logger.FromContext(ctx).Info("job run retried", "job", run.Job, "run", runID)
+	return s.JobRepository.FindRunByID(ctx, runID)
+}
+
+// DiscardRun removes a dead run from the dead-letter list.
+func (s *JobService) DiscardRun(ctx context.Context, runID string) error {
+	run, err := s.JobRepository.FindRunByID(ctx, runID)
+	if err != nil {
+		return err
+	}
+	return s.JobRepository.DeleteRun(ctx, run.ID)
+}
+
+// wakeUp tells the worker loop to look for due runs now instead of at the
+// next poll.
+func (s *JobService) wakeUp() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func isValidStatus(status string) bool {
+	for _, st := range jobmodel.Statuses {
+		if st == status {
+			return true
+		}
+	}
+	return false
+}
+
+func envInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	var n int
+	if _, err := fmt.Sscan(value, &n); err != nil || n < 0 {
+		logger.L().Error("invalid "+key+", using the default", "value", value, "default", fallback)
+		return fallback
+	}
+	return n
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		logger.L().Error("invalid "+key+", using the default", "value", value, "default", fallback)
+		return fallback
+	}
+	return d
+}
@@ -0,0 +1,233 @@
+package jobservice
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/logger"
+	"BackendCoursyclopedia/metrics"
+	"BackendCoursyclopedia/model/jobmodel"
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// leaseTTL is how long a run stays leased without a heartbeat. Workers
+	// renew it every third of that.
+	leaseTTL = time.Minute
+
+	retryBaseDelay = 30 * time.Second
+	retryMaxDelay  = time.Hour
+)
+
+// Run enqueues scheduled runs and executes due runs until ctx is done, then
+// waits for the runs in progress to stop. Every instance may call it: the
+// scheduled runs of one occurrence share a dedup key and each run is leased
+// to a single worker.
+func (s *JobService) Run(ctx context.Context) {
+	if s.Workers <= 0 {
+		logger.L().Info("job workers disabled on this instance")
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, name := range s.names {
+		if job := s.jobs[name]; job.schedule != nil {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.scheduleLoop(ctx, job)
+			}()
+		}
+	}
+	s.workLoop(ctx)
+	wg.Wait()
+}
+
+// scheduleLoop enqueues a run of job at every time its schedule fires.
+func (s *JobService) scheduleLoop(ctx context.Context, job *registeredJob) {
+	for {
+		next := job.schedule.Next(time.Now().In(s.Location))
+		if next.IsZero() {
+			logger.L().Error("job schedule never fires", "job", job.Name, "schedule", job.Schedule)
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		_, err := s.enqueue(ctx, job.Name, jobmodel.Run{
+			Trigger:  jobmodel.TriggerSchedule,
+			DedupKey: job.Name + "@" + next.UTC().Format(time.RFC3339),
+			RunAt:    next.UTC(),
+		})
+		// Another instance already enqueued this occurrence.
+		if err != nil && !apperror.Is(err, apperror.KindConflict) && ctx.Err() == nil {
+			logger.L().Error("failed to enqueue scheduled job", "job", job.Name, "error", err)
+		}
+	}
+}
+
+// workLoop leases due runs while it has free workers, and otherwise waits
+// for the poll interval or a local enqueue.
+func (s *JobService) workLoop(ctx context.Context) {
+	slots := make(chan struct{}, s.Workers)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	ticker := time.NewTicker(s.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for len(slots) < cap(slots) && ctx.Err() == nil {
+			run, err := s.JobRepository.LeaseRun(ctx, s.names, s.Owner, time.Now().UTC(), leaseTTL)
+			if err != nil {
+				if !apperror.Is(err, apperror.KindNotFound) && ctx.Err() == nil {
+					logger.L().Error("failed to lease job run", "error", err)
+				}
+				break
+			}
+
+			slots <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer func() {
+					<-slots
+					wg.Done()
+					s.wakeUp()
+				}()
+				s.execute(ctx, *run)
+			}()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// execute runs one leased run and records its outcome: success, a retry
+// after a backoff, the dead-letter list once attempts run out, or back to
+// the queue if the instance is shutting down.
+func (s *JobService) execute(ctx context.Context, run jobmodel.Run) {
+	job := s.jobs[run.Job]
+	log := logger.L().With("job", run.Job, "run", run.ID.Hex(), "attempt", run.Attempts)
+	// The outcome is recorded even when ctx was cancelled by a shutdown.
+	recordCtx := context.WithoutCancel(ctx)
+
+	if run.Attempts > run.MaxAttempts {
+		// The worker holding the final attempt died.
+		err := s.JobRepository.BuryRun(recordCtx, run.ID, s.Owner, time.Now().UTC(), "lease expired on the final attempt")
+		if err != nil {
+			log.Error("failed to record job run", "error", err)
+		}
+		metrics.JobRuns.WithLabelValues(run.Job, jobmodel.StatusDead).Inc()
+		return
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, job.Timeout)
+	defer cancel()
+	runCtx = logger.WithContext(runCtx, log)
+	stopHeartbeat := s.heartbeat(runCtx, cancel, run)
+
+	log.Info("job run started", "trigger", run.Trigger)
+	start := time.Now()
+	err := safeRun(runCtx, job.Run, run.Payload)
+	elapsed := time.Since(start)
+	stopHeartbeat()
+	metrics.JobRunDuration.WithLabelValues(run.Job).Observe(elapsed.Seconds())
+	log = log.With("duration_ms", elapsed.Milliseconds())
+
+	var result string
+	now := time.Now().UTC()
+	switch {
+	case err == nil:
+		result = jobmodel.StatusSucceeded
+		err = s.JobRepository.CompleteRun(recordCtx, run.ID, s.Owner, now)
+		log.Info("job run succeeded")
+	case ctx.Err() != nil:
+		result = "interrupted"
+		err = s.JobRepository.ReleaseRun(recordCtx, run.ID, s.Owner)
+		log.Info("job run interrupted by shutdown")
+	case run.Attempts >= run.MaxAttempts:
+		result = jobmodel.StatusDead
+		log.Error("job run failed on its last attempt", "error", err)
+		err = s.JobRepository.BuryRun(recordCtx, run.ID, s.Owner, now, err.Error())
+	default:
+		result = "retry"
+		retryAt := now.Add(backoff(run.Attempts))
+		log.Warn("job run failed, will retry", "retryAt", retryAt, "error", err)
+		err = s.JobRepository.RescheduleRun(recordCtx, run.ID, s.Owner, retryAt, err.Error())
+	}
+	metrics.JobRuns.WithLabelValues(run.Job, result).Inc()
+	if err != nil {
+		log.Error("failed to record job run", "error", err)
+	}
+}
+
+// heartbeat renews the lease on run until the returned function is called.
+// If the lease is lost to another worker it cancels the run.
+func (s *JobService) heartbeat(ctx context.Context, cancel context.CancelFunc, run jobmodel.Run) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(leaseTTL / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				err := s.JobRepository.ExtendLease(ctx, run.ID, s.Owner, time.Now().UTC().Add(leaseTTL))
+				if apperror.Is(err, apperror.KindNotFound) {
+					logger.FromContext(ctx).Error("lost the lease on a job run; stopping it")
+					cancel()
+					return
+				}
+				if err != nil && ctx.Err() == nil {
+					logger.FromContext(ctx).Error("failed to renew job lease", "error", err)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// backoff doubles the delay before each retry: 30s, 1m, 2m and so on, up
+// to an hour.
+func backoff(attempt int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempt && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, retryMaxDelay)
+}
+
+// safeRun turns a panic in a job into an error so it is retried like any
+// other failure instead of taking the process down.
+func safeRun(ctx context.Context, run func(context.Context, bson.M) error, payload bson.M) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.FromContext(ctx).Error("job panicked", "panic", r, "stack", string(debug.Stack()))
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return run(ctx, payload)
+}
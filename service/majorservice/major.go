@@ -1,6 +1,7 @@
 package majorservice
 
 import (
+	"BackendCoursyclopedia/apperror"
 	"BackendCoursyclopedia/model/majormodel"
 	"BackendCoursyclopedia/model/subjectmodel"
 	"BackendCoursyclopedia/repository/facultyrepository"
@@ -15,8 +16,8 @@ type IMajorService interface {
 	GetAllMajors(ctx context.Context) ([]majormodel.Major, error)
 	GetMajorByID(ctx context.Context, majorID string) (*majormodel.Major, error)
 	GetSubjectsForMajor(ctx context.Context, majorId string) ([]subjectmodel.Subject, error)
-	CreateMajor(majorName string, facultyId string) error
-	DeleteMajor(majorId string) error
+	CreateMajor(ctx context.Context, majorName string, facultyId string) error
+	DeleteMajor(ctx context.Context, majorId string) error
 	UpdateMajor(ctx context.Context, majorId string, newMajorName string, newFacultyId string) error
 }
 
@@ -55,9 +56,7 @@ func (s *MajorService) GetSubjectsForMajor(ctx context.Context, majorId string)
 
 	return subjects, nil
 }
-func (s *MajorService) CreateMajor(majorName string, facultyId string) error {
-	ctx := context.Background()
-
+func (s *MajorService) CreateMajor(ctx context.Context, majorName string, facultyId string) error {
 	majorId, err := s.MajorRepository.CreateMajor(ctx, majorName)
 	if err != nil {
 		return err
@@ -66,12 +65,10 @@ func (s *MajorService) CreateMajor(majorName string, facultyId string) error {
 	return s.FacultyRepository.AddMajorToFaculty(ctx, facultyId, majorId)
 }
 
-func (s *MajorService) DeleteMajor(majorId string) error {
-	ctx := context.Background()
-
+func (s *MajorService) DeleteMajor(ctx context.Context, majorId string) error {
 	objId, err := primitive.ObjectIDFromHex(majorId)
 	if err != nil {
-		return err
+		return apperror.InvalidID("major", err)
 	}
 
 	err = s.MajorRepository.DeleteMajor(ctx, objId)
@@ -85,7 +82,7 @@ func (s *MajorService) DeleteMajor(majorId string) error {
 func (s *MajorService) UpdateMajor(ctx context.Context, majorId string, newMajorName string, newFacultyId string) error {
 	majorObjId, err := primitive.ObjectIDFromHex(majorId)
 	if err != nil {
-		return err
+		return apperror.InvalidID("major", err)
 	}
 
 	if newMajorName != "" {
@@ -98,7 +95,7 @@ func (s *MajorService) UpdateMajor(ctx context.Context, majorId string, newMajor
 	if newFacultyId != "" {
 		newFacObjId, err := primitive.ObjectIDFromHex(newFacultyId)
 		if err != nil {
-			return err
+			return apperror.InvalidID("faculty", err)
 		}
 
 		currentFaculty, err := s.FacultyRepository.FindFacultyByMajorId(ctx, majorObjId)
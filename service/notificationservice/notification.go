@@ -0,0 +1,253 @@
+package notificationservice
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/logger"
+	"BackendCoursyclopedia/mail"
+	"BackendCoursyclopedia/model/notificationmodel"
+	"BackendCoursyclopedia/model/subjectmodel"
+	"BackendCoursyclopedia/model/usermodel"
+	"BackendCoursyclopedia/repository/notificationrepository"
+	userrepo "BackendCoursyclopedia/repository/userrepository"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	maxAttempts  = 5
+	sendTimeout  = 30 * time.Second
+	leaseTimeout = 2 * time.Minute
+	// deliverBatch bounds how many notifications one delivery run sends, so
+	// a large backlog is spread over several runs.
+	deliverBatch = 500
+
+	retryBaseDelay = time.Minute
+	retryMaxDelay  = time.Hour
+)
+
+type INotificationService interface {
+	NotifyAccountCreated(ctx context.Context, user usermodel.User) error
+	NotifyPasswordReset(ctx context.Context, user usermodel.User) error
+	NotifyPasswordResetRequested(ctx context.Context, user usermodel.User, link string, expiresIn time.Duration) error
+	NotifyEmailVerification(ctx context.Context, user usermodel.User, link string, expiresIn time.Duration) error
+	NotifySubjectStatusChanged(ctx context.Context, subject subjectmodel.Subject, from, to string) error
+	DeliverPending(ctx context.Context) (int, error)
+	GetPreferences(ctx context.Context, userID string) (*usermodel.NotificationPreferences, error)
+	UpdatePreferences(ctx context.Context, userID string, request usermodel.NotificationPreferencesRequest) (*usermodel.NotificationPreferences, error)
+}
+
+type NotificationService struct {
+	NotificationRepository notificationrepository.INotificationRepository
+	UserRepository         userrepo.IUserRepository
+	Transport              mail.Transport
+}
+
+func NewNotificationService(notificationRepo notificationrepository.INotificationRepository, userRepo userrepo.IUserRepository, transport mail.Transport) INotificationService {
+	return &NotificationService{
+		NotificationRepository: notificationRepo,
+		UserRepository:         userRepo,
+		Transport:              transport,
+	}
+}
+
+func (s *NotificationService) NotifyAccountCreated(ctx context.Context, user usermodel.User) error {
+	return s.queue(ctx, []usermodel.User{user}, notificationmodel.KindAccountCreated, nil)
+}
+
+func (s *NotificationService) NotifyPasswordReset(ctx context.Context, user usermodel.User) error {
+	return s.queue(ctx, []usermodel.User{user}, notificationmodel.KindPasswordReset, map[string]any{
+		"At": time.Now().UTC().Format("2 January 2006 15:04 MST"),
+	})
+}
+
+// NotifyPasswordResetRequested sends the link for choosing a new password.
+func (s *NotificationService) NotifyPasswordResetRequested(ctx context.Context, user usermodel.User, link string, expiresIn time.Duration) error {
+	return s.queue(ctx, []usermodel.User{user}, notificationmodel.KindPasswordResetRequest, map[string]any{
+		"Link":           link,
+		"ExpiresInHours": int(expiresIn.Hours()),
+	})
+}
+
+// NotifyEmailVerification sends the link that confirms the user's address.
+func (s *NotificationService) NotifyEmailVerification(ctx context.Context, user usermodel.User, link string, expiresIn time.Duration) error {
+	return s.queue(ctx, []usermodel.User{user}, notificationmodel.KindEmailVerification, map[string]any{
+		"Link":           link,
+		"ExpiresInHours": int(expiresIn.Hours()),
+	})
+}
+
+// NotifySubjectStatusChanged emails everyone with subject on their wishlist
+// who has not muted subject updates.
+func (s *NotificationService) NotifySubjectStatusChanged(ctx context.Context, subject subjectmodel.Subject, from, to string) error {
+	if from == "" {
+		from = subjectmodel.StatusAvailable
+	}
+	users, err := s.UserRepository.FindUsersByWishlist(ctx, subject.ID)
+	if err != nil {
+		return err
+	}
+	return s.queue(ctx, users, notificationmodel.KindSubjectStatus, map[string]any{
+		"SubjectCode": subject.SubjectCode,
+		"SubjectName": subject.Name,
+		"From":        from,
+		"To":          to,
+	})
+}
+
+// queue renders kind for every user who wants it, in their locale, and adds
+// the emails to the outbox. data is shared by every recipient; Name and
+// Email are filled in per user.
+func (s *NotificationService) queue(ctx context.Context, users []usermodel.User, kind string, data map[string]any) error {
+	now := time.Now().UTC()
+	notifications := make([]notificationmodel.Notification, 0, len(users))
+	for _, user := range users {
+		prefs := user.NotificationPreferences()
+		if user.Email == "" || (contains(notificationmodel.OptionalKinds, kind) && !prefs.Wants(kind)) {
+			continue
+		}
+
+		userData := map[string]any{"Name": displayName(user), "Email": user.Email}
+		for k, v := range data {
+			userData[k] = v
+		}
+		email, err := render(prefs.Locale, kind, userData)
+		if err != nil {
+			return err
+		}
+
+		notifications = append(notifications, notificationmodel.Notification{
+			UserID:        user.ID,
+			Kind:          kind,
+			Locale:        email.Locale,
+			To:            user.Email,
+			Subject:       email.Subject,
+			Text:          email.Text,
+			HTML:          email.HTML,
+			Status:        notificationmodel.StatusPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			Redact:        contains(notificationmodel.RedactedKinds, kind),
+		})
+	}
+
+	if err := s.NotificationRepository.CreateNotifications(ctx, notifications); err != nil {
+		return err
+	}
+	if len(notifications) > 0 {
+		logger.FromContext(ctx).Info("notifications queued", "kind", kind, "count", len(notifications))
+	}
+	return nil
+}
+
+// DeliverPending sends due notifications from the outbox until none are
+// left or a batch has been sent, and returns how many were sent. Failed
+// sends are retried with a backoff and marked failed after maxAttempts.
+func (s *NotificationService) DeliverPending(ctx context.Context) (int, error) {
+	sent := 0
+	for i := 0; i < deliverBatch; i++ {
+		now := time.Now().UTC()
+		notification, err := s.NotificationRepository.ClaimNotification(ctx, now, now.Add(leaseTimeout))
+		if apperror.Is(err, apperror.KindNotFound) {
+			break
+		}
+		if err != nil {
+			return sent, err
+		}
+
+		delivered, err := s.send(ctx, *notification)
+		if err != nil {
+			return sent, err
+		}
+		if delivered {
+			sent++
+		}
+	}
+	if sent > 0 {
+		logger.FromContext(ctx).Info("notifications sent", "count", sent)
+	}
+	return sent, nil
+}
+
+// send delivers one claimed notification and records the outcome. It only
+// returns an error when the outcome cannot be recorded.
+func (s *NotificationService) send(ctx context.Context, n notificationmodel.Notification) (bool, error) {
+	log := logger.FromContext(ctx).With("notification_id", n.ID.Hex(), "kind", n.Kind, "attempt", n.Attempts)
+
+	sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	err := s.Transport.Send(sendCtx, mail.Message{To: n.To, Subject: n.Subject, Text: n.Text, HTML: n.HTML, Sensitive: n.Redact})
+	cancel()
+
+	switch {
+	case err == nil:
+		if err := s.NotificationRepository.MarkSent(ctx, n.ID, time.Now().UTC()); err != nil {
+			return true, err
+		}
+		return true, s.redact(ctx, n)
+	case n.Attempts >= maxAttempts:
+		log.Error("notification could not be delivered", "error", err)
+		if err := s.NotificationRepository.FailNotification(ctx, n.ID, err.Error()); err != nil {
+			return false, err
+		}
+		return false, s.redact(ctx, n)
+	default:
+		log.Warn("notification delivery failed, will retry", "error", err)
+		return false, s.NotificationRepository.RescheduleNotification(ctx, n.ID, time.Now().UTC().Add(backoff(n.Attempts)), err.Error())
+	}
+}
+
+func (s *NotificationService) redact(ctx context.Context, n notificationmodel.Notification) error {
+	if !n.Redact {
+		return nil
+	}
+	return s.NotificationRepository.RedactNotification(ctx, n.ID)
+}
+
+func (s *NotificationService) GetPreferences(ctx context.Context, userID string) (*usermodel.NotificationPreferences, error) {
+	user, err := s.UserRepository.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	prefs := user.NotificationPreferences()
+	return &prefs, nil
+}
+
+func (s *NotificationService) UpdatePreferences(ctx context.Context, userID string, request usermodel.NotificationPreferencesRequest) (*usermodel.NotificationPreferences, error) {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, apperror.InvalidID("user", err)
+	}
+
+	prefs := request.ToPreferences()
+	if err := s.UserRepository.UpdateNotificationPreferences(ctx, id, prefs); err != nil {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+func contains(kinds []string, kind string) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func displayName(user usermodel.User) string {
+	if user.Profile.FirstName != "" {
+		return user.Profile.FirstName
+	}
+	return user.Email
+}
+
+// backoff doubles the delay before each retry: 1m, 2m, 4m and so on, up to
+// an hour.
+func backoff(attempt int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempt && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, retryMaxDelay)
+}
@@ -0,0 +1,179 @@
+package notificationservice
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/mail"
+	"BackendCoursyclopedia/model/notificationmodel"
+	"BackendCoursyclopedia/repository/notificationrepository"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fakeOutbox keeps notifications in memory and claims them the way the
+// Mongo repository does: due pending ones only, counting the attempt.
+type fakeOutbox struct {
+	notificationrepository.INotificationRepository
+	notifications []*notificationmodel.Notification
+}
+
+func (f *fakeOutbox) ClaimNotification(_ context.Context, now time.Time, leaseUntil time.Time) (*notificationmodel.Notification, error) {
+	for _, n := range f.notifications {
+		if n.Status == notificationmodel.StatusPending && !n.NextAttemptAt.After(now) {
+			n.Status = notificationmodel.StatusSending
+			n.LeaseExpiresAt = &leaseUntil
+			n.Attempts++
+			claimed := *n
+			return &claimed, nil
+		}
+	}
+	return nil, apperror.NotFound("due notification not found")
+}
+
+func (f *fakeOutbox) MarkSent(_ context.Context, id primitive.ObjectID, at time.Time) error {
+	n := f.find(id)
+	n.Status, n.SentAt, n.LeaseExpiresAt, n.LastError = notificationmodel.StatusSent, &at, nil, ""
+	return nil
+}
+
+func (f *fakeOutbox) RescheduleNotification(_ context.Context, id primitive.ObjectID, nextAttemptAt time.Time, lastError string) error {
+	n := f.find(id)
+	n.Status, n.NextAttemptAt, n.LastError, n.LeaseExpiresAt = notificationmodel.StatusPending, nextAttemptAt, lastError, nil
+	return nil
+}
+
+func (f *fakeOutbox) FailNotification(_ context.Context, id primitive.ObjectID, lastError string) error {
+	n := f.find(id)
+	n.Status, n.LastError, n.LeaseExpiresAt = notificationmodel.StatusFailed, lastError, nil
+	return nil
+}
+
+func (f *fakeOutbox) find(id primitive.ObjectID) *notificationmodel.Notification {
+	for _, n := range f.notifications {
+		if n.ID == id {
+			return n
+		}
+	}
+	panic("unknown notification " + id.Hex())
+}
+
+type fakeTransport struct {
+	err  error
+	sent []mail.Message
+}
+
+func (t *fakeTransport) Send(_ context.Context, msg mail.Message) error {
+	if t.err != nil {
+		return t.err
+	}
+	t.sent = append(t.sent, msg)
+	return nil
+}
+
+func TestDeliverPending(t *testing.T) {
+	refused := errors.New("550 mailbox unavailable")
+
+	tests := []struct {
+		name          string
+		attempts      int
+		notBefore     time.Duration
+		sendErr       error
+		wantSent      int
+		wantStatus    string
+		wantAttempts  int
+		wantRetryIn   time.Duration
+		wantLastError string
+	}{
+		{name: "delivered", wantSent: 1, wantStatus: notificationmodel.StatusSent, wantAttempts: 1},
+		{name: "first failure is retried after a minute", sendErr: refused, wantStatus: notificationmodel.StatusPending, wantAttempts: 1, wantRetryIn: time.Minute, wantLastError: refused.Error()},
+		{name: "later failures back off further", attempts: 2, sendErr: refused, wantStatus: notificationmodel.StatusPending, wantAttempts: 3, wantRetryIn: 4 * time.Minute, wantLastError: refused.Error()},
+		{name: "last attempt fails for good", attempts: maxAttempts - 1, sendErr: refused, wantStatus: notificationmodel.StatusFailed, wantAttempts: maxAttempts, wantLastError: refused.Error()},
+		{name: "retry delivered", attempts: maxAttempts - 1, wantSent: 1, wantStatus: notificationmodel.StatusSent, wantAttempts: maxAttempts},
+		{name: "not yet due", notBefore: time.Hour, wantStatus: notificationmodel.StatusPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now().UTC()
+			n := &notificationmodel.Notification{
+				ID:            primitive.NewObjectID(),
+				Kind:          notificationmodel.KindAccountCreated,
+				To:            "student@example.com",
+				Subject:       "Welcome",
+				Text:          "Hello",
+				Status:        notificationmodel.StatusPending,
+				Attempts:      tt.attempts,
+				NextAttemptAt: start.Add(tt.notBefore),
+			}
+			outbox := &fakeOutbox{notifications: []*notificationmodel.Notification{n}}
+			transport := &fakeTransport{err: tt.sendErr}
+			service := &NotificationService{NotificationRepository: outbox, Transport: transport}
+
+			sent, err := service.DeliverPending(context.Background())
+			if err != nil {
+				t.Fatalf("DeliverPending() error = %v", err)
+			}
+			if sent != tt.wantSent || len(transport.sent) != tt.wantSent {
+				t.Errorf("DeliverPending() = %d with %d sent by the transport, want %d", sent, len(transport.sent), tt.wantSent)
+			}
+			if n.Status != tt.wantStatus || n.Attempts != tt.wantAttempts || n.LastError != tt.wantLastError {
+				t.Errorf("notification status, attempts, lastError = %q, %d, %q, want %q, %d, %q",
+					n.Status, n.Attempts, n.LastError, tt.wantStatus, tt.wantAttempts, tt.wantLastError)
+			}
+			if n.LeaseExpiresAt != nil {
+				t.Errorf("lease left at %v after the outcome was recorded", n.LeaseExpiresAt)
+			}
+			if tt.wantRetryIn > 0 {
+				if delay := n.NextAttemptAt.Sub(start); delay < tt.wantRetryIn || delay > tt.wantRetryIn+time.Minute {
+					t.Errorf("next attempt in %v, want %v", delay, tt.wantRetryIn)
+				}
+			}
+		})
+	}
+}
+
+func TestDeliverPendingSendsEveryDueNotification(t *testing.T) {
+	now := time.Now().UTC()
+	outbox := &fakeOutbox{}
+	for i := 0; i < 3; i++ {
+		outbox.notifications = append(outbox.notifications, &notificationmodel.Notification{
+			ID: primitive.NewObjectID(), To: "student@example.com", Status: notificationmodel.StatusPending, NextAttemptAt: now,
+		})
+	}
+	transport := &fakeTransport{}
+	service := &NotificationService{NotificationRepository: outbox, Transport: transport}
+
+	sent, err := service.DeliverPending(context.Background())
+	if err != nil || sent != 3 {
+		t.Fatalf("DeliverPending() = %d, %v, want 3, nil", sent, err)
+	}
+	for _, n := range outbox.notifications {
+		if n.Status != notificationmodel.StatusSent {
+			t.Errorf("notification %s status = %q, want sent", n.ID.Hex(), n.Status)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 0, want: time.Minute},
+		{attempt: 1, want: time.Minute},
+		{attempt: 2, want: 2 * time.Minute},
+		{attempt: 3, want: 4 * time.Minute},
+		{attempt: 6, want: 32 * time.Minute},
+		{attempt: 7, want: time.Hour},
+		{attempt: 100, want: time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package notificationservice
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+)
+
+// DefaultLocale is used when a user has no locale or one without templates.
+const DefaultLocale = "en"
+
+// Templates live in templates/<locale>/<kind>.tmpl and define "subject",
+// "text" and "html". Each locale also has a layout.tmpl with the shared
+// blocks. To translate the emails, copy templates/en to a new locale
+// directory and translate every file.
+//
+//go:embed templates
+var templateFS embed.FS
+
+type emailTemplate struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// templates maps locale and then kind to the parsed templates.
+var templates = mustParseTemplates()
+
+func mustParseTemplates() map[string]map[string]emailTemplate {
+	parsed := map[string]map[string]emailTemplate{}
+
+	locales, err := fs.ReadDir(templateFS, "templates")
+	if err != nil {
+		panic(err)
+	}
+	for _, locale := range locales {
+		dir := path.Join("templates", locale.Name())
+		files, err := fs.Glob(templateFS, dir+"/*.tmpl")
+		if err != nil {
+			panic(err)
+		}
+
+		layout := path.Join(dir, "layout.tmpl")
+		parsed[locale.Name()] = map[string]emailTemplate{}
+		for _, file := range files {
+			if file == layout {
+				continue
+			}
+			kind := strings.TrimSuffix(path.Base(file), ".tmpl")
+			parsed[locale.Name()][kind] = emailTemplate{
+				text: texttemplate.Must(texttemplate.ParseFS(templateFS, layout, file)),
+				html: htmltemplate.Must(htmltemplate.ParseFS(templateFS, layout, file)),
+			}
+		}
+	}
+	if _, ok := parsed[DefaultLocale]; !ok {
+		panic("missing templates for the default locale " + DefaultLocale)
+	}
+	return parsed
+}
+
+// resolveLocale picks the closest locale with templates for kind: the exact
+// tag, then its language, then the default.
+func resolveLocale(locale, kind string) string {
+	locale = strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	candidates := []string{locale}
+	if language, _, ok := strings.Cut(locale, "-"); ok {
+		candidates = append(candidates, language)
+	}
+	for _, candidate := range candidates {
+		if _, ok := templates[candidate][kind]; ok {
+			return candidate
+		}
+	}
+	return DefaultLocale
+}
+
+type renderedEmail struct {
+	Locale  string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// render executes the templates for kind in the closest available locale.
+func render(locale, kind string, data map[string]any) (renderedEmail, error) {
+	locale = resolveLocale(locale, kind)
+	tmpl, ok := templates[locale][kind]
+	if !ok {
+		return renderedEmail{}, fmt.Errorf("no email template for %q", kind)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := tmpl.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return renderedEmail{}, err
+	}
+	if err := tmpl.text.ExecuteTemplate(&text, "text", data); err != nil {
+		return renderedEmail{}, err
+	}
+	if err := tmpl.html.ExecuteTemplate(&html, "html", data); err != nil {
+		return renderedEmail{}, err
+	}
+
+	return renderedEmail{
+		Locale:  locale,
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+		HTML:    html.String(),
+	}, nil
+}
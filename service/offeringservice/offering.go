@@ -0,0 +1,175 @@
+package offeringservice
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/model/offeringmodel"
+	"BackendCoursyclopedia/repository/majorrepository"
+	"BackendCoursyclopedia/repository/offeringrepository"
+	"BackendCoursyclopedia/repository/sectionrepository"
+	"BackendCoursyclopedia/repository/subjectrepository"
+	"BackendCoursyclopedia/repository/termrepository"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IOfferingService interface {
+	GetOffering(ctx context.Context, offeringID string) (*offeringmodel.Offering, error)
+	GetOfferingsByTerm(ctx context.Context, termID string, status string) ([]offeringmodel.Offering, error)
+	GetOfferingsBySubject(ctx context.Context, subjectID string, termID string) ([]offeringmodel.Offering, error)
+	GetOfferingsByMajor(ctx context.Context, majorID string, termID string) ([]offeringmodel.Offering, error)
+	CreateOffering(ctx context.Context, request offeringmodel.OfferingRequest) (*offeringmodel.Offering, error)
+	UpdateOffering(ctx context.Context, offeringID string, request offeringmodel.OfferingRequest) (*offeringmodel.Offering, error)
+	DeleteOffering(ctx context.Context, offeringID string) error
+}
+
+type OfferingService struct {
+	OfferingRepository offeringrepository.IOfferingRepository
+	SectionRepository  sectionrepository.ISectionRepository
+	TermRepository     termrepository.ITermRepository
+	SubjectRepository  subjectrepository.ISubjectRepository
+	MajorRepository    majorrepository.IMajorRepository
+}
+
+func NewOfferingService(offeringRepo offeringrepository.IOfferingRepository, sectionRepo sectionrepository.ISectionRepository, termRepo termrepository.ITermRepository, subjectRepo subjectrepository.ISubjectRepository, majorRepo majorrepository.IMajorRepository) IOfferingService {
+	return &OfferingService{
+		OfferingRepository: offeringRepo,
+		SectionRepository:  sectionRepo,
+		TermRepository:     termRepo,
+		SubjectRepository:  subjectRepo,
+		MajorRepository:    majorRepo,
+	}
+}
+
+func (s *OfferingService) GetOffering(ctx context.Context, offeringID string) (*offeringmodel.Offering, error) {
+	return s.OfferingRepository.FindOfferingByID(ctx, offeringID)
+}
+
+func (s *OfferingService) GetOfferingsByTerm(ctx context.Context, termID string, status string) ([]offeringmodel.Offering, error) {
+	term, err := s.TermRepository.FindTermByID(ctx, termID)
+	if err != nil {
+		return nil, err
+	}
+	return s.OfferingRepository.FindOfferings(ctx, offeringmodel.Filter{TermID: term.ID, Status: status})
+}
+
+// GetOfferingsBySubject lists a subject's offerings, in every term unless
+// termID is given.
+func (s *OfferingService) GetOfferingsBySubject(ctx context.Context, subjectID string, termID string) ([]offeringmodel.Offering, error) {
+	subject, err := s.SubjectRepository.FindSubjectbyID(ctx, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	filter := offeringmodel.Filter{SubjectIDs: []primitive.ObjectID{subject.ID}}
+	if filter.TermID, err = optionalID(termID, "term"); err != nil {
+		return nil, err
+	}
+	return s.OfferingRepository.FindOfferings(ctx, filter)
+}
+
+// GetOfferingsByMajor lists the offerings of every subject in a major, in
+// every term unless termID is given.
+func (s *OfferingService) GetOfferingsByMajor(ctx context.Context, majorID string, termID string) ([]offeringmodel.Offering, error) {
+	major, err := s.MajorRepository.FindmajorbyID(ctx, majorID)
+	if err != nil {
+		return nil, err
+	}
+	filter := offeringmodel.Filter{SubjectIDs: major.SubjectIDs}
+	if filter.SubjectIDs == nil {
+		filter.SubjectIDs = []primitive.ObjectID{}
+	}
+	if filter.TermID, err = optionalID(termID, "term"); err != nil {
+		return nil, err
+	}
+	return s.OfferingRepository.FindOfferings(ctx, filter)
+}
+
+func (s *OfferingService) CreateOffering(ctx context.Context, request offeringmodel.OfferingRequest) (*offeringmodel.Offering, error) {
+	offering := request.ToOffering()
+	if err := s.resolve(ctx, &offering); err != nil {
+		return nil, err
+	}
+	offering.CreatedAt = time.Now().UTC()
+	offering.UpdatedAt = offering.CreatedAt
+
+	id, err := s.OfferingRepository.CreateOffering(ctx, offering)
+	if apperror.Is(err, apperror.KindConflict) {
+		return nil, duplicate(offering)
+	}
+	if err != nil {
+		return nil, err
+	}
+	offering.ID = id
+	return &offering, nil
+}
+
+func (s *OfferingService) UpdateOffering(ctx context.Context, offeringID string, request offeringmodel.OfferingRequest) (*offeringmodel.Offering, error) {
+	existing, err := s.OfferingRepository.FindOfferingByID(ctx, offeringID)
+	if err != nil {
+		return nil, err
+	}
+
+	offering := request.ToOffering()
+	if err := s.resolve(ctx, &offering); err != nil {
+		return nil, err
+	}
+	offering.ID, offering.CreatedAt = existing.ID, existing.CreatedAt
+	offering.UpdatedAt = time.Now().UTC()
+
+	err = s.OfferingRepository.UpdateOffering(ctx, offering)
+	if apperror.Is(err, apperror.KindConflict) {
+		return nil, duplicate(offering)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &offering, nil
+}
+
+// DeleteOffering deletes an offering together with its sections.
+func (s *OfferingService) DeleteOffering(ctx context.Context, offeringID string) error {
+	objID, err := primitive.ObjectIDFromHex(offeringID)
+	if err != nil {
+		return apperror.InvalidID("offering", err)
+	}
+	if err := s.OfferingRepository.DeleteOffering(ctx, objID); err != nil {
+		return err
+	}
+	return s.SectionRepository.DeleteSectionsByOffering(ctx, objID)
+}
+
+// resolve checks that the subject and term exist and fills in the campus
+// and professors from the subject when the request leaves them out.
+func (s *OfferingService) resolve(ctx context.Context, offering *offeringmodel.Offering) error {
+	subject, err := s.SubjectRepository.FindSubjectbyID(ctx, offering.SubjectID.Hex())
+	if err != nil {
+		return err
+	}
+	if _, err := s.TermRepository.FindTermByID(ctx, offering.TermID.Hex()); err != nil {
+		return err
+	}
+
+	if offering.Campus == "" {
+		offering.Campus = subject.Campus
+	}
+	if len(offering.Professors) == 0 && subject.Professors != nil {
+		offering.Professors = subject.Professors
+	}
+	return nil
+}
+
+func duplicate(offering offeringmodel.Offering) error {
+	return apperror.Conflict("the subject is already offered in this term on campus %q", offering.Campus)
+}
+
+func optionalID(hex string, entity string) (primitive.ObjectID, error) {
+	if hex == "" {
+		return primitive.NilObjectID, nil
+	}
+	id, err := primitive.ObjectIDFromHex(hex)
+	if err != nil {
+		return primitive.NilObjectID, apperror.InvalidID(entity, err)
+	}
+	return id, nil
+}
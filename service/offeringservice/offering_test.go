@@ -0,0 +1,126 @@
+package offeringservice
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/model/offeringmodel"
+	"BackendCoursyclopedia/model/subjectmodel"
+	"BackendCoursyclopedia/model/termmodel"
+	"BackendCoursyclopedia/repository/offeringrepository"
+	"BackendCoursyclopedia/repository/subjectrepository"
+	"BackendCoursyclopedia/repository/termrepository"
+	"context"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type fakeSubjectRepository struct {
+	subjectrepository.ISubjectRepository
+	subjects map[string]subjectmodel.Subject
+}
+
+func (r *fakeSubjectRepository) FindSubjectbyID(_ context.Context, id string) (*subjectmodel.Subject, error) {
+	if s, ok := r.subjects[id]; ok {
+		return &s, nil
+	}
+	return nil, apperror.NotFound("subject not found")
+}
+
+type fakeTermRepository struct {
+	termrepository.ITermRepository
+	terms map[string]termmodel.Term
+}
+
+func (r *fakeTermRepository) FindTermByID(_ context.Context, id string) (*termmodel.Term, error) {
+	if t, ok := r.terms[id]; ok {
+		return &t, nil
+	}
+	return nil, apperror.NotFound("term not found")
+}
+
+type fakeOfferingRepository struct {
+	offeringrepository.IOfferingRepository
+	created []offeringmodel.Offering
+	err     error
+}
+
+func (r *fakeOfferingRepository) CreateOffering(_ context.Context, offering offeringmodel.Offering) (primitive.ObjectID, error) {
+	if r.err != nil {
+		return primitive.NilObjectID, r.err
+	}
+	r.created = append(r.created, offering)
+	return primitive.NewObjectID(), nil
+}
+
+func TestCreateOffering(t *testing.T) {
+	subjectID, termID := primitive.NewObjectID(), primitive.NewObjectID()
+	professor, other := primitive.NewObjectID(), primitive.NewObjectID()
+	subject := subjectmodel.Subject{ID: subjectID, Campus: "Suvarnabhumi", Professors: []primitive.ObjectID{professor}}
+
+	tests := []struct {
+		name           string
+		request        offeringmodel.OfferingRequest
+		repoErr        error
+		wantKind       apperror.Kind
+		wantCampus     string
+		wantProfessors []primitive.ObjectID
+	}{
+		{
+			name:           "campus and professors default to the subject's",
+			request:        offeringmodel.OfferingRequest{SubjectID: subjectID.Hex(), TermID: termID.Hex()},
+			wantCampus:     "Suvarnabhumi",
+			wantProfessors: []primitive.ObjectID{professor},
+		},
+		{
+			name:           "request overrides the subject",
+			request:        offeringmodel.OfferingRequest{SubjectID: subjectID.Hex(), TermID: termID.Hex(), Campus: "Hua Mak", Professors: []string{other.Hex()}},
+			wantCampus:     "Hua Mak",
+			wantProfessors: []primitive.ObjectID{other},
+		},
+		{
+			name:     "unknown subject",
+			request:  offeringmodel.OfferingRequest{SubjectID: primitive.NewObjectID().Hex(), TermID: termID.Hex()},
+			wantKind: apperror.KindNotFound,
+		},
+		{
+			name:     "unknown term",
+			request:  offeringmodel.OfferingRequest{SubjectID: subjectID.Hex(), TermID: primitive.NewObjectID().Hex()},
+			wantKind: apperror.KindNotFound,
+		},
+		{
+			name:     "already offered",
+			request:  offeringmodel.OfferingRequest{SubjectID: subjectID.Hex(), TermID: termID.Hex()},
+			repoErr:  apperror.Conflict("duplicate"),
+			wantKind: apperror.KindConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offeringRepo := &fakeOfferingRepository{err: tt.repoErr}
+			service := &OfferingService{
+				OfferingRepository: offeringRepo,
+				TermRepository:     &fakeTermRepository{terms: map[string]termmodel.Term{termID.Hex(): {ID: termID}}},
+				SubjectRepository:  &fakeSubjectRepository{subjects: map[string]subjectmodel.Subject{subjectID.Hex(): subject}},
+			}
+
+			got, err := service.CreateOffering(context.Background(), tt.request)
+			if tt.wantKind != "" {
+				if !apperror.Is(err, tt.wantKind) {
+					t.Fatalf("CreateOffering() error = %v, want kind %v", err, tt.wantKind)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateOffering() error = %v", err)
+			}
+			if got.Campus != tt.wantCampus || !reflect.DeepEqual(got.Professors, tt.wantProfessors) {
+				t.Errorf("CreateOffering() campus=%q professors=%v, want %q %v", got.Campus, got.Professors, tt.wantCampus, tt.wantProfessors)
+			}
+			if got.Status != offeringmodel.StatusPlanned || got.CreatedAt.IsZero() || len(offeringRepo.created) != 1 {
+				t.Errorf("CreateOffering() = %+v, want a stored planned offering", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package sectionservice
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/model/sectionmodel"
+	"BackendCoursyclopedia/repository/offeringrepository"
+	"BackendCoursyclopedia/repository/sectionrepository"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ISectionService interface {
+	GetSectionsByOffering(ctx context.Context, offeringID string) ([]sectionmodel.Section, error)
+	GetSection(ctx context.Context, sectionID string) (*sectionmodel.Section, error)
+	CreateSection(ctx context.Context, offeringID string, request sectionmodel.SectionRequest) (*sectionmodel.Section, error)
+	UpdateSection(ctx context.Context, sectionID string, request sectionmodel.SectionRequest) (*sectionmodel.Section, error)
+	DeleteSection(ctx context.Context, sectionID string) error
+}
+
+type SectionService struct {
+	SectionRepository  sectionrepository.ISectionRepository
+	OfferingRepository offeringrepository.IOfferingRepository
+}
+
+func NewSectionService(sectionRepo sectionrepository.ISectionRepository, offeringRepo offeringrepository.IOfferingRepository) ISectionService {
+	return &SectionService{
+		SectionRepository:  sectionRepo,
+		OfferingRepository: offeringRepo,
+	}
+}
+
+func (s *SectionService) GetSectionsByOffering(ctx context.Context, offeringID string) ([]sectionmodel.Section, error) {
+	offering, err := s.OfferingRepository.FindOfferingByID(ctx, offeringID)
+	if err != nil {
+		return nil, err
+	}
+	return s.SectionRepository.FindSectionsByOfferings(ctx, []primitive.ObjectID{offering.ID})
+}
+
+func (s *SectionService) GetSection(ctx context.Context, sectionID string) (*sectionmodel.Section, error) {
+	return s.SectionRepository.FindSectionByID(ctx, sectionID)
+}
+
+func (s *SectionService) CreateSection(ctx context.Context, offeringID string, request sectionmodel.SectionRequest) (*sectionmodel.Section, error) {
+	offering, err := s.OfferingRepository.FindOfferingByID(ctx, offeringID)
+	if err != nil {
+		return nil, err
+	}
+
+	section := request.ToSection()
+	if fields := checkSection(section); len(fields) > 0 {
+		return nil, apperror.Validation(fields)
+	}
+	section.OfferingID = offering.ID
+	section.CreatedAt = time.Now().UTC()
+	section.UpdatedAt = section.CreatedAt
+
+	id, err := s.SectionRepository.CreateSection(ctx, section)
+	if apperror.Is(err, apperror.KindConflict) {
+		return nil, duplicate(section)
+	}
+	if err != nil {
+		return nil, err
+	}
+	section.ID = id
+	return &section, nil
+}
+
+func (s *SectionService) UpdateSection(ctx context.Context, sectionID string, request sectionmodel.SectionRequest) (*sectionmodel.Section, error) {
+	existing, err := s.SectionRepository.FindSectionByID(ctx, sectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	section := request.ToSection()
+	if fields := checkSection(section); len(fields) > 0 {
+		return nil, apperror.Validation(fields)
+	}
+	section.ID, section.OfferingID, section.CreatedAt = existing.ID, existing.OfferingID, existing.CreatedAt
+	section.UpdatedAt = time.Now().UTC()
+
+	err = s.SectionRepository.UpdateSection(ctx, section)
+	if apperror.Is(err, apperror.KindConflict) {
+		return nil, duplicate(section)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &section, nil
+}
+
+func (s *SectionService) DeleteSection(ctx context.Context, sectionID string) error {
+	objID, err := primitive.ObjectIDFromHex(sectionID)
+	if err != nil {
+		return apperror.InvalidID("section", err)
+	}
+	return s.SectionRepository.DeleteSection(ctx, objID)
+}
+
+func duplicate(section sectionmodel.Section) error {
+	return apperror.Conflict("section %s already exists for this offering", section.Number)
+}
+
+// checkSection rejects meetings and exams that end before they start and
+// meetings of the same section that overlap each other.
+func checkSection(section sectionmodel.Section) []apperror.FieldError {
+	var fields []apperror.FieldError
+	for i, m := range section.Meetings {
+		if start, end := m.Minutes(); end <= start {
+			fields = append(fields, apperror.FieldError{Field: fmt.Sprintf("meetings[%d].end", i), Rule: "gtfield", Message: "must be after start"})
+			continue
+		}
+		for j := 0; j < i; j++ {
+			if m.Overlaps(section.Meetings[j]) {
+				fields = append(fields, apperror.FieldError{Field: fmt.Sprintf("meetings[%d]", i), Rule: "overlap", Message: fmt.Sprintf("overlaps meetings[%d]", j)})
+				break
+			}
+		}
+	}
+	for i, e := range section.Exams {
+		if !e.End.After(e.Start) {
+			fields = append(fields, apperror.FieldError{Field: fmt.Sprintf("exams[%d].end", i), Rule: "gtfield", Message: "must be after start"})
+		}
+	}
+	return fields
+}
@@ -0,0 +1,65 @@
+package sectionservice
+
+import (
+	"BackendCoursyclopedia/model/sectionmodel"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCheckSection(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2026, 12, 10, hour, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name       string
+		meetings   []sectionmodel.Meeting
+		exams      []sectionmodel.Exam
+		wantFields []string
+	}{
+		{
+			name: "valid",
+			meetings: []sectionmodel.Meeting{
+				{Day: "monday", Start: "09:00", End: "10:30"},
+				{Day: "monday", Start: "10:30", End: "12:00"},
+				{Day: "tuesday", Start: "09:00", End: "10:30"},
+			},
+			exams: []sectionmodel.Exam{{Kind: sectionmodel.ExamFinal, Start: at(9), End: at(12)}},
+		},
+		{
+			name:       "meeting ends before it starts",
+			meetings:   []sectionmodel.Meeting{{Day: "monday", Start: "10:30", End: "09:00"}},
+			wantFields: []string{"meetings[0].end"},
+		},
+		{
+			name:       "empty meeting",
+			meetings:   []sectionmodel.Meeting{{Day: "monday", Start: "09:00", End: "09:00"}},
+			wantFields: []string{"meetings[0].end"},
+		},
+		{
+			name: "overlapping meetings",
+			meetings: []sectionmodel.Meeting{
+				{Day: "monday", Start: "09:00", End: "10:30"},
+				{Day: "tuesday", Start: "09:00", End: "10:30"},
+				{Day: "monday", Start: "10:00", End: "11:00"},
+			},
+			wantFields: []string{"meetings[2]"},
+		},
+		{
+			name:       "exam ends before it starts",
+			exams:      []sectionmodel.Exam{{Kind: sectionmodel.ExamMidterm, Start: at(12), End: at(9)}},
+			wantFields: []string{"exams[0].end"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, fe := range checkSection(sectionmodel.Section{Meetings: tt.meetings, Exams: tt.exams}) {
+				got = append(got, fe.Field)
+			}
+			if !reflect.DeepEqual(got, tt.wantFields) {
+				t.Errorf("checkSection() fields = %v, want %v", got, tt.wantFields)
+			}
+		})
+	}
+}
@@ -0,0 +1,490 @@
+package studyplanservice
+
+import (
+	"BackendCoursyclopedia/model/curriculummodel"
+	"BackendCoursyclopedia/model/degreeauditmodel"
+	"BackendCoursyclopedia/model/studyplanmodel"
+	"BackendCoursyclopedia/model/usermodel"
+	"BackendCoursyclopedia/requisites"
+	"BackendCoursyclopedia/service/degreeauditservice"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxSemesters bounds plans generated without a target number of semesters.
+const maxSemesters = 16
+
+// planner holds everything generation and validation read: the curriculum
+// the student follows, the subject catalogue and what they have passed.
+type planner struct {
+	curriculum curriculummodel.Curriculum
+	catalog    *requisites.Catalog
+	completed  []usermodel.CompletedSubject
+	passed     map[string]bool
+	maxCredits int
+	// position orders subjects by their recommended year and semester.
+	position map[string]int
+}
+
+func newPlanner(curriculum curriculummodel.Curriculum, catalog *requisites.Catalog, completed []usermodel.CompletedSubject, maxCredits int) *planner {
+	p := &planner{
+		curriculum: curriculum,
+		catalog:    catalog,
+		completed:  completed,
+		passed:     degreeauditservice.PassedCodes(catalog, completed),
+		maxCredits: maxCredits,
+		position:   map[string]int{},
+	}
+	for _, g := range curriculum.Groups {
+		for _, cs := range g.Subjects {
+			if sub, ok := catalog.ByID(cs.SubjectID); ok {
+				p.position[sub.SubjectCode] = cs.Year*10 + cs.Semester
+			}
+		}
+	}
+	return p
+}
+
+// generate picks the subjects still needed to satisfy the curriculum and
+// lays them out over at most semesters semesters (maxSemesters if 0), taking
+// the subjects with the longest chain of dependents first.
+func (p *planner) generate(semesters int) ([]studyplanmodel.Semester, []studyplanmodel.Issue) {
+	needed, issues := p.selectSubjects()
+
+	limit := semesters
+	if limit == 0 {
+		limit = maxSemesters
+	}
+	schedule, left := p.schedule(needed, limit)
+
+	if len(left) > 0 {
+		issues = append(issues, p.explainLeftover(needed, left, limit))
+	}
+	plan := p.toSemesters(schedule)
+	return plan, dedupe(append(issues, p.validate(plan)...))
+}
+
+// selectSubjects lists the codes still to take: every remaining core
+// subject, enough electives to meet each group's rule and the credit total,
+// and any prerequisite or co-requisite those need. Subjects that can never
+// be taken are left out and reported.
+func (p *planner) selectSubjects() ([]string, []studyplanmodel.Issue) {
+	audit := degreeauditservice.Evaluate(p.curriculum, p.catalog, p.completed)
+
+	var (
+		needed []string
+		issues []studyplanmodel.Issue
+	)
+	inNeed := map[string]bool{}
+	reported := map[string]bool{}
+	report := func(issue studyplanmodel.Issue) {
+		key := issue.Kind + issue.SubjectCode
+		if !reported[key] {
+			reported[key] = true
+			issues = append(issues, issue)
+		}
+	}
+
+	visiting := map[string]bool{}
+	var add func(code string) bool
+	add = func(code string) bool {
+		if p.passed[code] || inNeed[code] {
+			return true
+		}
+		if visiting[code] {
+			report(studyplanmodel.Issue{Kind: studyplanmodel.IssuePrerequisiteCycle, SubjectCode: code,
+				Message: code + " is part of a prerequisite cycle"})
+			return false
+		}
+		sub, ok := p.catalog.ByCode(code)
+		if !ok {
+			report(studyplanmodel.Issue{Kind: studyplanmodel.IssueUnknownPrerequisite, SubjectCode: code,
+				Message: code + " is required as a prerequisite but is not in the catalogue"})
+			return false
+		}
+		if !degreeauditservice.IsOffered(sub) {
+			report(studyplanmodel.Issue{Kind: studyplanmodel.IssueNotOffered, SubjectCode: code,
+				Message: code + " is needed but not offered"})
+			return false
+		}
+		if sub.Credit > p.maxCredits {
+			report(studyplanmodel.Issue{Kind: studyplanmodel.IssueOverCreditCap, SubjectCode: code,
+				Message: fmt.Sprintf("%s is worth %d credits, more than the %d allowed per semester", code, sub.Credit, p.maxCredits)})
+			return false
+		}
+
+		visiting[code] = true
+		defer delete(visiting, code)
+		for _, pre := range p.catalog.Prerequisites(sub) {
+			if !add(pre) {
+				return false
+			}
+		}
+		inNeed[code] = true
+		needed = append(needed, code)
+		for _, co := range p.catalog.CoRequisites(sub) {
+			add(co)
+		}
+		return true
+	}
+
+	credits := audit.EarnedCredits
+	addCounted := func(ref degreeauditmodel.SubjectRef) bool {
+		if inNeed[ref.SubjectCode] {
+			return true
+		}
+		before := len(needed)
+		if !add(ref.SubjectCode) {
+			return false
+		}
+		for _, code := range needed[before:] {
+			sub, _ := p.catalog.ByCode(code)
+			credits += sub.Credit
+		}
+		return true
+	}
+
+	var spare []degreeauditmodel.SubjectRef
+	for i, req := range audit.Requirements {
+		remaining := append([]degreeauditmodel.SubjectRef(nil), req.Remaining...)
+		p.sortRefs(remaining)
+		if req.Satisfied {
+			spare = append(spare, remaining...)
+			continue
+		}
+
+		switch req.Kind {
+		case curriculummodel.GroupCore:
+			for _, ref := range remaining {
+				addCounted(ref)
+			}
+		case curriculummodel.GroupElective:
+			group := p.curriculum.Groups[i]
+			earned, count := req.EarnedCredits, req.CompletedCount
+			for j, ref := range remaining {
+				if earned >= group.MinCredits && count >= group.MinCount {
+					spare = append(spare, remaining[j:]...)
+					break
+				}
+				if addCounted(ref) {
+					earned += ref.Credit
+					count++
+				}
+			}
+		}
+	}
+
+	// Electives beyond each group's minimum make up any remaining total.
+	p.sortRefs(spare)
+	for _, ref := range spare {
+		if credits >= p.curriculum.TotalCredits {
+			break
+		}
+		addCounted(ref)
+	}
+
+	return needed, issues
+}
+
+// schedule fills semesters greedily. A subject is ready once all its
+// prerequisites were passed or planned in an earlier semester; it is taken
+// together with any co-requisite not yet done.
+func (p *planner) schedule(needed []string, limit int) ([][]string, map[string]bool) {
+	done := map[string]bool{}
+	for code := range p.passed {
+		done[code] = true
+	}
+	left := map[string]bool{}
+	for _, code := range needed {
+		left[code] = true
+	}
+	height, _ := p.heights(needed)
+
+	var semesters [][]string
+	for len(left) > 0 && len(semesters) < limit {
+		var ready []string
+		isReady := map[string]bool{}
+		for _, code := range needed {
+			if left[code] && p.prerequisitesDone(code, done) {
+				ready = append(ready, code)
+				isReady[code] = true
+			}
+		}
+		sort.SliceStable(ready, func(i, j int) bool {
+			a, b := ready[i], ready[j]
+			if height[a] != height[b] {
+				return height[a] > height[b]
+			}
+			return p.less(a, b)
+		})
+
+		var taken []string
+		inSemester := map[string]bool{}
+		credits := 0
+		for _, code := range ready {
+			if inSemester[code] {
+				continue
+			}
+			group := []string{code}
+			ok := true
+			sub, _ := p.catalog.ByCode(code)
+			for _, co := range p.catalog.CoRequisites(sub) {
+				if done[co] || inSemester[co] || co == code {
+					continue
+				}
+				if !isReady[co] {
+					ok = false
+					break
+				}
+				group = append(group, co)
+			}
+			if !ok {
+				continue
+			}
+
+			sum := 0
+			for _, c := range group {
+				s, _ := p.catalog.ByCode(c)
+				sum += s.Credit
+			}
+			if credits+sum > p.maxCredits {
+				continue
+			}
+			credits += sum
+			for _, c := range group {
+				inSemester[c] = true
+				taken = append(taken, c)
+			}
+		}
+
+		if len(taken) == 0 {
+			break
+		}
+		for _, code := range taken {
+			done[code] = true
+			delete(left, code)
+		}
+		semesters = append(semesters, taken)
+	}
+	return semesters, left
+}
+
+// explainLeftover says why the subjects in left did not fit, giving the two
+// lower bounds on the number of semesters: credits over the cap and the
+// longest prerequisite chain.
+func (p *planner) explainLeftover(needed []string, left map[string]bool, limit int) studyplanmodel.Issue {
+	credits := 0
+	for _, code := range needed {
+		sub, _ := p.catalog.ByCode(code)
+		credits += sub.Credit
+	}
+	byCredits := (credits + p.maxCredits - 1) / p.maxCredits
+
+	height, next := p.heights(needed)
+	longest := ""
+	for _, code := range needed {
+		if longest == "" || height[code] > height[longest] {
+			longest = code
+		}
+	}
+	var chain []string
+	for code := longest; code != ""; code = next[code] {
+		chain = append(chain, code)
+	}
+
+	codes := make([]string, 0, len(left))
+	for code := range left {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	return studyplanmodel.Issue{
+		Kind: studyplanmodel.IssueTooFewSemesters,
+		Message: fmt.Sprintf("%s do not fit in %d semesters; %d credits at %d per semester need at least %d semesters and the prerequisite chain %s needs %d",
+			strings.Join(codes, ", "), limit, credits, p.maxCredits, byCredits, strings.Join(chain, " → "), len(chain)),
+	}
+}
+
+// heights gives, for each needed subject, the number of semesters from it
+// to the end of its longest chain of needed dependents, and the next subject
+// on that chain.
+func (p *planner) heights(needed []string) (map[string]int, map[string]string) {
+	inNeed := map[string]bool{}
+	for _, code := range needed {
+		inNeed[code] = true
+	}
+	dependents := map[string][]string{}
+	for _, code := range needed {
+		sub, _ := p.catalog.ByCode(code)
+		for _, pre := range p.catalog.Prerequisites(sub) {
+			if inNeed[pre] {
+				dependents[pre] = append(dependents[pre], code)
+			}
+		}
+	}
+
+	height := map[string]int{}
+	next := map[string]string{}
+	var visit func(code string, depth int) int
+	visit = func(code string, depth int) int {
+		if h, ok := height[code]; ok {
+			return h
+		}
+		if depth > len(needed) {
+			return 0 // cycle; already reported when selecting
+		}
+		best := 0
+		for _, d := range dependents[code] {
+			if h := visit(d, depth+1); h > best {
+				best, next[code] = h, d
+			}
+		}
+		height[code] = best + 1
+		return best + 1
+	}
+	for _, code := range needed {
+		visit(code, 0)
+	}
+	return height, next
+}
+
+// validate checks a plan against the credit cap, prerequisite and
+// co-requisite order, availability and the curriculum requirements.
+func (p *planner) validate(semesters []studyplanmodel.Semester) []studyplanmodel.Issue {
+	var issues []studyplanmodel.Issue
+	done := map[string]bool{}
+	for code := range p.passed {
+		done[code] = true
+	}
+	plannedIn := map[string]int{}
+	completed := append([]usermodel.CompletedSubject(nil), p.completed...)
+
+	for i, semester := range semesters {
+		n := i + 1
+		inSemester := map[string]bool{}
+		for _, ps := range semester.Subjects {
+			if sub, ok := p.catalog.ByID(ps.SubjectID); ok {
+				inSemester[sub.SubjectCode] = true
+			}
+		}
+
+		credits := 0
+		for _, ps := range semester.Subjects {
+			sub, ok := p.catalog.ByID(ps.SubjectID)
+			if !ok {
+				issues = append(issues, studyplanmodel.Issue{Kind: studyplanmodel.IssueUnknownSubject, Semester: n,
+					Message: fmt.Sprintf("subject %s does not exist", ps.SubjectID.Hex())})
+				continue
+			}
+			code := sub.SubjectCode
+			credits += sub.Credit
+
+			if p.passed[code] {
+				issues = append(issues, studyplanmodel.Issue{Kind: studyplanmodel.IssueAlreadyPassed, Semester: n, SubjectCode: code,
+					Message: code + " is already passed"})
+			}
+			if first, dup := plannedIn[code]; dup {
+				issues = append(issues, studyplanmodel.Issue{Kind: studyplanmodel.IssueDuplicate, Semester: n, SubjectCode: code,
+					Message: fmt.Sprintf("%s is already planned in semester %d", code, first)})
+			} else {
+				plannedIn[code] = n
+			}
+			if !degreeauditservice.IsOffered(sub) {
+				issues = append(issues, studyplanmodel.Issue{Kind: studyplanmodel.IssueNotOffered, Semester: n, SubjectCode: code,
+					Message: code + " is not offered"})
+			}
+			for _, pre := range p.catalog.Prerequisites(sub) {
+				if !done[pre] {
+					issues = append(issues, studyplanmodel.Issue{Kind: studyplanmodel.IssuePrerequisiteOrder, Semester: n, SubjectCode: code,
+						Message: fmt.Sprintf("%s needs %s in an earlier semester", code, pre)})
+				}
+			}
+			for _, co := range p.catalog.CoRequisites(sub) {
+				if !done[co] && !inSemester[co] {
+					issues = append(issues, studyplanmodel.Issue{Kind: studyplanmodel.IssueCoRequisiteMissing, Semester: n, SubjectCode: code,
+						Message: fmt.Sprintf("%s must be taken with or after %s", code, co)})
+				}
+			}
+			completed = append(completed, usermodel.CompletedSubject{SubjectID: sub.ID, Grade: "S"})
+		}
+
+		if credits > p.maxCredits {
+			issues = append(issues, studyplanmodel.Issue{Kind: studyplanmodel.IssueOverCreditCap, Semester: n,
+				Message: fmt.Sprintf("semester has %d credits, more than the %d allowed", credits, p.maxCredits)})
+		}
+		for code := range inSemester {
+			done[code] = true
+		}
+	}
+
+	audit := degreeauditservice.Evaluate(p.curriculum, p.catalog, completed)
+	for _, req := range audit.Requirements {
+		if !req.Satisfied {
+			issues = append(issues, studyplanmodel.Issue{Kind: studyplanmodel.IssueRequirementsUnmet,
+				Message: fmt.Sprintf("group %q is not complete at the end of the plan", req.Group)})
+		}
+	}
+	if audit.RemainingCredits > 0 {
+		issues = append(issues, studyplanmodel.Issue{Kind: studyplanmodel.IssueRequirementsUnmet,
+			Message: fmt.Sprintf("the plan ends %d credits short of graduation", audit.RemainingCredits)})
+	}
+	return issues
+}
+
+func (p *planner) toSemesters(schedule [][]string) []studyplanmodel.Semester {
+	semesters := make([]studyplanmodel.Semester, len(schedule))
+	for i, codes := range schedule {
+		sort.SliceStable(codes, func(a, b int) bool { return p.less(codes[a], codes[b]) })
+		semester := studyplanmodel.Semester{Subjects: []studyplanmodel.PlannedSubject{}}
+		for _, code := range codes {
+			sub, _ := p.catalog.ByCode(code)
+			semester.Subjects = append(semester.Subjects, studyplanmodel.PlannedSubject{
+				SubjectID: sub.ID, SubjectCode: sub.SubjectCode, Name: sub.Name, Credit: sub.Credit,
+			})
+			semester.Credits += sub.Credit
+		}
+		semesters[i] = semester
+	}
+	return semesters
+}
+
+func (p *planner) prerequisitesDone(code string, done map[string]bool) bool {
+	sub, _ := p.catalog.ByCode(code)
+	for _, pre := range p.catalog.Prerequisites(sub) {
+		if !done[pre] {
+			return false
+		}
+	}
+	return true
+}
+
+// less orders by recommended year and semester, subjects outside the
+// curriculum last, then by code.
+func (p *planner) less(a, b string) bool {
+	pa, oka := p.position[a]
+	pb, okb := p.position[b]
+	if oka != okb {
+		return oka
+	}
+	if pa != pb {
+		return pa < pb
+	}
+	return a < b
+}
+
+func (p *planner) sortRefs(refs []degreeauditmodel.SubjectRef) {
+	sort.SliceStable(refs, func(i, j int) bool { return p.less(refs[i].SubjectCode, refs[j].SubjectCode) })
+}
+
+func dedupe(issues []studyplanmodel.Issue) []studyplanmodel.Issue {
+	seen := map[studyplanmodel.Issue]bool{}
+	out := []studyplanmodel.Issue{}
+	for _, issue := range issues {
+		if !seen[issue] {
+			seen[issue] = true
+			out = append(out, issue)
+		}
+	}
+	return out
+}
@@ -0,0 +1,348 @@
+package studyplanservice
+
+import (
+	"BackendCoursyclopedia/model/curriculummodel"
+	"BackendCoursyclopedia/model/studyplanmodel"
+	"BackendCoursyclopedia/model/subjectmodel"
+	"BackendCoursyclopedia/model/usermodel"
+	"BackendCoursyclopedia/requisites"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type testSubject struct {
+	code   string
+	credit int
+	pre    []string
+	co     []string
+	status string
+}
+
+// newTestPlanner builds a planner whose curriculum is one core group of
+// core, all recommended for the same semester so ties break by code.
+func newTestPlanner(subjects []testSubject, core []string, passed []string, maxCredits int) *planner {
+	stored := make([]subjectmodel.Subject, len(subjects))
+	for i, s := range subjects {
+		stored[i] = subjectmodel.Subject{
+			ID:            primitive.NewObjectID(),
+			SubjectCode:   s.code,
+			Credit:        s.credit,
+			PreRequisite:  s.pre,
+			CoRequisite:   s.co,
+			SubjectStatus: s.status,
+		}
+	}
+	catalog := requisites.NewCatalog(stored)
+
+	group := curriculummodel.RequirementGroup{Name: "core", Kind: curriculummodel.GroupCore}
+	for _, code := range core {
+		sub, _ := catalog.ByCode(code)
+		group.Subjects = append(group.Subjects, curriculummodel.CurriculumSubject{SubjectID: sub.ID, Year: 1, Semester: 1})
+	}
+	curriculum := curriculummodel.Curriculum{Groups: []curriculummodel.RequirementGroup{group}}
+
+	var completed []usermodel.CompletedSubject
+	for _, code := range passed {
+		sub, _ := catalog.ByCode(code)
+		completed = append(completed, usermodel.CompletedSubject{SubjectID: sub.ID, Term: "1/2023", Grade: "A"})
+	}
+	return newPlanner(curriculum, catalog, completed, maxCredits)
+}
+
+func planCodes(plan []studyplanmodel.Semester) [][]string {
+	codes := make([][]string, len(plan))
+	for i, semester := range plan {
+		codes[i] = []string{}
+		for _, ps := range semester.Subjects {
+			codes[i] = append(codes[i], ps.SubjectCode)
+		}
+	}
+	return codes
+}
+
+func issueKinds(issues []studyplanmodel.Issue) map[string]bool {
+	kinds := map[string]bool{}
+	for _, issue := range issues {
+		kinds[issue.Kind] = true
+	}
+	return kinds
+}
+
+func TestGenerate(t *testing.T) {
+	chain := []testSubject{
+		{code: "CS101", credit: 3},
+		{code: "CS201", credit: 3, pre: []string{"CS101"}},
+		{code: "CS301", credit: 3, pre: []string{"CS201"}},
+	}
+
+	tests := []struct {
+		name       string
+		subjects   []testSubject
+		core       []string
+		passed     []string
+		maxCredits int
+		semesters  int
+		want       [][]string
+		wantIssues []string
+	}{
+		{
+			name:       "prerequisites come in earlier semesters",
+			subjects:   chain,
+			core:       []string{"CS301", "CS201", "CS101"},
+			maxCredits: 18,
+			want:       [][]string{{"CS101"}, {"CS201"}, {"CS301"}},
+		},
+		{
+			name:       "passed prerequisites are not planned again",
+			subjects:   chain,
+			core:       []string{"CS101", "CS201", "CS301"},
+			passed:     []string{"CS101"},
+			maxCredits: 18,
+			want:       [][]string{{"CS201"}, {"CS301"}},
+		},
+		{
+			name:       "prerequisites outside the curriculum are added",
+			subjects:   chain,
+			core:       []string{"CS301"},
+			maxCredits: 18,
+			want:       [][]string{{"CS101"}, {"CS201"}, {"CS301"}},
+		},
+		{
+			name: "the longest chain starts first under the credit cap",
+			subjects: append([]testSubject{
+				{code: "AA100", credit: 3},
+			}, chain...),
+			core:       []string{"AA100", "CS101", "CS201", "CS301"},
+			maxCredits: 3,
+			want:       [][]string{{"CS101"}, {"CS201"}, {"AA100"}, {"CS301"}},
+		},
+		{
+			name: "co-requisites share a semester",
+			subjects: []testSubject{
+				{code: "PH101", credit: 3, co: []string{"PH102"}},
+				{code: "PH102", credit: 1},
+			},
+			core:       []string{"PH101", "PH102"},
+			maxCredits: 4,
+			want:       [][]string{{"PH101", "PH102"}},
+		},
+		{
+			name:       "a plan that needs more semesters than allowed is reported",
+			subjects:   chain,
+			core:       []string{"CS101", "CS201", "CS301"},
+			maxCredits: 18,
+			semesters:  2,
+			want:       [][]string{{"CS101"}, {"CS201"}},
+			wantIssues: []string{studyplanmodel.IssueTooFewSemesters, studyplanmodel.IssueRequirementsUnmet},
+		},
+		{
+			name: "a prerequisite cycle is reported and left out",
+			subjects: []testSubject{
+				{code: "XX101", credit: 3, pre: []string{"XX102"}},
+				{code: "XX102", credit: 3, pre: []string{"XX101"}},
+			},
+			core:       []string{"XX101"},
+			maxCredits: 18,
+			want:       [][]string{},
+			wantIssues: []string{studyplanmodel.IssuePrerequisiteCycle, studyplanmodel.IssueRequirementsUnmet},
+		},
+		{
+			name: "an unknown prerequisite is reported",
+			subjects: []testSubject{
+				{code: "CS401", credit: 3, pre: []string{"CS999"}},
+			},
+			core:       []string{"CS401"},
+			maxCredits: 18,
+			want:       [][]string{},
+			wantIssues: []string{studyplanmodel.IssueUnknownPrerequisite, studyplanmodel.IssueRequirementsUnmet},
+		},
+		{
+			name: "subjects not offered are reported",
+			subjects: []testSubject{
+				{code: "CS101", credit: 3},
+				{code: "CS102", credit: 3, status: subjectmodel.StatusSuspended},
+			},
+			core:       []string{"CS101", "CS102"},
+			maxCredits: 18,
+			want:       [][]string{{"CS101"}},
+			wantIssues: []string{studyplanmodel.IssueNotOffered, studyplanmodel.IssueRequirementsUnmet},
+		},
+		{
+			name: "subjects over the credit cap are reported",
+			subjects: []testSubject{
+				{code: "CS101", credit: 3},
+				{code: "CS499", credit: 12},
+			},
+			core:       []string{"CS101", "CS499"},
+			maxCredits: 9,
+			want:       [][]string{{"CS101"}},
+			wantIssues: []string{studyplanmodel.IssueOverCreditCap, studyplanmodel.IssueRequirementsUnmet},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPlanner(tt.subjects, tt.core, tt.passed, tt.maxCredits)
+			plan, issues := p.generate(tt.semesters)
+
+			if got := planCodes(plan); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("plan = %v, want %v", got, tt.want)
+			}
+			kinds := issueKinds(issues)
+			if len(kinds) != len(tt.wantIssues) {
+				t.Errorf("issues = %+v, want kinds %v", issues, tt.wantIssues)
+			}
+			for _, kind := range tt.wantIssues {
+				if !kinds[kind] {
+					t.Errorf("missing %s issue in %+v", kind, issues)
+				}
+			}
+		})
+	}
+}
+
+func TestExplainLeftoverLowerBounds(t *testing.T) {
+	tests := []struct {
+		name       string
+		subjects   []testSubject
+		maxCredits int
+		limit      int
+		want       []string
+	}{
+		{
+			name: "credits over the cap",
+			subjects: []testSubject{
+				{code: "AA101", credit: 3},
+				{code: "AA102", credit: 3},
+				{code: "AA103", credit: 3},
+				{code: "AA104", credit: 3},
+			},
+			maxCredits: 3,
+			limit:      2,
+			want:       []string{"12 credits at 3 per semester need at least 4 semesters", "AA103, AA104 do not fit"},
+		},
+		{
+			name: "prerequisite chain",
+			subjects: []testSubject{
+				{code: "CS101", credit: 3},
+				{code: "CS201", credit: 3, pre: []string{"CS101"}},
+				{code: "CS301", credit: 3, pre: []string{"CS201"}},
+			},
+			maxCredits: 18,
+			limit:      2,
+			want:       []string{"need at least 1 semesters", "chain CS101 → CS201 → CS301 needs 3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var core []string
+			for _, s := range tt.subjects {
+				core = append(core, s.code)
+			}
+			p := newTestPlanner(tt.subjects, core, nil, tt.maxCredits)
+			_, issues := p.generate(tt.limit)
+
+			var message string
+			for _, issue := range issues {
+				if issue.Kind == studyplanmodel.IssueTooFewSemesters {
+					message = issue.Message
+				}
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(message, want) {
+					t.Errorf("message %q does not contain %q", message, want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	subjects := []testSubject{
+		{code: "CS101", credit: 3},
+		{code: "CS201", credit: 3, pre: []string{"CS101"}},
+		{code: "PH101", credit: 3, co: []string{"PH102"}},
+		{code: "PH102", credit: 1},
+		{code: "CS499", credit: 3, status: subjectmodel.StatusArchived},
+	}
+	core := []string{"CS101", "CS201"}
+
+	tests := []struct {
+		name       string
+		passed     []string
+		maxCredits int
+		plan       [][]string
+		want       []string
+	}{
+		{
+			name: "valid plan",
+			plan: [][]string{{"CS101"}, {"CS201"}},
+		},
+		{
+			name: "prerequisite in the same semester",
+			plan: [][]string{{"CS101", "CS201"}},
+			want: []string{studyplanmodel.IssuePrerequisiteOrder},
+		},
+		{
+			name: "co-requisite missing",
+			plan: [][]string{{"CS101", "PH101"}, {"CS201"}},
+			want: []string{studyplanmodel.IssueCoRequisiteMissing},
+		},
+		{
+			name: "co-requisite in the same semester",
+			plan: [][]string{{"CS101", "PH101", "PH102"}, {"CS201"}},
+		},
+		{
+			name: "planned twice",
+			plan: [][]string{{"CS101"}, {"CS201", "CS101"}},
+			want: []string{studyplanmodel.IssueDuplicate},
+		},
+		{
+			name:   "already passed",
+			passed: []string{"CS101"},
+			plan:   [][]string{{"CS101", "CS201"}},
+			want:   []string{studyplanmodel.IssueAlreadyPassed},
+		},
+		{
+			name:       "over the credit cap",
+			maxCredits: 6,
+			plan:       [][]string{{"CS101", "PH101", "PH102"}, {"CS201"}},
+			want:       []string{studyplanmodel.IssueOverCreditCap},
+		},
+		{
+			name: "not offered",
+			plan: [][]string{{"CS101", "CS499"}, {"CS201"}},
+			want: []string{studyplanmodel.IssueNotOffered},
+		},
+		{
+			name: "requirements unmet",
+			plan: [][]string{{"CS101"}},
+			want: []string{studyplanmodel.IssueRequirementsUnmet},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maxCredits := tt.maxCredits
+			if maxCredits == 0 {
+				maxCredits = 9
+			}
+			p := newTestPlanner(subjects, core, tt.passed, maxCredits)
+			plan := p.toSemesters(tt.plan)
+
+			kinds := issueKinds(p.validate(plan))
+			if len(kinds) != len(tt.want) {
+				t.Errorf("issue kinds = %v, want %v", kinds, tt.want)
+			}
+			for _, kind := range tt.want {
+				if !kinds[kind] {
+					t.Errorf("missing %s issue, got %v", kind, kinds)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package studyplanservice
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/model/studyplanmodel"
+	"BackendCoursyclopedia/model/usermodel"
+	"BackendCoursyclopedia/repository/curriculumrepository"
+	"BackendCoursyclopedia/repository/studyplanrepository"
+	"BackendCoursyclopedia/repository/subjectrepository"
+	userrepo "BackendCoursyclopedia/repository/userrepository"
+	"BackendCoursyclopedia/requisites"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IStudyPlanService interface {
+	Generate(ctx context.Context, userID string, request studyplanmodel.GenerateRequest) (*studyplanmodel.Plan, error)
+	GetPlans(ctx context.Context, userID string) ([]studyplanmodel.Plan, error)
+	GetPlan(ctx context.Context, userID string, planID string) (*studyplanmodel.Plan, error)
+	SavePlan(ctx context.Context, userID string, request studyplanmodel.PlanRequest) (*studyplanmodel.Plan, error)
+	UpdatePlan(ctx context.Context, userID string, planID string, request studyplanmodel.PlanRequest) (*studyplanmodel.Plan, error)
+	RevalidatePlan(ctx context.Context, userID string, planID string) (*studyplanmodel.Plan, error)
+	DeletePlan(ctx context.Context, userID string, planID string) error
+}
+
+type StudyPlanService struct {
+	StudyPlanRepository  studyplanrepository.IStudyPlanRepository
+	UserRepository       userrepo.IUserRepository
+	SubjectRepository    subjectrepository.ISubjectRepository
+	CurriculumRepository curriculumrepository.ICurriculumRepository
+}
+
+func NewStudyPlanService(studyPlanRepo studyplanrepository.IStudyPlanRepository, userRepo userrepo.IUserRepository, subjectRepo subjectrepository.ISubjectRepository, curriculumRepo curriculumrepository.ICurriculumRepository) IStudyPlanService {
+	return &StudyPlanService{
+		StudyPlanRepository:  studyPlanRepo,
+		UserRepository:       userRepo,
+		SubjectRepository:    subjectRepo,
+		CurriculumRepository: curriculumRepo,
+	}
+}
+
+// Generate builds a plan without saving it; save it with SavePlan after any
+// edits.
+func (s *StudyPlanService) Generate(ctx context.Context, userID string, request studyplanmodel.GenerateRequest) (*studyplanmodel.Plan, error) {
+	user, err := s.UserRepository.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	p, plan, err := s.load(ctx, user, request.MajorID, request.IntakeYear, request.MaxCreditsPerSemester)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.Name = "Generated plan"
+	plan.Semesters, plan.Issues = p.generate(request.Semesters)
+	plan.Feasible = len(plan.Issues) == 0
+	return plan, nil
+}
+
+func (s *StudyPlanService) GetPlans(ctx context.Context, userID string) ([]studyplanmodel.Plan, error) {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, apperror.InvalidID("user", err)
+	}
+	return s.StudyPlanRepository.FindPlansByUser(ctx, id)
+}
+
+func (s *StudyPlanService) GetPlan(ctx context.Context, userID string, planID string) (*studyplanmodel.Plan, error) {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, apperror.InvalidID("user", err)
+	}
+	return s.StudyPlanRepository.FindPlan(ctx, id, planID)
+}
+
+// SavePlan validates and stores a plan. Plans with issues are saved too, so
+// students can keep working on them; Feasible says whether they hold up.
+func (s *StudyPlanService) SavePlan(ctx context.Context, userID string, request studyplanmodel.PlanRequest) (*studyplanmodel.Plan, error) {
+	user, err := s.UserRepository.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	p, plan, err := s.load(ctx, user, request.MajorID, request.IntakeYear, request.MaxCreditsPerSemester)
+	if err != nil {
+		return nil, err
+	}
+	if err := fill(p, plan, request); err != nil {
+		return nil, err
+	}
+	plan.CreatedAt = plan.UpdatedAt
+
+	id, err := s.StudyPlanRepository.CreatePlan(ctx, *plan)
+	if err != nil {
+		return nil, err
+	}
+	plan.ID = id
+	return plan, nil
+}
+
+func (s *StudyPlanService) UpdatePlan(ctx context.Context, userID string, planID string, request studyplanmodel.PlanRequest) (*studyplanmodel.Plan, error) {
+	user, err := s.UserRepository.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.StudyPlanRepository.FindPlan(ctx, user.ID, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	majorID, intakeYear := request.MajorID, request.IntakeYear
+	if majorID == "" {
+		majorID = existing.MajorID.Hex()
+	}
+	if intakeYear == 0 {
+		intakeYear = existing.IntakeYear
+	}
+	p, plan, err := s.load(ctx, user, majorID, intakeYear, request.MaxCreditsPerSemester)
+	if err != nil {
+		return nil, err
+	}
+	if err := fill(p, plan, request); err != nil {
+		return nil, err
+	}
+	plan.ID, plan.CreatedAt = existing.ID, existing.CreatedAt
+
+	if err := s.StudyPlanRepository.ReplacePlan(ctx, *plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// RevalidatePlan checks a saved plan again against the current catalogue,
+// curriculum and completed subjects, refreshing subject names and credits.
+func (s *StudyPlanService) RevalidatePlan(ctx context.Context, userID string, planID string) (*studyplanmodel.Plan, error) {
+	user, err := s.UserRepository.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.StudyPlanRepository.FindPlan(ctx, user.ID, planID)
+	if err != nil {
+		return nil, err
+	}
+	p, plan, err := s.load(ctx, user, existing.MajorID.Hex(), existing.IntakeYear, existing.MaxCreditsPerSemester)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.ID, plan.Name, plan.CreatedAt, plan.UpdatedAt = existing.ID, existing.Name, existing.CreatedAt, existing.UpdatedAt
+	plan.Semesters = make([]studyplanmodel.Semester, len(existing.Semesters))
+	for i, semester := range existing.Semesters {
+		ids := make([]primitive.ObjectID, len(semester.Subjects))
+		for j, ps := range semester.Subjects {
+			ids[j] = ps.SubjectID
+		}
+		plan.Semesters[i] = semesterOf(p.catalog, ids)
+	}
+	plan.Issues = dedupe(p.validate(plan.Semesters))
+	plan.Feasible = len(plan.Issues) == 0
+
+	if err := s.StudyPlanRepository.ReplacePlan(ctx, *plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (s *StudyPlanService) DeletePlan(ctx context.Context, userID string, planID string) error {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return apperror.InvalidID("user", err)
+	}
+	return s.StudyPlanRepository.DeletePlan(ctx, id, planID)
+}
+
+// load resolves the curriculum and catalogue for user and returns a planner
+// with an empty plan carrying the programme details.
+func (s *StudyPlanService) load(ctx context.Context, user *usermodel.User, majorID string, intakeYear, maxCredits int) (*planner, *studyplanmodel.Plan, error) {
+	major := user.MajorID
+	if majorID != "" {
+		var err error
+		if major, err = primitive.ObjectIDFromHex(majorID); err != nil {
+			return nil, nil, apperror.InvalidID("major", err)
+		}
+	}
+	if intakeYear == 0 {
+		intakeYear = user.IntakeYear
+	}
+	if major.IsZero() || intakeYear == 0 {
+		return nil, nil, apperror.InvalidArgument("set a major and intake year before planning")
+	}
+
+	curriculum, err := s.CurriculumRepository.FindApplicableCurriculum(ctx, major, intakeYear)
+	if err != nil {
+		return nil, nil, err
+	}
+	subjects, err := s.SubjectRepository.FindAllSubjects(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now().UTC()
+	plan := &studyplanmodel.Plan{
+		UserID:                user.ID,
+		MajorID:               major,
+		IntakeYear:            intakeYear,
+		CurriculumVersion:     curriculum.Version,
+		MaxCreditsPerSemester: maxCredits,
+		ValidatedAt:           now,
+		UpdatedAt:             now,
+	}
+	return newPlanner(*curriculum, requisites.NewCatalog(subjects), user.CompletedSubjects, maxCredits), plan, nil
+}
+
+func fill(p *planner, plan *studyplanmodel.Plan, request studyplanmodel.PlanRequest) error {
+	plan.Name = request.Name
+	plan.Semesters = make([]studyplanmodel.Semester, len(request.Semesters))
+	for i, semester := range request.Semesters {
+		ids := make([]primitive.ObjectID, len(semester.Subjects))
+		for j, hex := range semester.Subjects {
+			id, err := primitive.ObjectIDFromHex(hex)
+			if err != nil {
+				return apperror.InvalidID("subject", err)
+			}
+			ids[j] = id
+		}
+		plan.Semesters[i] = semesterOf(p.catalog, ids)
+	}
+	plan.Issues = dedupe(p.validate(plan.Semesters))
+	plan.Feasible = len(plan.Issues) == 0
+	return nil
+}
+
+// semesterOf fills in subject details from the catalogue. Unknown subjects
+// keep only their ID and are reported by validate.
+func semesterOf(catalog *requisites.Catalog, ids []primitive.ObjectID) studyplanmodel.Semester {
+	semester := studyplanmodel.Semester{Subjects: []studyplanmodel.PlannedSubject{}}
+	for _, id := range ids {
+		ps := studyplanmodel.PlannedSubject{SubjectID: id}
+		if sub, ok := catalog.ByID(id); ok {
+			ps.SubjectCode, ps.Name, ps.Credit = sub.SubjectCode, sub.Name, sub.Credit
+		}
+		semester.Subjects = append(semester.Subjects, ps)
+		semester.Credits += ps.Credit
+	}
+	return semester
+}
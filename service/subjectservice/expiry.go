@@ -0,0 +1,40 @@
+package subjectservice
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/logger"
+	"BackendCoursyclopedia/model/subjectmodel"
+	"context"
+	"time"
+)
+
+// ArchiveExpiredSubjects archives every AVAILABLE subject whose availability
+// duration has run out and returns how many it archived. Subjects whose
+// status changes meanwhile are skipped.
+func (s *SubjectService) ArchiveExpiredSubjects(ctx context.Context) (int, error) {
+	now := time.Now()
+	subjects, err := s.SubjectRepository.FindExpiredSubjects(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	archived := 0
+	for _, subject := range subjects {
+		if expiresAt, ok := subject.ExpiresAt(); !ok || expiresAt.After(now) {
+			continue
+		}
+		err := s.changeStatus(ctx, subject, subjectmodel.StatusArchived)
+		if apperror.Is(err, apperror.KindConflict) || apperror.Is(err, apperror.KindNotFound) {
+			continue
+		}
+		if err != nil {
+			return archived, err
+		}
+		archived++
+	}
+
+	if archived > 0 {
+		logger.FromContext(ctx).Info("expired subjects archived", "count", archived)
+	}
+	return archived, nil
+}
@@ -0,0 +1,276 @@
+package subjectservice
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/db"
+	"BackendCoursyclopedia/logger"
+	"BackendCoursyclopedia/metrics"
+	"BackendCoursyclopedia/model/majormodel"
+	"BackendCoursyclopedia/model/subjectmodel"
+	"BackendCoursyclopedia/validation"
+	"context"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ImportSubjects validates every row, resolves its major by ID or name and
+// then creates subjects whose code is new and updates those that exist in
+// the columns the file has, moving them to the given major if needed.
+// Nothing is written in dry-run mode, or in all-or-nothing mode when any
+// row is invalid. All-or-nothing applies the rows in one transaction, so a
+// failed write leaves the database as it was and is returned as an error.
+// Otherwise every valid row is attempted and the report records which were
+// applied and why the others failed.
+func (s *SubjectService) ImportSubjects(ctx context.Context, rows []subjectmodel.SubjectImportRow, opts subjectmodel.SubjectImportOptions) (*subjectmodel.SubjectImportReport, error) {
+	majors, err := s.MajorRepository.FindAllMajors(ctx)
+	if err != nil {
+		return nil, err
+	}
+	subjects, err := s.SubjectRepository.FindAllSubjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resolveMajor := majorResolver(majors)
+	existing := map[string]subjectmodel.Subject{}
+	for _, sub := range subjects {
+		existing[sub.SubjectCode] = sub
+	}
+
+	report := &subjectmodel.SubjectImportReport{
+		DryRun:       opts.DryRun,
+		AllOrNothing: opts.AllOrNothing,
+		Rows:         make([]subjectmodel.SubjectImportRowResult, len(rows)),
+	}
+	majorIDs := make([]primitive.ObjectID, len(rows))
+	seen := map[string]int{}
+
+	for i, row := range rows {
+		result := subjectmodel.SubjectImportRowResult{Line: row.Line, SubjectCode: row.SubjectCode}
+		fieldErrors := append([]apperror.FieldError{}, row.ParseErrors...)
+		current, ok := existing[row.SubjectCode]
+
+		if err := validation.Struct(row); err != nil {
+			appErr, isAppErr := apperror.As(err)
+			if !isAppErr {
+				return nil, err
+			}
+			for _, fe := range appErr.Fields {
+				// A cell that failed to parse is also reported as missing.
+				if hasFieldError(row.ParseErrors, fe.Field) {
+					continue
+				}
+				// An update leaves fields the file has no column for alone.
+				if ok && isRequiredRule(fe.Rule) && !hasImportColumn(row, fe.Field) {
+					continue
+				}
+				fieldErrors = append(fieldErrors, fe)
+			}
+		}
+		if ref := importMajorRef(row); ref != "" {
+			majorID, err := resolveMajor(ref)
+			if err != nil {
+				fieldErrors = append(fieldErrors, apperror.FieldError{Field: "major", Rule: "exists", Message: err.Error()})
+			}
+			majorIDs[i] = majorID
+			result.MajorID = majorID.Hex()
+		}
+		if line, ok := seen[row.SubjectCode]; ok && row.SubjectCode != "" {
+			fieldErrors = append(fieldErrors, apperror.FieldError{
+				Field: "subjectCode", Rule: "unique", Message: fmt.Sprintf("duplicates line %d", line),
+			})
+		}
+		seen[row.SubjectCode] = row.Line
+
+		if status := row.SubjectStatus; status != "" && subjectmodel.IsValidStatus(status) {
+			switch {
+			case ok && !subjectmodel.CanTransition(current.SubjectStatus, status):
+				fieldErrors = append(fieldErrors, transitionError(current.SubjectStatus, status))
+			case !ok && !subjectmodel.IsInitialStatus(status):
+				fieldErrors = append(fieldErrors, initialStatusError())
+			}
+		}
+
+		switch {
+		case len(fieldErrors) > 0:
+			result.Action = subjectmodel.ImportActionInvalid
+			result.Errors = fieldErrors
+			result.MajorID = ""
+			report.Invalid++
+		case ok:
+			result.Action = subjectmodel.ImportActionUpdate
+			result.SubjectID = current.ID.Hex()
+			report.Updated++
+		default:
+			result.Action = subjectmodel.ImportActionCreate
+			report.Created++
+		}
+		report.Rows[i] = result
+	}
+
+	if opts.DryRun || (opts.AllOrNothing && report.Invalid > 0) {
+		return report, nil
+	}
+
+	if opts.AllOrNothing {
+		err := db.WithTransaction(ctx, func(ctx context.Context) error {
+			for i, row := range rows {
+				if err := s.applyImportRow(ctx, existing, row, majorIDs[i], &report.Rows[i]); err != nil {
+					return fmt.Errorf("line %d: %w", row.Line, err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		for i, row := range rows {
+			result := &report.Rows[i]
+			if err := s.applyImportRow(ctx, existing, row, majorIDs[i], result); err != nil {
+				logger.FromContext(ctx).Warn("import row failed", "line", row.Line, "error", err)
+				result.Error = err.Error()
+				report.Failed++
+			}
+		}
+	}
+	report.Applied = true
+	for _, result := range report.Rows {
+		if result.Applied && result.Action == subjectmodel.ImportActionCreate {
+			metrics.SubjectsCreated.Inc()
+		}
+	}
+
+	logger.FromContext(ctx).Info("subjects imported",
+		"created", report.Created, "updated", report.Updated, "invalid", report.Invalid, "failed", report.Failed)
+	return report, nil
+}
+
+// applyImportRow writes one row according to its planned action and marks
+// the result applied. A transaction may run it more than once, so the
+// result is reset first.
+func (s *SubjectService) applyImportRow(ctx context.Context, existing map[string]subjectmodel.Subject, row subjectmodel.SubjectImportRow, majorID primitive.ObjectID, result *subjectmodel.SubjectImportRowResult) error {
+	result.Applied = false
+	switch result.Action {
+	case subjectmodel.ImportActionCreate:
+		result.SubjectID = ""
+		id, err := s.createSubject(ctx, row.ToSubject(), majorID.Hex())
+		if err != nil {
+			return err
+		}
+		result.SubjectID = id
+	case subjectmodel.ImportActionUpdate:
+		if err := s.applyImportUpdate(ctx, existing[row.SubjectCode], row, majorID); err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+	result.Applied = true
+	return nil
+}
+
+// applyImportUpdate overwrites the fields of an existing subject that the
+// row has a column for, changes its status if the row gives another one,
+// and links it to majorID only. A zero majorID leaves the major alone.
+func (s *SubjectService) applyImportUpdate(ctx context.Context, current subjectmodel.Subject, row subjectmodel.SubjectImportRow, majorID primitive.ObjectID) error {
+	subjectID := current.ID
+	subject := row.ToSubject()
+	updates := bson.M{}
+	set := func(column, field string, value interface{}) {
+		if row.HasColumn(column) {
+			updates[field] = value
+		}
+	}
+	set("name", "name", subject.Name)
+	set("professors", "professors", subject.Professors)
+	set("subjectDescription", "subjectDescription", subject.SubjectDescription)
+	set("campus", "campus", subject.Campus)
+	set("credit", "credit", subject.Credit)
+	set("preRequisite", "pre_requisite", subject.PreRequisite)
+	set("coRequisite", "co_requisite", subject.CoRequisite)
+	set("availableDuration", "available_duration", subject.AvailableDuration)
+	if len(updates) > 0 {
+		if err := s.SubjectRepository.UpdateSubject(ctx, subjectID, updates); err != nil {
+			return err
+		}
+	}
+	if subject.SubjectStatus != "" && subject.SubjectStatus != current.SubjectStatus {
+		if err := s.changeStatus(ctx, current, subject.SubjectStatus); err != nil {
+			return err
+		}
+	}
+	if majorID.IsZero() {
+		return nil
+	}
+
+	currentMajor, err := s.MajorRepository.FindMajorBySubjectId(ctx, subjectID)
+	if apperror.Is(err, apperror.KindNotFound) {
+		return s.MajorRepository.AddSubjectToMajor(ctx, majorID.Hex(), subjectID.Hex())
+	}
+	if err != nil {
+		return err
+	}
+	if currentMajor.ID != majorID {
+		return s.MajorRepository.UpdatemajorforSubject(ctx, subjectID, currentMajor.ID, majorID)
+	}
+	return nil
+}
+
+// hasImportColumn reports whether row has a column for the field named in
+// a validation error. Either major column counts for the major.
+func hasImportColumn(row subjectmodel.SubjectImportRow, field string) bool {
+	if field == "major" || field == "majorId" {
+		return row.HasColumn("major") || row.HasColumn("majorId")
+	}
+	return row.HasColumn(field)
+}
+
+func isRequiredRule(rule string) bool {
+	return rule == "required" || rule == "required_without"
+}
+
+func importMajorRef(row subjectmodel.SubjectImportRow) string {
+	if row.MajorID != "" {
+		return row.MajorID
+	}
+	return row.Major
+}
+
+// majorResolver looks a major up by ID first and then by case-insensitive
+// name, rejecting names shared by several majors.
+func majorResolver(majors []majormodel.Major) func(string) (primitive.ObjectID, error) {
+	byID := map[primitive.ObjectID]bool{}
+	byName := map[string][]primitive.ObjectID{}
+	for _, m := range majors {
+		byID[m.ID] = true
+		name := strings.ToLower(strings.TrimSpace(m.MajorName))
+		byName[name] = append(byName[name], m.ID)
+	}
+
+	return func(ref string) (primitive.ObjectID, error) {
+		if id, err := primitive.ObjectIDFromHex(ref); err == nil && byID[id] {
+			return id, nil
+		}
+		switch ids := byName[strings.ToLower(strings.TrimSpace(ref))]; len(ids) {
+		case 0:
+			return primitive.NilObjectID, fmt.Errorf("no major with ID or name %q", ref)
+		case 1:
+			return ids[0], nil
+		default:
+			return primitive.NilObjectID, fmt.Errorf("%d majors are named %q; use the major ID", len(ids), ref)
+		}
+	}
+}
+
+func hasFieldError(errs []apperror.FieldError, field string) bool {
+	for _, fe := range errs {
+		if fe.Field == field {
+			return true
+		}
+	}
+	return false
+}
@@ -1,11 +1,20 @@
 package subjectservice
 
 import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/logger"
+	"BackendCoursyclopedia/metrics"
 	"BackendCoursyclopedia/model/subjectmodel"
+	"BackendCoursyclopedia/repository/curriculumrepository"
 	"BackendCoursyclopedia/repository/majorrepository"
+	"BackendCoursyclopedia/repository/offeringrepository"
+	"BackendCoursyclopedia/repository/studyplanrepository"
 	"BackendCoursyclopedia/repository/subjectrepository"
+	"BackendCoursyclopedia/service/notificationservice"
 	"context"
-	"log"
+	"fmt"
+	"strings"
+	"time"
 
 	// "go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson"
@@ -18,21 +27,31 @@ type ISubjectService interface {
 	GetAllSubjects(ctx context.Context) ([]subjectmodel.Subject, error)
 	GetSubjectByID(ctx context.Context, subjectID string) (*subjectmodel.Subject, error)
 	CreateSubject(ctx context.Context, subject subjectmodel.Subject, majorId string) (string, error)
-	DeleteSubject(subjectId string) error
+	DeleteSubject(ctx context.Context, subjectId string) error
 	UpdateSubject(ctx context.Context, subjectId string, updates subjectmodel.SubjectUpdateRequest, newMajorId string) error
 	UpdateLikes(ctx context.Context, subjectID string, likes int) error
 	AddLikeByEmail(ctx context.Context, subjectID string, userEmail string) error
+	ImportSubjects(ctx context.Context, rows []subjectmodel.SubjectImportRow, opts subjectmodel.SubjectImportOptions) (*subjectmodel.SubjectImportReport, error)
+	ArchiveExpiredSubjects(ctx context.Context) (int, error)
 }
 
 type SubjectService struct {
-	SubjectRepository subjectrepository.ISubjectRepository
-	MajorRepository   majorrepository.IMajorRepository
+	SubjectRepository    subjectrepository.ISubjectRepository
+	MajorRepository      majorrepository.IMajorRepository
+	OfferingRepository   offeringrepository.IOfferingRepository
+	CurriculumRepository curriculumrepository.ICurriculumRepository
+	StudyPlanRepository  studyplanrepository.IStudyPlanRepository
+	NotificationService  notificationservice.INotificationService
 }
 
-func NewSubjectService(SubjectRepo subjectrepository.ISubjectRepository, MajorRepo majorrepository.IMajorRepository) ISubjectService {
+func NewSubjectService(SubjectRepo subjectrepository.ISubjectRepository, MajorRepo majorrepository.IMajorRepository, offeringRepo offeringrepository.IOfferingRepository, curriculumRepo curriculumrepository.ICurriculumRepository, studyPlanRepo studyplanrepository.IStudyPlanRepository, notificationService notificationservice.INotificationService) ISubjectService {
 	return &SubjectService{
-		SubjectRepository: SubjectRepo,
-		MajorRepository:   MajorRepo,
+		SubjectRepository:    SubjectRepo,
+		MajorRepository:      MajorRepo,
+		OfferingRepository:   offeringRepo,
+		CurriculumRepository: curriculumRepo,
+		StudyPlanRepository:  studyPlanRepo,
+		NotificationService:  notificationService,
 	}
 }
 
@@ -44,14 +63,34 @@ func (s SubjectService) GetSubjectByID(ctx context.Context, subjectID string) (*
 	return s.SubjectRepository.FindSubjectbyID(ctx, subjectID)
 }
 
+// CreateSubject inserts a subject, AVAILABLE unless it is created as a
+// DRAFT, and links it to a major.
 func (s *SubjectService) CreateSubject(ctx context.Context, subject subjectmodel.Subject, majorId string) (string, error) {
-	subjectId, err := s.SubjectRepository.CreateSubject(ctx, subject)
+	subjectIdHex, err := s.createSubject(ctx, subject, majorId)
 	if err != nil {
 		return "", err
 	}
 
+	metrics.SubjectsCreated.Inc()
+	return subjectIdHex, nil
+}
+
+// createSubject stores the subject and links it to its major without
+// counting it, for callers that count only once their writes are committed.
+func (s *SubjectService) createSubject(ctx context.Context, subject subjectmodel.Subject, majorId string) (string, error) {
 	if subject.SubjectStatus == "" {
-		subject.SubjectStatus = "AVAILABLE"
+		subject.SubjectStatus = subjectmodel.StatusAvailable
+	}
+	if !subjectmodel.IsInitialStatus(subject.SubjectStatus) {
+		return "", apperror.Validation([]apperror.FieldError{initialStatusError()})
+	}
+	if subject.SubjectStatus == subjectmodel.StatusAvailable {
+		subject.AvailableSince = primitive.NewDateTimeFromTime(time.Now())
+	}
+
+	subjectId, err := s.SubjectRepository.CreateSubject(ctx, subject)
+	if err != nil {
+		return "", err
 	}
 
 	subjectIdHex := subjectId.Hex()
@@ -64,11 +103,17 @@ func (s *SubjectService) CreateSubject(ctx context.Context, subject subjectmodel
 	return subjectIdHex, nil
 }
 
-func (s *SubjectService) DeleteSubject(subjectId string) error {
-	ctx := context.Background()
-
+// DeleteSubject deletes a subject and unlinks it from its majors. It is
+// refused while offerings, curricula or study plans refer to the subject;
+// sections belong to offerings, so they go with them. Archive a subject
+// that is still referenced instead.
+func (s *SubjectService) DeleteSubject(ctx context.Context, subjectId string) error {
 	objId, err := primitive.ObjectIDFromHex(subjectId)
 	if err != nil {
+		return apperror.InvalidID("subject", err)
+	}
+
+	if err := s.checkUnreferenced(ctx, objId); err != nil {
 		return err
 	}
 
@@ -80,11 +125,51 @@ func (s *SubjectService) DeleteSubject(subjectId string) error {
 	return s.MajorRepository.RemoveSubjectFromMajors(ctx, objId)
 }
 
+func (s *SubjectService) checkUnreferenced(ctx context.Context, subjectID primitive.ObjectID) error {
+	offerings, err := s.OfferingRepository.CountOfferingsBySubject(ctx, subjectID)
+	if err != nil {
+		return err
+	}
+	curricula, err := s.CurriculumRepository.CountCurriculaBySubject(ctx, subjectID)
+	if err != nil {
+		return err
+	}
+	plans, err := s.StudyPlanRepository.CountPlansBySubject(ctx, subjectID)
+	if err != nil {
+		return err
+	}
+
+	var refs []string
+	for _, ref := range []struct {
+		count int64
+		name  string
+	}{{offerings, "offerings"}, {curricula, "curricula"}, {plans, "study plans"}} {
+		if ref.count > 0 {
+			refs = append(refs, fmt.Sprintf("%d %s", ref.count, ref.name))
+		}
+	}
+	if len(refs) > 0 {
+		return apperror.Conflict("subject is used by %s; remove them or archive the subject instead", strings.Join(refs, ", "))
+	}
+	return nil
+}
+
 func (s *SubjectService) UpdateSubject(ctx context.Context, subjectId string, updates subjectmodel.SubjectUpdateRequest, newMajorId string) error {
 	subjectObjId, err := primitive.ObjectIDFromHex(subjectId)
 	if err != nil {
 
-		return err
+		return apperror.InvalidID("subject", err)
+	}
+
+	// Check the status change first so an invalid one changes nothing.
+	var current *subjectmodel.Subject
+	if updates.SubjectStatus != "" {
+		if current, err = s.SubjectRepository.FindSubjectbyID(ctx, subjectId); err != nil {
+			return err
+		}
+		if !subjectmodel.CanTransition(current.SubjectStatus, updates.SubjectStatus) {
+			return apperror.Validation([]apperror.FieldError{transitionError(current.SubjectStatus, updates.SubjectStatus)})
+		}
 	}
 
 	updateFields := bson.M{}
@@ -113,9 +198,6 @@ func (s *SubjectService) UpdateSubject(ctx context.Context, subjectId string, up
 	if updates.CoRequisite != nil {
 		updateFields["co_requisite"] = updates.CoRequisite
 	}
-	if updates.SubjectStatus != "" {
-		updateFields["subjectStatus"] = updates.SubjectStatus
-	}
 	if updates.AvailableDuration != nil {
 		updateFields["available_duration"] = *updates.AvailableDuration
 	}
@@ -124,7 +206,7 @@ func (s *SubjectService) UpdateSubject(ctx context.Context, subjectId string, up
 		newmajObjId, err := primitive.ObjectIDFromHex(newMajorId)
 		if err != nil {
 
-			return err
+			return apperror.InvalidID("major", err)
 		}
 
 		currentmajor, err := s.MajorRepository.FindMajorBySubjectId(ctx, subjectObjId)
@@ -147,19 +229,49 @@ func (s *SubjectService) UpdateSubject(ctx context.Context, subjectId string, up
 			return err
 		}
 	}
+	if current != nil && current.SubjectStatus != updates.SubjectStatus {
+		return s.changeStatus(ctx, *current, updates.SubjectStatus)
+	}
 	return nil
 }
 
+// changeStatus moves subject to status, which the caller has checked is an
+// allowed transition, and tells the users who wishlisted it. The change
+// stands even if the notifications cannot be queued.
+func (s *SubjectService) changeStatus(ctx context.Context, subject subjectmodel.Subject, status string) error {
+	if err := s.SubjectRepository.UpdateSubjectStatus(ctx, subject.ID, subject.SubjectStatus, status, time.Now()); err != nil {
+		return err
+	}
+	logger.FromContext(ctx).Info("subject status changed",
+		"subject_id", subject.ID.Hex(), "from", subject.SubjectStatus, "to", status)
+
+	if err := s.NotificationService.NotifySubjectStatusChanged(ctx, subject, subject.SubjectStatus, status); err != nil {
+		logger.FromContext(ctx).Error("failed to queue subject status notifications", "subject_id", subject.ID.Hex(), "error", err)
+	}
+	return nil
+}
+
+func transitionError(from, to string) apperror.FieldError {
+	if from == "" {
+		from = subjectmodel.StatusAvailable
+	}
+	return apperror.FieldError{Field: "subjectStatus", Rule: "transition", Message: fmt.Sprintf("cannot change from %s to %s", from, to)}
+}
+
+func initialStatusError() apperror.FieldError {
+	return apperror.FieldError{Field: "subjectStatus", Rule: "transition", Message: fmt.Sprintf("new subjects must be %s or %s", subjectmodel.StatusDraft, subjectmodel.StatusAvailable)}
+}
+
 func (s *SubjectService) UpdateLikes(ctx context.Context, subjectID string, likes int) error {
 	id, err := primitive.ObjectIDFromHex(subjectID)
 	if err != nil {
-		log.Printf("Invalid ID format: %v", err)
-		return err
+		logger.FromContext(ctx).Warn("invalid subject ID", "subject_id", subjectID, "error", err)
+		return apperror.InvalidID("subject", err)
 	}
 
 	err = s.SubjectRepository.UpdateLikes(ctx, id, likes)
 	if err != nil {
-		log.Printf("Error updating subject likes: %v", err)
+		logger.FromContext(ctx).Error("failed to update subject likes", "subject_id", subjectID, "error", err)
 		return err
 	}
 
@@ -169,15 +281,16 @@ func (s *SubjectService) UpdateLikes(ctx context.Context, subjectID string, like
 func (s *SubjectService) AddLikeByEmail(ctx context.Context, subjectID string, userEmail string) error {
 	id, err := primitive.ObjectIDFromHex(subjectID)
 	if err != nil {
-		log.Printf("Invalid ID format: %v", err)
-		return err
+		logger.FromContext(ctx).Warn("invalid subject ID", "subject_id", subjectID, "error", err)
+		return apperror.InvalidID("subject", err)
 	}
 
 	err = s.SubjectRepository.AddEmailToLikeList(ctx, id, userEmail)
 	if err != nil {
-		log.Printf("Error updating subject likelist: %v", err)
+		logger.FromContext(ctx).Error("failed to update subject likelist", "subject_id", subjectID, "error", err)
 		return err
 	}
+	metrics.LikesAdded.Inc()
 
 	return nil
 }
@@ -0,0 +1,110 @@
+package termservice
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/model/termmodel"
+	"BackendCoursyclopedia/repository/offeringrepository"
+	"BackendCoursyclopedia/repository/termrepository"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ITermService interface {
+	GetTerms(ctx context.Context) ([]termmodel.Term, error)
+	GetTerm(ctx context.Context, termID string) (*termmodel.Term, error)
+	CreateTerm(ctx context.Context, request termmodel.TermRequest) (*termmodel.Term, error)
+	UpdateTerm(ctx context.Context, termID string, request termmodel.TermRequest) (*termmodel.Term, error)
+	DeleteTerm(ctx context.Context, termID string) error
+}
+
+type TermService struct {
+	TermRepository     termrepository.ITermRepository
+	OfferingRepository offeringrepository.IOfferingRepository
+}
+
+func NewTermService(termRepo termrepository.ITermRepository, offeringRepo offeringrepository.IOfferingRepository) ITermService {
+	return &TermService{
+		TermRepository:     termRepo,
+		OfferingRepository: offeringRepo,
+	}
+}
+
+func (s *TermService) GetTerms(ctx context.Context) ([]termmodel.Term, error) {
+	return s.TermRepository.FindTerms(ctx)
+}
+
+func (s *TermService) GetTerm(ctx context.Context, termID string) (*termmodel.Term, error) {
+	return s.TermRepository.FindTermByID(ctx, termID)
+}
+
+func (s *TermService) CreateTerm(ctx context.Context, request termmodel.TermRequest) (*termmodel.Term, error) {
+	term := request.ToTerm()
+	if err := checkDates(term); err != nil {
+		return nil, err
+	}
+
+	id, err := s.TermRepository.CreateTerm(ctx, term)
+	if apperror.Is(err, apperror.KindConflict) {
+		return nil, duplicate(term)
+	}
+	if err != nil {
+		return nil, err
+	}
+	term.ID = id
+	return &term, nil
+}
+
+func (s *TermService) UpdateTerm(ctx context.Context, termID string, request termmodel.TermRequest) (*termmodel.Term, error) {
+	existing, err := s.TermRepository.FindTermByID(ctx, termID)
+	if err != nil {
+		return nil, err
+	}
+
+	term := request.ToTerm()
+	term.ID = existing.ID
+	if err := checkDates(term); err != nil {
+		return nil, err
+	}
+
+	err = s.TermRepository.UpdateTerm(ctx, term)
+	if apperror.Is(err, apperror.KindConflict) {
+		return nil, duplicate(term)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &term, nil
+}
+
+// DeleteTerm refuses to delete a term that still has offerings, so they are
+// never left pointing at a missing term.
+func (s *TermService) DeleteTerm(ctx context.Context, termID string) error {
+	objID, err := primitive.ObjectIDFromHex(termID)
+	if err != nil {
+		return apperror.InvalidID("term", err)
+	}
+
+	count, err := s.OfferingRepository.CountOfferingsByTerm(ctx, objID)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return apperror.Conflict("term has %d offerings; delete them first", count)
+	}
+
+	return s.TermRepository.DeleteTerm(ctx, objID)
+}
+
+func duplicate(term termmodel.Term) error {
+	return apperror.Conflict("semester %d of %d already exists", term.Semester, term.AcademicYear)
+}
+
+func checkDates(term termmodel.Term) error {
+	if !term.EndDate.After(term.StartDate) {
+		return apperror.Validation([]apperror.FieldError{
+			{Field: "endDate", Rule: "gtfield", Message: "must be after startDate"},
+		})
+	}
+	return nil
+}
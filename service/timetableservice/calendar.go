@@ -0,0 +1,184 @@
+package timetableservice
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/ical"
+	"BackendCoursyclopedia/logger"
+	"BackendCoursyclopedia/model/termmodel"
+	"BackendCoursyclopedia/model/usermodel"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// defaultTimezone is used for class times when TIMEZONE is not set.
+const defaultTimezone = "Asia/Bangkok"
+
+// Location returns the time zone that meeting times are given in, read from
+// TIMEZONE.
+func Location() *time.Location {
+	name := os.Getenv("TIMEZONE")
+	if name == "" {
+		name = defaultTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		logger.L().Error("invalid TIMEZONE, using UTC", "timezone", name, "error", err)
+		return time.UTC
+	}
+	return loc
+}
+
+// Calendar builds the iCalendar feed of the user's chosen sections.
+func (s *TimetableService) Calendar(ctx context.Context, userID string) (*ical.Calendar, error) {
+	user, err := s.UserRepository.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.calendar(ctx, user)
+}
+
+// CalendarByToken builds the feed for the user owning a subscription token.
+// The feed of a pending, suspended or deleted user is not found, like a
+// revoked token.
+func (s *TimetableService) CalendarByToken(ctx context.Context, token string) (*ical.Calendar, error) {
+	user, err := s.UserRepository.FindUserByCalendarTokenHash(ctx, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive() {
+		return nil, apperror.NotFound("calendar not found")
+	}
+	return s.calendar(ctx, user)
+}
+
+// CreateCalendarToken issues a new subscription token for the user, which
+// revokes the previous one. Only its hash is stored, so the token cannot be
+// shown again.
+func (s *TimetableService) CreateCalendarToken(ctx context.Context, userID string) (string, error) {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return "", apperror.InvalidID("user", err)
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	if err := s.UserRepository.SetCalendarTokenHash(ctx, id, hashToken(token)); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *TimetableService) RevokeCalendarToken(ctx context.Context, userID string) error {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return apperror.InvalidID("user", err)
+	}
+	return s.UserRepository.SetCalendarTokenHash(ctx, id, "")
+}
+
+func (s *TimetableService) calendar(ctx context.Context, user *usermodel.User) (*ical.Calendar, error) {
+	catalog, err := s.catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.entries(ctx, catalog, user.TimetableSections, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var termIDs []primitive.ObjectID
+	for _, e := range entries {
+		termIDs = append(termIDs, e.Offering.TermID)
+	}
+	terms, err := s.TermRepository.FindTermsByIDs(ctx, termIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := map[primitive.ObjectID]termmodel.Term{}
+	for _, term := range terms {
+		byID[term.ID] = term
+	}
+
+	loc := Location()
+	calendar := &ical.Calendar{
+		Name:     "Coursyclopedia timetable",
+		Location: loc,
+		Stamp:    time.Now(),
+		Events:   []ical.Event{},
+	}
+	for _, e := range entries {
+		term, ok := byID[e.Offering.TermID]
+		if !ok {
+			continue
+		}
+		calendar.Events = append(calendar.Events, events(e, term, loc)...)
+	}
+	return calendar, nil
+}
+
+// events returns a weekly event for every meeting of e within the term and
+// one event for every exam.
+func events(e Entry, term termmodel.Term, loc *time.Location) []ical.Event {
+	title := strings.TrimSpace(e.Subject.SubjectCode + " " + e.Subject.Name)
+	description := fmt.Sprintf("Section %s", e.Section.Number)
+	if e.Offering.Campus != "" {
+		description += ", " + e.Offering.Campus
+	}
+
+	// Term dates are stored as UTC midnight; read them as calendar dates.
+	sy, sm, sd := term.StartDate.UTC().Date()
+	ey, em, ed := term.EndDate.UTC().Date()
+	until := time.Date(ey, em, ed, 23, 59, 59, 0, loc)
+
+	var out []ical.Event
+	for i, m := range e.Section.Meetings {
+		day := time.Date(sy, sm, sd, 0, 0, 0, 0, loc)
+		for day.Weekday() != m.Weekday() {
+			day = day.AddDate(0, 0, 1)
+		}
+		start, end := m.Minutes()
+		y, mo, d := day.Date()
+		first := time.Date(y, mo, d, start/60, start%60, 0, 0, loc)
+		if first.After(until) {
+			continue
+		}
+		out = append(out, ical.Event{
+			UID:         fmt.Sprintf("%s-%d@coursyclopedia", e.Section.ID.Hex(), i),
+			Summary:     title,
+			Description: description,
+			Location:    m.Room,
+			Start:       first,
+			End:         time.Date(y, mo, d, end/60, end%60, 0, 0, loc),
+			Until:       until,
+		})
+	}
+	for i, exam := range e.Section.Exams {
+		out = append(out, ical.Event{
+			UID:         fmt.Sprintf("%s-exam-%d@coursyclopedia", e.Section.ID.Hex(), i),
+			Summary:     fmt.Sprintf("%s exam: %s", strings.ToUpper(exam.Kind[:1])+exam.Kind[1:], title),
+			Description: description,
+			Location:    exam.Room,
+			Start:       exam.Start,
+			End:         exam.End,
+		})
+	}
+	return out
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
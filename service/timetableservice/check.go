@@ -0,0 +1,143 @@
+package timetableservice
+
+import (
+	"BackendCoursyclopedia/model/offeringmodel"
+	"BackendCoursyclopedia/model/sectionmodel"
+	"BackendCoursyclopedia/model/subjectmodel"
+	"BackendCoursyclopedia/model/timetablemodel"
+	"BackendCoursyclopedia/requisites"
+	"fmt"
+	"time"
+)
+
+// Entry is a section together with the offering and subject it belongs to.
+type Entry struct {
+	Section  sectionmodel.Section
+	Offering offeringmodel.Offering
+	Subject  subjectmodel.Subject
+}
+
+// Ref describes e for reports.
+func (e Entry) Ref() timetablemodel.SectionRef {
+	return timetablemodel.SectionRef{
+		SectionID:   e.Section.ID,
+		Number:      e.Section.Number,
+		OfferingID:  e.Offering.ID,
+		TermID:      e.Offering.TermID,
+		SubjectID:   e.Subject.ID,
+		SubjectCode: e.Subject.SubjectCode,
+		Campus:      e.Offering.Campus,
+	}
+}
+
+func (e Entry) label() string {
+	return fmt.Sprintf("%s section %s", e.Subject.SubjectCode, e.Section.Number)
+}
+
+// Clashes lists the conflicts between two sections. Sections of different
+// terms never clash.
+func Clashes(a, b Entry) []timetablemodel.Conflict {
+	if a.Offering.TermID != b.Offering.TermID {
+		return nil
+	}
+	refs := []timetablemodel.SectionRef{a.Ref(), b.Ref()}
+
+	var conflicts []timetablemodel.Conflict
+	if a.Subject.ID == b.Subject.ID {
+		conflicts = append(conflicts, timetablemodel.Conflict{
+			Kind:     timetablemodel.ConflictDuplicateSubject,
+			Sections: refs,
+			Message:  fmt.Sprintf("%s and %s are sections of the same subject", a.label(), b.label()),
+		})
+	}
+	for _, ma := range a.Section.Meetings {
+		for _, mb := range b.Section.Meetings {
+			if !ma.Overlaps(mb) {
+				continue
+			}
+			start, end := later(ma.Start, mb.Start), earlier(ma.End, mb.End)
+			conflicts = append(conflicts, timetablemodel.Conflict{
+				Kind:     timetablemodel.ConflictMeetingOverlap,
+				Sections: refs,
+				Day:      ma.Day,
+				Start:    start,
+				End:      end,
+				Message:  fmt.Sprintf("%s and %s meet at the same time on %s %s-%s", a.label(), b.label(), ma.Day, start, end),
+			})
+		}
+	}
+	for _, ea := range a.Section.Exams {
+		for _, eb := range b.Section.Exams {
+			if !ea.Overlaps(eb) {
+				continue
+			}
+			start, end := ea.Start, ea.End
+			if eb.Start.After(start) {
+				start = eb.Start
+			}
+			if eb.End.Before(end) {
+				end = eb.End
+			}
+			conflicts = append(conflicts, timetablemodel.Conflict{
+				Kind:     timetablemodel.ConflictExamClash,
+				Sections: refs,
+				Start:    start.Format(time.RFC3339),
+				End:      end.Format(time.RFC3339),
+				Message:  fmt.Sprintf("the %s exam of %s clashes with the %s exam of %s", ea.Kind, a.label(), eb.Kind, b.label()),
+			})
+		}
+	}
+	return conflicts
+}
+
+// Check reports every clash between entries and every co-requisite that is
+// neither chosen for the same term nor in passed.
+func Check(entries []Entry, catalog *requisites.Catalog, passed map[string]bool) timetablemodel.Report {
+	report := timetablemodel.Report{
+		Sections:            make([]timetablemodel.SectionRef, len(entries)),
+		Conflicts:           []timetablemodel.Conflict{},
+		MissingCorequisites: []timetablemodel.MissingCorequisite{},
+	}
+
+	chosen := map[string]bool{}
+	for i, e := range entries {
+		report.Sections[i] = e.Ref()
+		chosen[e.Offering.TermID.Hex()+"/"+e.Subject.SubjectCode] = true
+		for _, other := range entries[:i] {
+			report.Conflicts = append(report.Conflicts, Clashes(other, e)...)
+		}
+	}
+
+	for _, e := range entries {
+		var missing []string
+		for _, code := range catalog.CoRequisites(e.Subject) {
+			if !chosen[e.Offering.TermID.Hex()+"/"+code] && !passed[code] {
+				missing = append(missing, code)
+			}
+		}
+		if len(missing) > 0 {
+			report.MissingCorequisites = append(report.MissingCorequisites, timetablemodel.MissingCorequisite{
+				Section: e.Ref(),
+				Missing: missing,
+			})
+		}
+	}
+
+	report.Valid = len(report.Conflicts) == 0 && len(report.MissingCorequisites) == 0
+	return report
+}
+
+// "15:04" clock strings compare correctly as strings.
+func later(a, b string) string {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func earlier(a, b string) string {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,202 @@
+package timetableservice
+
+import (
+	"BackendCoursyclopedia/model/offeringmodel"
+	"BackendCoursyclopedia/model/sectionmodel"
+	"BackendCoursyclopedia/model/subjectmodel"
+	"BackendCoursyclopedia/model/timetablemodel"
+	"BackendCoursyclopedia/requisites"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	term1 = primitive.NewObjectID()
+	term2 = primitive.NewObjectID()
+)
+
+// testSubjects gives each subject code one ID and its co-requisites, so
+// sections of the same code belong to the same subject.
+type testSubjects map[string]subjectmodel.Subject
+
+func (ts testSubjects) get(code string, co ...string) subjectmodel.Subject {
+	sub, ok := ts[code]
+	if !ok {
+		sub = subjectmodel.Subject{ID: primitive.NewObjectID(), SubjectCode: code}
+	}
+	if len(co) > 0 {
+		sub.CoRequisite = co
+	}
+	ts[code] = sub
+	return sub
+}
+
+func (ts testSubjects) catalog() *requisites.Catalog {
+	subjects := make([]subjectmodel.Subject, 0, len(ts))
+	for _, sub := range ts {
+		subjects = append(subjects, sub)
+	}
+	return requisites.NewCatalog(subjects)
+}
+
+// meeting parses "monday 09:00-10:30".
+func meeting(s string) sectionmodel.Meeting {
+	day, span, _ := strings.Cut(s, " ")
+	start, end, _ := strings.Cut(span, "-")
+	return sectionmodel.Meeting{Day: day, Start: start, End: end}
+}
+
+func entry(subject subjectmodel.Subject, number string, term primitive.ObjectID, campus string, meetings ...string) Entry {
+	section := sectionmodel.Section{ID: primitive.NewObjectID(), Number: number}
+	for _, m := range meetings {
+		section.Meetings = append(section.Meetings, meeting(m))
+	}
+	return Entry{
+		Section:  section,
+		Offering: offeringmodel.Offering{ID: primitive.NewObjectID(), TermID: term, SubjectID: subject.ID, Campus: campus},
+		Subject:  subject,
+	}
+}
+
+func withExam(e Entry, kind, start, end string) Entry {
+	s, _ := time.Parse(time.RFC3339, start)
+	f, _ := time.Parse(time.RFC3339, end)
+	e.Section.Exams = append(e.Section.Exams, sectionmodel.Exam{Kind: kind, Start: s, End: f})
+	return e
+}
+
+func TestClashes(t *testing.T) {
+	subjects := testSubjects{}
+	math, physics := subjects.get("MA101"), subjects.get("PH101")
+	const finalStart, finalEnd = "2026-12-10T09:00:00+07:00", "2026-12-10T12:00:00+07:00"
+
+	tests := []struct {
+		name string
+		a, b Entry
+		want []timetablemodel.Conflict
+	}{
+		{
+			name: "different days",
+			a:    entry(math, "1", term1, "", "monday 09:00-10:30"),
+			b:    entry(physics, "1", term1, "", "tuesday 09:00-10:30"),
+		},
+		{
+			name: "overlapping meetings",
+			a:    entry(math, "1", term1, "", "monday 09:00-10:30"),
+			b:    entry(physics, "1", term1, "", "monday 10:00-12:00"),
+			want: []timetablemodel.Conflict{{Kind: timetablemodel.ConflictMeetingOverlap, Day: "monday", Start: "10:00", End: "10:30"}},
+		},
+		{
+			name: "one meeting ends as the other starts",
+			a:    entry(math, "1", term1, "", "monday 09:00-10:30"),
+			b:    entry(physics, "1", term1, "", "monday 10:30-12:00"),
+		},
+		{
+			name: "different terms never clash",
+			a:    entry(math, "1", term1, "", "monday 09:00-10:30"),
+			b:    entry(math, "2", term2, "", "monday 09:00-10:30"),
+		},
+		{
+			name: "two sections of one subject",
+			a:    entry(math, "1", term1, "", "monday 09:00-10:30"),
+			b:    entry(math, "2", term1, "", "friday 09:00-10:30"),
+			want: []timetablemodel.Conflict{{Kind: timetablemodel.ConflictDuplicateSubject}},
+		},
+		{
+			name: "exams at the same time",
+			a:    withExam(entry(math, "1", term1, "", "monday 09:00-10:30"), "final", finalStart, finalEnd),
+			b:    withExam(entry(physics, "1", term1, "", "tuesday 09:00-10:30"), "final", "2026-12-10T11:00:00+07:00", "2026-12-10T14:00:00+07:00"),
+			want: []timetablemodel.Conflict{{Kind: timetablemodel.ConflictExamClash, Start: "2026-12-10T11:00:00+07:00", End: finalEnd}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Clashes(tt.a, tt.b)
+			// Compare what locates a clash; sections and messages are
+			// derived from the entries.
+			for i := range got {
+				got[i].Sections, got[i].Message = nil, ""
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Clashes() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	subjects := testSubjects{}
+	chem := subjects.get("CH101", "CH102")
+	lab := subjects.get("CH102")
+	math := subjects.get("MA101")
+	catalog := subjects.catalog()
+
+	tests := []struct {
+		name        string
+		entries     []Entry
+		passed      map[string]bool
+		wantValid   bool
+		wantClashes int
+		wantMissing []string
+	}{
+		{
+			name:      "no clashes",
+			entries:   []Entry{entry(math, "1", term1, "", "monday 09:00-10:30"), entry(lab, "1", term1, "", "monday 13:00-16:00")},
+			wantValid: true,
+		},
+		{
+			name:        "every pair is checked",
+			entries:     []Entry{entry(math, "1", term1, "", "monday 09:00-12:00"), entry(lab, "1", term1, "", "monday 10:00-11:00"), entry(math, "2", term1, "", "monday 11:00-12:00")},
+			wantClashes: 3,
+		},
+		{
+			name:        "co-requisite not chosen",
+			entries:     []Entry{entry(chem, "1", term1, "", "monday 09:00-10:30")},
+			wantMissing: []string{"CH102"},
+		},
+		{
+			name:      "co-requisite chosen for the same term",
+			entries:   []Entry{entry(chem, "1", term1, "", "monday 09:00-10:30"), entry(lab, "1", term1, "", "tuesday 13:00-16:00")},
+			wantValid: true,
+		},
+		{
+			name:        "co-requisite chosen for another term",
+			entries:     []Entry{entry(chem, "1", term1, "", "monday 09:00-10:30"), entry(lab, "1", term2, "", "tuesday 13:00-16:00")},
+			wantMissing: []string{"CH102"},
+		},
+		{
+			name:      "co-requisite already passed",
+			entries:   []Entry{entry(chem, "1", term1, "", "monday 09:00-10:30")},
+			passed:    map[string]bool{"CH102": true},
+			wantValid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Check(tt.entries, catalog, tt.passed)
+
+			if report.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v", report.Valid, tt.wantValid)
+			}
+			if len(report.Sections) != len(tt.entries) {
+				t.Errorf("got %d sections, want %d", len(report.Sections), len(tt.entries))
+			}
+			if len(report.Conflicts) != tt.wantClashes {
+				t.Errorf("got %d conflicts, want %d: %+v", len(report.Conflicts), tt.wantClashes, report.Conflicts)
+			}
+			var missing []string
+			for _, m := range report.MissingCorequisites {
+				missing = append(missing, m.Missing...)
+			}
+			if !reflect.DeepEqual(missing, tt.wantMissing) {
+				t.Errorf("missing co-requisites = %v, want %v", missing, tt.wantMissing)
+			}
+		})
+	}
+}
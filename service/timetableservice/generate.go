@@ -0,0 +1,251 @@
+package timetableservice
+
+import (
+	"BackendCoursyclopedia/model/sectionmodel"
+	"BackendCoursyclopedia/model/timetablemodel"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultLimit     = 5
+	defaultTimeLimit = 2 * time.Second
+	maxTimeLimit     = 10 * time.Second
+	// maxExplored caps the number of partial timetables visited, so a search
+	// stays bounded even on a fast machine with a generous time limit.
+	maxExplored = 200000
+
+	// Penalties subtracted from a timetable's score.
+	earlyPenalty   = 10 // per meeting before NoClassesBefore
+	freeDayPenalty = 20 // per preferred free day with classes
+	campusPenalty  = 15 // per section away from the preferred campus
+	gapPenalty     = 1  // per full hour between classes on the same day
+)
+
+type candidate struct {
+	timetable timetablemodel.Timetable
+	penalty   int
+}
+
+// search enumerates one section per subject depth-first, keeping the limit
+// timetables with the lowest penalty. Branches whose penalty so far can no
+// longer beat the worst kept timetable are cut.
+type search struct {
+	ctx      context.Context
+	groups   [][]Entry
+	prefs    timetablemodel.Preferences
+	early    int
+	freeDays map[string]bool
+	limit    int
+	deadline time.Time
+
+	chosen    []Entry
+	best      []candidate
+	found     int
+	explored  int
+	stoppedBy string
+}
+
+// Generate returns the best conflict-free timetables taking one section from
+// each group. Groups with fewer sections are tried first to fail fast.
+func Generate(ctx context.Context, groups [][]Entry, prefs timetablemodel.Preferences, limit int, timeLimit time.Duration) timetablemodel.GenerateResult {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if timeLimit <= 0 {
+		timeLimit = defaultTimeLimit
+	}
+	timeLimit = min(timeLimit, maxTimeLimit)
+
+	s := &search{
+		ctx:      ctx,
+		prefs:    prefs,
+		freeDays: map[string]bool{},
+		limit:    limit,
+		deadline: time.Now().Add(timeLimit),
+	}
+	if prefs.NoClassesBefore != "" {
+		s.early = sectionmodel.ClockMinutes(prefs.NoClassesBefore)
+	}
+	for _, d := range prefs.FreeDays {
+		s.freeDays[d] = true
+	}
+
+	s.groups = make([][]Entry, len(groups))
+	for i, g := range groups {
+		s.groups[i] = append([]Entry(nil), g...)
+		sort.SliceStable(s.groups[i], func(a, b int) bool {
+			return s.sectionPenalty(s.groups[i][a]) < s.sectionPenalty(s.groups[i][b])
+		})
+	}
+	sort.SliceStable(s.groups, func(a, b int) bool { return len(s.groups[a]) < len(s.groups[b]) })
+
+	s.walk(0, 0)
+
+	result := timetablemodel.GenerateResult{
+		Timetables: make([]timetablemodel.Timetable, len(s.best)),
+		Found:      s.found,
+		Explored:   s.explored,
+		Complete:   s.stoppedBy == "",
+		StoppedBy:  s.stoppedBy,
+	}
+	for i, c := range s.best {
+		result.Timetables[i] = c.timetable
+	}
+	return result
+}
+
+// walk tries every section of group i. bound is the penalty of the sections
+// chosen so far, leaving out gaps, which a later section can close.
+func (s *search) walk(i, bound int) bool {
+	s.explored++
+	if s.explored%256 == 0 && (s.ctx.Err() != nil || time.Now().After(s.deadline)) {
+		s.stoppedBy = timetablemodel.StopTimeLimit
+		return false
+	}
+	if s.explored >= maxExplored {
+		s.stoppedBy = timetablemodel.StopSearchLimit
+		return false
+	}
+	if len(s.best) == s.limit && bound >= s.best[len(s.best)-1].penalty {
+		return true
+	}
+	if i == len(s.groups) {
+		s.found++
+		s.keep()
+		return true
+	}
+
+	for _, e := range s.groups[i] {
+		if s.clashes(e) {
+			continue
+		}
+		added := s.sectionPenalty(e) + s.newFreeDayPenalty(e)
+		s.chosen = append(s.chosen, e)
+		ok := s.walk(i+1, bound+added)
+		s.chosen = s.chosen[:len(s.chosen)-1]
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *search) clashes(e Entry) bool {
+	for _, c := range s.chosen {
+		for _, conflict := range Clashes(c, e) {
+			if conflict.Kind != timetablemodel.ConflictDuplicateSubject {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *search) keep() {
+	timetable, penalty := s.score(s.chosen)
+	c := candidate{timetable: timetable, penalty: penalty}
+	// Equal penalties keep the order they were found in.
+	i := sort.Search(len(s.best), func(i int) bool {
+		return s.best[i].penalty > penalty
+	})
+	if i == s.limit {
+		return
+	}
+	s.best = append(s.best, candidate{})
+	copy(s.best[i+1:], s.best[i:])
+	s.best[i] = c
+	if len(s.best) > s.limit {
+		s.best = s.best[:s.limit]
+	}
+}
+
+// sectionPenalty is the part of the penalty that depends on e alone.
+func (s *search) sectionPenalty(e Entry) int {
+	penalty := 0
+	if s.prefs.Campus != "" && !strings.EqualFold(e.Offering.Campus, s.prefs.Campus) {
+		penalty += campusPenalty
+	}
+	if s.early > 0 {
+		for _, m := range e.Section.Meetings {
+			if start, _ := m.Minutes(); start < s.early {
+				penalty += earlyPenalty
+			}
+		}
+	}
+	return penalty
+}
+
+// newFreeDayPenalty charges for preferred free days that e is the first
+// chosen section to use.
+func (s *search) newFreeDayPenalty(e Entry) int {
+	penalty := 0
+	counted := map[string]bool{}
+	for _, m := range e.Section.Meetings {
+		if !s.freeDays[m.Day] || counted[m.Day] {
+			continue
+		}
+		counted[m.Day] = true
+		used := false
+		for _, c := range s.chosen {
+			for _, cm := range c.Section.Meetings {
+				used = used || cm.Day == m.Day
+			}
+		}
+		if !used {
+			penalty += freeDayPenalty
+		}
+	}
+	return penalty
+}
+
+// score builds the timetable for entries and its total penalty.
+func (s *search) score(entries []Entry) (timetablemodel.Timetable, int) {
+	timetable := timetablemodel.Timetable{
+		Sections: make([]timetablemodel.ScheduledSection, len(entries)),
+		Unmet:    []string{},
+	}
+	penalty := 0
+	byDay := map[string][]sectionmodel.Meeting{}
+
+	for i, e := range entries {
+		timetable.Sections[i] = timetablemodel.ScheduledSection{SectionRef: e.Ref(), Meetings: e.Section.Meetings}
+		if s.prefs.Campus != "" && !strings.EqualFold(e.Offering.Campus, s.prefs.Campus) {
+			penalty += campusPenalty
+			timetable.Unmet = append(timetable.Unmet, fmt.Sprintf("%s is on campus %q", e.label(), e.Offering.Campus))
+		}
+		for _, m := range e.Section.Meetings {
+			byDay[m.Day] = append(byDay[m.Day], m)
+			if start, _ := m.Minutes(); s.early > 0 && start < s.early {
+				penalty += earlyPenalty
+				timetable.Unmet = append(timetable.Unmet, fmt.Sprintf("%s starts at %s on %s", e.label(), m.Start, m.Day))
+			}
+		}
+	}
+	sort.Slice(timetable.Sections, func(a, b int) bool {
+		return timetable.Sections[a].SubjectCode < timetable.Sections[b].SubjectCode
+	})
+
+	for _, day := range sectionmodel.Days {
+		meetings := byDay[day]
+		if len(meetings) == 0 {
+			continue
+		}
+		if s.freeDays[day] {
+			penalty += freeDayPenalty
+			timetable.Unmet = append(timetable.Unmet, "classes on "+day)
+		}
+		sort.Slice(meetings, func(a, b int) bool { return meetings[a].Start < meetings[b].Start })
+		for i := 1; i < len(meetings); i++ {
+			_, prevEnd := meetings[i-1].Minutes()
+			start, _ := meetings[i].Minutes()
+			penalty += gapPenalty * ((start - prevEnd) / 60)
+		}
+	}
+
+	timetable.Score = -penalty
+	return timetable, penalty
+}
@@ -0,0 +1,164 @@
+package timetableservice
+
+import (
+	"BackendCoursyclopedia/model/timetablemodel"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func sectionLabels(timetable timetablemodel.Timetable) []string {
+	labels := make([]string, len(timetable.Sections))
+	for i, s := range timetable.Sections {
+		labels[i] = s.SubjectCode + "/" + s.Number
+	}
+	return labels
+}
+
+func TestGenerate(t *testing.T) {
+	subjects := testSubjects{}
+	math, physics, english := subjects.get("MA101"), subjects.get("PH101"), subjects.get("EN101")
+
+	mathMonday := entry(math, "1", term1, "Hua Mak", "monday 08:00-09:30")
+	mathTuesday := entry(math, "2", term1, "Suvarnabhumi", "tuesday 10:00-11:30")
+	physicsMonday := entry(physics, "1", term1, "Hua Mak", "monday 08:00-09:30")
+	physicsLate := entry(physics, "2", term1, "Hua Mak", "monday 13:00-14:30")
+	englishTuesday := entry(english, "1", term1, "Suvarnabhumi", "tuesday 13:00-14:30")
+
+	tests := []struct {
+		name      string
+		groups    [][]Entry
+		prefs     timetablemodel.Preferences
+		limit     int
+		want      [][]string
+		wantFound int
+	}{
+		{
+			name:      "clashing sections are never combined",
+			groups:    [][]Entry{{mathMonday}, {physicsMonday, physicsLate}},
+			want:      [][]string{{"MA101/1", "PH101/2"}},
+			wantFound: 1,
+		},
+		{
+			name:      "no conflict-free combination",
+			groups:    [][]Entry{{mathMonday}, {physicsMonday}},
+			want:      [][]string{},
+			wantFound: 0,
+		},
+		{
+			name:   "classes before the preferred start rank lower",
+			groups: [][]Entry{{mathMonday, mathTuesday}},
+			prefs:  timetablemodel.Preferences{NoClassesBefore: "09:00"},
+			want:   [][]string{{"MA101/2"}, {"MA101/1"}},
+		},
+		{
+			name:   "preferred free days rank higher",
+			groups: [][]Entry{{mathMonday, mathTuesday}, {englishTuesday}},
+			prefs:  timetablemodel.Preferences{FreeDays: []string{"monday"}},
+			want:   [][]string{{"EN101/1", "MA101/2"}, {"EN101/1", "MA101/1"}},
+		},
+		{
+			name:   "sections on the preferred campus rank higher",
+			groups: [][]Entry{{mathMonday, mathTuesday}},
+			prefs:  timetablemodel.Preferences{Campus: "suvarnabhumi"},
+			want:   [][]string{{"MA101/2"}, {"MA101/1"}},
+		},
+		{
+			name:   "gaps between classes rank lower",
+			groups: [][]Entry{{mathMonday}, {physicsLate, entry(physics, "3", term1, "", "monday 09:30-11:00")}},
+			want:   [][]string{{"MA101/1", "PH101/3"}, {"MA101/1", "PH101/2"}},
+		},
+		{
+			name:      "only the limit best are kept",
+			groups:    [][]Entry{{mathMonday, mathTuesday}, {physicsMonday, physicsLate}},
+			prefs:     timetablemodel.Preferences{NoClassesBefore: "09:00"},
+			limit:     1,
+			want:      [][]string{{"MA101/2", "PH101/2"}},
+			wantFound: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Generate(context.Background(), tt.groups, tt.prefs, tt.limit, time.Second)
+
+			got := make([][]string, len(result.Timetables))
+			for i, timetable := range result.Timetables {
+				got[i] = sectionLabels(timetable)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("timetables = %v, want %v", got, tt.want)
+			}
+			if tt.wantFound != 0 || len(tt.want) == 0 {
+				if result.Found != tt.wantFound {
+					t.Errorf("Found = %d, want %d", result.Found, tt.wantFound)
+				}
+			}
+			if !result.Complete {
+				t.Errorf("search stopped by %s", result.StoppedBy)
+			}
+			for i := 1; i < len(result.Timetables); i++ {
+				if result.Timetables[i].Score > result.Timetables[i-1].Score {
+					t.Errorf("timetable %d scores %d, more than the one before it", i, result.Timetables[i].Score)
+				}
+			}
+		})
+	}
+}
+
+// TestGenerateBoundKeepsBest checks that cutting branches by the worst kept
+// penalty never loses the best timetable.
+func TestGenerateBoundKeepsBest(t *testing.T) {
+	subjects := testSubjects{}
+	days := []string{"monday", "tuesday", "wednesday", "thursday", "friday"}
+	starts := []string{"08:00", "09:30", "13:00", "15:00"}
+
+	var groups [][]Entry
+	for i, code := range []string{"MA101", "PH101", "EN101", "CH101", "BI101"} {
+		sub := subjects.get(code)
+		var group []Entry
+		for j, day := range days {
+			start := starts[(i+j)%len(starts)]
+			end := map[string]string{"08:00": "09:30", "09:30": "11:00", "13:00": "14:30", "15:00": "16:30"}[start]
+			campus := "Hua Mak"
+			if (i+j)%2 == 0 {
+				campus = "Suvarnabhumi"
+			}
+			group = append(group, entry(sub, string(rune('1'+j)), term1, campus, day+" "+start+"-"+end))
+		}
+		groups = append(groups, group)
+	}
+	prefs := timetablemodel.Preferences{NoClassesBefore: "09:00", FreeDays: []string{"friday"}, Campus: "Suvarnabhumi"}
+
+	tests := []struct {
+		name  string
+		limit int
+	}{
+		{name: "limit 1", limit: 1},
+		{name: "limit 3", limit: 3},
+		{name: "limit 10", limit: 10},
+	}
+
+	all := Generate(context.Background(), groups, prefs, 50, 5*time.Second)
+	if !all.Complete || len(all.Timetables) == 0 {
+		t.Fatalf("exhaustive search: complete=%v timetables=%d", all.Complete, len(all.Timetables))
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Generate(context.Background(), groups, prefs, tt.limit, 5*time.Second)
+			if len(result.Timetables) != tt.limit {
+				t.Fatalf("got %d timetables, want %d", len(result.Timetables), tt.limit)
+			}
+			for i, timetable := range result.Timetables {
+				if timetable.Score != all.Timetables[i].Score {
+					t.Errorf("timetable %d scores %d, want %d", i, timetable.Score, all.Timetables[i].Score)
+				}
+			}
+			if result.Explored > all.Explored {
+				t.Errorf("explored %d partial timetables, more than the %d of the wider search", result.Explored, all.Explored)
+			}
+		})
+	}
+}
@@ -0,0 +1,294 @@
+package timetableservice
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/ical"
+	"BackendCoursyclopedia/model/offeringmodel"
+	"BackendCoursyclopedia/model/timetablemodel"
+	"BackendCoursyclopedia/repository/offeringrepository"
+	"BackendCoursyclopedia/repository/sectionrepository"
+	"BackendCoursyclopedia/repository/subjectrepository"
+	"BackendCoursyclopedia/repository/termrepository"
+	userrepo "BackendCoursyclopedia/repository/userrepository"
+	"BackendCoursyclopedia/requisites"
+	"BackendCoursyclopedia/service/degreeauditservice"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ITimetableService interface {
+	Check(ctx context.Context, userID string, request timetablemodel.CheckRequest) (*timetablemodel.Report, error)
+	Generate(ctx context.Context, userID string, request timetablemodel.GenerateRequest) (*timetablemodel.GenerateResult, error)
+	GetMyTimetable(ctx context.Context, userID string) (*timetablemodel.Report, error)
+	SaveMyTimetable(ctx context.Context, userID string, request timetablemodel.SaveRequest) (*timetablemodel.Report, error)
+	Calendar(ctx context.Context, userID string) (*ical.Calendar, error)
+	CalendarByToken(ctx context.Context, token string) (*ical.Calendar, error)
+	CreateCalendarToken(ctx context.Context, userID string) (string, error)
+	RevokeCalendarToken(ctx context.Context, userID string) error
+}
+
+type TimetableService struct {
+	SectionRepository  sectionrepository.ISectionRepository
+	OfferingRepository offeringrepository.IOfferingRepository
+	TermRepository     termrepository.ITermRepository
+	SubjectRepository  subjectrepository.ISubjectRepository
+	UserRepository     userrepo.IUserRepository
+}
+
+func NewTimetableService(sectionRepo sectionrepository.ISectionRepository, offeringRepo offeringrepository.IOfferingRepository, termRepo termrepository.ITermRepository, subjectRepo subjectrepository.ISubjectRepository, userRepo userrepo.IUserRepository) ITimetableService {
+	return &TimetableService{
+		SectionRepository:  sectionRepo,
+		OfferingRepository: offeringRepo,
+		TermRepository:     termRepo,
+		SubjectRepository:  subjectRepo,
+		UserRepository:     userRepo,
+	}
+}
+
+// Check reports clashes between the chosen sections and missing
+// co-requisites. Subjects the user has already passed count as taken.
+func (s *TimetableService) Check(ctx context.Context, userID string, request timetablemodel.CheckRequest) (*timetablemodel.Report, error) {
+	user, err := s.UserRepository.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, len(request.Sections))
+	for i, hex := range request.Sections {
+		if ids[i], err = primitive.ObjectIDFromHex(hex); err != nil {
+			return nil, apperror.InvalidID("section", err)
+		}
+	}
+
+	catalog, err := s.catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.entries(ctx, catalog, ids, true)
+	if err != nil {
+		return nil, err
+	}
+
+	report := Check(entries, catalog, degreeauditservice.PassedCodes(catalog, user.CompletedSubjects))
+	return &report, nil
+}
+
+// GetMyTimetable checks the user's saved sections. Sections deleted since
+// they were chosen are left out.
+func (s *TimetableService) GetMyTimetable(ctx context.Context, userID string) (*timetablemodel.Report, error) {
+	user, err := s.UserRepository.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	catalog, err := s.catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.entries(ctx, catalog, user.TimetableSections, false)
+	if err != nil {
+		return nil, err
+	}
+
+	report := Check(entries, catalog, degreeauditservice.PassedCodes(catalog, user.CompletedSubjects))
+	return &report, nil
+}
+
+// SaveMyTimetable stores the user's chosen sections and returns their check.
+// Timetables with conflicts are saved too; the report shows what to fix.
+func (s *TimetableService) SaveMyTimetable(ctx context.Context, userID string, request timetablemodel.SaveRequest) (*timetablemodel.Report, error) {
+	user, err := s.UserRepository.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, len(request.Sections))
+	for i, hex := range request.Sections {
+		if ids[i], err = primitive.ObjectIDFromHex(hex); err != nil {
+			return nil, apperror.InvalidID("section", err)
+		}
+	}
+
+	catalog, err := s.catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.entries(ctx, catalog, ids, true)
+	if err != nil {
+		return nil, err
+	}
+	saved := make([]primitive.ObjectID, len(entries))
+	for i, e := range entries {
+		saved[i] = e.Section.ID
+	}
+	if err := s.UserRepository.UpdateTimetableSections(ctx, user.ID, saved); err != nil {
+		return nil, err
+	}
+
+	report := Check(entries, catalog, degreeauditservice.PassedCodes(catalog, user.CompletedSubjects))
+	return &report, nil
+}
+
+// Generate searches the open and planned sections of the requested subjects
+// in a term for the conflict-free timetables that best fit the preferences.
+func (s *TimetableService) Generate(ctx context.Context, userID string, request timetablemodel.GenerateRequest) (*timetablemodel.GenerateResult, error) {
+	user, err := s.UserRepository.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	term, err := s.TermRepository.FindTermByID(ctx, request.TermID)
+	if err != nil {
+		return nil, err
+	}
+	catalog, err := s.catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var subjectIDs []primitive.ObjectID
+	var fields []apperror.FieldError
+	seen := map[primitive.ObjectID]bool{}
+	for i, hex := range request.Subjects {
+		id, _ := primitive.ObjectIDFromHex(hex)
+		if _, ok := catalog.ByID(id); !ok {
+			fields = append(fields, apperror.FieldError{Field: fmt.Sprintf("subjects[%d]", i), Rule: "exists", Message: "subject does not exist"})
+			continue
+		}
+		if !seen[id] {
+			seen[id] = true
+			subjectIDs = append(subjectIDs, id)
+		}
+	}
+	if len(fields) > 0 {
+		return nil, apperror.Validation(fields)
+	}
+
+	offerings, err := s.OfferingRepository.FindOfferings(ctx, offeringmodel.Filter{TermID: term.ID, SubjectIDs: subjectIDs})
+	if err != nil {
+		return nil, err
+	}
+	byID := map[primitive.ObjectID]offeringmodel.Offering{}
+	var offeringIDs []primitive.ObjectID
+	for _, offering := range offerings {
+		if offering.Status == offeringmodel.StatusOpen || offering.Status == offeringmodel.StatusPlanned {
+			byID[offering.ID] = offering
+			offeringIDs = append(offeringIDs, offering.ID)
+		}
+	}
+	sections, err := s.SectionRepository.FindSectionsByOfferings(ctx, offeringIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	bySubject := map[primitive.ObjectID][]Entry{}
+	for _, section := range sections {
+		offering := byID[section.OfferingID]
+		subject, _ := catalog.ByID(offering.SubjectID)
+		bySubject[subject.ID] = append(bySubject[subject.ID], Entry{Section: section, Offering: offering, Subject: subject})
+	}
+	groups := make([][]Entry, len(subjectIDs))
+	for i, id := range subjectIDs {
+		if len(bySubject[id]) == 0 {
+			subject, _ := catalog.ByID(id)
+			fields = append(fields, apperror.FieldError{Field: fmt.Sprintf("subjects[%d]", i), Rule: "offered", Message: fmt.Sprintf("%s has no open sections in %s", subject.SubjectCode, term.Name)})
+		}
+		groups[i] = bySubject[id]
+	}
+	if len(fields) > 0 {
+		return nil, apperror.Validation(fields)
+	}
+
+	result := Generate(ctx, groups, request.Preferences, request.Limit, time.Duration(request.TimeLimitMs)*time.Millisecond)
+	result.MissingCorequisites = missingCorequisites(groups, catalog, degreeauditservice.PassedCodes(catalog, user.CompletedSubjects))
+	return &result, nil
+}
+
+// missingCorequisites lists, per requested subject, the co-requisites that
+// are neither requested nor already passed.
+func missingCorequisites(groups [][]Entry, catalog *requisites.Catalog, passed map[string]bool) []timetablemodel.MissingCorequisite {
+	requested := map[string]bool{}
+	for _, g := range groups {
+		requested[g[0].Subject.SubjectCode] = true
+	}
+
+	missing := []timetablemodel.MissingCorequisite{}
+	for _, g := range groups {
+		subject := g[0].Subject
+		var codes []string
+		for _, code := range catalog.CoRequisites(subject) {
+			if !requested[code] && !passed[code] {
+				codes = append(codes, code)
+			}
+		}
+		if len(codes) > 0 {
+			missing = append(missing, timetablemodel.MissingCorequisite{
+				Section: timetablemodel.SectionRef{SubjectID: subject.ID, SubjectCode: subject.SubjectCode, TermID: g[0].Offering.TermID},
+				Missing: codes,
+			})
+		}
+	}
+	return missing
+}
+
+func (s *TimetableService) catalog(ctx context.Context) (*requisites.Catalog, error) {
+	subjects, err := s.SubjectRepository.FindAllSubjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return requisites.NewCatalog(subjects), nil
+}
+
+// entries loads the given sections with their offerings and subjects, in
+// the order of ids. Duplicate IDs are ignored. Missing sections are an error
+// when strict and skipped otherwise.
+func (s *TimetableService) entries(ctx context.Context, catalog *requisites.Catalog, ids []primitive.ObjectID, strict bool) ([]Entry, error) {
+	if len(ids) == 0 {
+		return []Entry{}, nil
+	}
+
+	sections, err := s.SectionRepository.FindSectionsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	var offeringIDs []primitive.ObjectID
+	for _, section := range sections {
+		offeringIDs = append(offeringIDs, section.OfferingID)
+	}
+	offerings, err := s.OfferingRepository.FindOfferingsByIDs(ctx, offeringIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := map[primitive.ObjectID]*Entry{}
+	for _, section := range sections {
+		entries[section.ID] = &Entry{Section: section}
+	}
+	for _, e := range entries {
+		for _, offering := range offerings {
+			if offering.ID == e.Section.OfferingID {
+				e.Offering = offering
+			}
+		}
+		e.Subject, _ = catalog.ByID(e.Offering.SubjectID)
+	}
+
+	ordered := make([]Entry, 0, len(entries))
+	seen := map[primitive.ObjectID]bool{}
+	for _, id := range ids {
+		e, ok := entries[id]
+		switch {
+		case !ok && strict:
+			return nil, apperror.NotFound("section %s not found", id.Hex())
+		case ok && (e.Offering.ID.IsZero() || e.Subject.ID.IsZero()) && strict:
+			return nil, apperror.NotFound("the offering of section %s no longer exists", id.Hex())
+		case !ok || e.Offering.ID.IsZero() || e.Subject.ID.IsZero():
+			continue
+		}
+		if !seen[id] {
+			seen[id] = true
+			ordered = append(ordered, *e)
+		}
+	}
+	return ordered, nil
+}
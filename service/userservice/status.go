@@ -0,0 +1,53 @@
+package usersvc
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/logger"
+	"BackendCoursyclopedia/model/usermodel"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SuspendUser stops an active or pending user from signing in and revokes
+// the tokens they already hold.
+func (s *UserService) SuspendUser(ctx context.Context, userID, adminID, reason string) (*usermodel.User, error) {
+	if userID == adminID {
+		return nil, apperror.Forbidden("you cannot suspend your own account")
+	}
+	return s.setStatus(ctx, userID, adminID, reason,
+		[]string{usermodel.StatusActive, usermodel.StatusPending, ""}, usermodel.StatusSuspended)
+}
+
+// ReactivateUser lets a suspended user sign in again. Tokens revoked by the
+// suspension stay revoked.
+func (s *UserService) ReactivateUser(ctx context.Context, userID, adminID, reason string) (*usermodel.User, error) {
+	return s.setStatus(ctx, userID, adminID, reason,
+		[]string{usermodel.StatusSuspended}, usermodel.StatusActive)
+}
+
+func (s *UserService) setStatus(ctx context.Context, userID, adminID, reason string, from []string, status string) (*usermodel.User, error) {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, apperror.InvalidID("user", err)
+	}
+
+	change := usermodel.StatusChange{Reason: reason, ChangedBy: adminID, ChangedAt: time.Now().UTC()}
+	revoke := status == usermodel.StatusSuspended || status == usermodel.StatusDeleted
+	user, err := s.UserRepository.SetUserStatus(ctx, id, from, status, change, revoke)
+	if apperror.Is(err, apperror.KindNotFound) {
+		// Tell a missing user apart from one in the wrong status.
+		current, findErr := s.UserRepository.FindUserByID(ctx, userID)
+		if findErr != nil {
+			return nil, findErr
+		}
+		return nil, apperror.Conflict("user is %s and cannot be made %s", current.Status, status)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Info("user status changed", "user_id", userID, "status", status, "admin_id", adminID, "reason", reason)
+	return user, nil
+}
@@ -0,0 +1,126 @@
+package usersvc
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/model/usermodel"
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SetUserStatus follows the Mongo repository: an empty status in from also
+// matches a user stored without one.
+func (f *fakeUsers) SetUserStatus(_ context.Context, userID primitive.ObjectID, from []string, status string, change usermodel.StatusChange, revokeTokens bool) (*usermodel.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	u, ok := f.users[userID]
+	if !ok || !containsString(from, u.Status) {
+		return nil, apperror.NotFound("user not found")
+	}
+	u.Status = status
+	u.StatusChange = &change
+	if revokeTokens {
+		u.TokenVersion++
+	}
+	user := *u
+	return &user, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCheckCanLogin(t *testing.T) {
+	tests := []struct {
+		status  string
+		wantErr apperror.Kind
+	}{
+		{status: usermodel.StatusActive},
+		{status: ""},
+		{status: usermodel.StatusPending, wantErr: apperror.KindForbidden},
+		{status: usermodel.StatusSuspended, wantErr: apperror.KindForbidden},
+		{status: usermodel.StatusDeleted, wantErr: apperror.KindUnauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			err := checkCanLogin(&usermodel.User{Status: tt.status})
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("checkCanLogin() error = %v, want nil", err)
+			}
+			if tt.wantErr != "" && !apperror.Is(err, tt.wantErr) {
+				t.Fatalf("checkCanLogin() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStatusChanges(t *testing.T) {
+	adminID := primitive.NewObjectID().Hex()
+	suspend := func(s *UserService, userID string) error {
+		_, err := s.SuspendUser(context.Background(), userID, adminID, "spam")
+		return err
+	}
+	reactivate := func(s *UserService, userID string) error {
+		_, err := s.ReactivateUser(context.Background(), userID, adminID, "appeal")
+		return err
+	}
+	remove := func(s *UserService, userID string) error {
+		return s.DeleteSpecificUser(context.Background(), userID, adminID)
+	}
+
+	tests := []struct {
+		name        string
+		status      string
+		change      func(s *UserService, userID string) error
+		wantErr     apperror.Kind
+		wantStatus  string
+		wantVersion int
+	}{
+		{name: "suspend active", status: usermodel.StatusActive, change: suspend, wantStatus: usermodel.StatusSuspended, wantVersion: 1},
+		{name: "suspend without a status", status: "", change: suspend, wantStatus: usermodel.StatusSuspended, wantVersion: 1},
+		{name: "suspend deleted", status: usermodel.StatusDeleted, change: suspend, wantErr: apperror.KindConflict, wantStatus: usermodel.StatusDeleted},
+		{name: "reactivate keeps tokens revoked", status: usermodel.StatusSuspended, change: reactivate, wantStatus: usermodel.StatusActive},
+		{name: "reactivate active", status: usermodel.StatusActive, change: reactivate, wantErr: apperror.KindConflict, wantStatus: usermodel.StatusActive},
+		{name: "delete active", status: usermodel.StatusActive, change: remove, wantStatus: usermodel.StatusDeleted, wantVersion: 1},
+		{name: "delete suspended", status: usermodel.StatusSuspended, change: remove, wantStatus: usermodel.StatusDeleted, wantVersion: 1},
+		{name: "delete deleted", status: usermodel.StatusDeleted, change: remove, wantErr: apperror.KindConflict, wantStatus: usermodel.StatusDeleted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := usermodel.User{ID: primitive.NewObjectID(), Email: "student@example.com", Status: tt.status}
+			service, users, _, _ := newTokenTestService(user)
+
+			err := tt.change(service, user.ID.Hex())
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("error = %v, want nil", err)
+			}
+			if tt.wantErr != "" && !apperror.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %s", err, tt.wantErr)
+			}
+			stored, _ := users.FindUserByID(context.Background(), user.ID.Hex())
+			if stored.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", stored.Status, tt.wantStatus)
+			}
+			if stored.TokenVersion != tt.wantVersion {
+				t.Errorf("token version = %d, want %d", stored.TokenVersion, tt.wantVersion)
+			}
+		})
+	}
+
+	t.Run("admins cannot suspend themselves", func(t *testing.T) {
+		admin := usermodel.User{ID: primitive.NewObjectID(), Status: usermodel.StatusActive}
+		service, _, _, _ := newTokenTestService(admin)
+		_, err := service.SuspendUser(context.Background(), admin.ID.Hex(), admin.ID.Hex(), "")
+		if !apperror.Is(err, apperror.KindForbidden) {
+			t.Errorf("error = %v, want forbidden", err)
+		}
+	})
+}
@@ -0,0 +1,222 @@
+package usersvc
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/logger"
+	"BackendCoursyclopedia/model/authtokenmodel"
+	"BackendCoursyclopedia/model/usermodel"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	passwordResetTTL     = time.Hour
+	emailVerificationTTL = 48 * time.Hour
+	// maxTokensPerHour bounds how many emails of each kind one account can
+	// be sent in an hour, however many addresses the requests come from.
+	maxTokensPerHour = 3
+	// backgroundTimeout bounds the lookup and send that ForgotPassword and
+	// ResendVerification leave running after they return.
+	backgroundTimeout = 30 * time.Second
+)
+
+// ForgotPassword emails a password reset link to the user with the given
+// email. It returns before looking the address up, so neither its result
+// nor its timing tells callers which addresses are registered.
+func (s *UserService) ForgotPassword(ctx context.Context, email string) error {
+	s.inBackground(ctx, "password reset", func(ctx context.Context) error {
+		user, err := s.UserRepository.GetUserByEmail(ctx, email)
+		if apperror.Is(err, apperror.KindNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !user.IsActive() && user.Status != usermodel.StatusPending {
+			return nil
+		}
+		return s.sendToken(ctx, *user, authtokenmodel.PurposePasswordReset)
+	})
+	return nil
+}
+
+// ResetPasswordWithToken sets a new password using a link from
+// ForgotPassword. The link also proves the user owns the address, so a
+// pending account is activated.
+func (s *UserService) ResetPasswordWithToken(ctx context.Context, token, newPassword string) error {
+	consumed, err := s.AuthTokenRepository.ConsumeToken(ctx, authtokenmodel.PurposePasswordReset, hashToken(token), time.Now().UTC())
+	if apperror.Is(err, apperror.KindNotFound) {
+		return apperror.InvalidArgument("invalid or expired token")
+	}
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	if err := s.UserRepository.UpdatePassword(ctx, consumed.UserID, hashedPassword); err != nil {
+		return err
+	}
+	// Any other reset links sent to the user stop working.
+	if err := s.AuthTokenRepository.DeleteTokens(ctx, consumed.UserID, authtokenmodel.PurposePasswordReset); err != nil {
+		return err
+	}
+
+	activated, err := s.activate(ctx, consumed)
+	if err != nil {
+		return err
+	}
+	user, err := s.UserRepository.FindUserByID(ctx, consumed.UserID.Hex())
+	if err != nil {
+		return err
+	}
+	if activated {
+		s.notifyAccountCreated(ctx, *user)
+	}
+	s.notifyPasswordReset(ctx, *user)
+	return nil
+}
+
+// VerifyEmail activates the pending account a verification link was sent
+// to.
+func (s *UserService) VerifyEmail(ctx context.Context, token string) error {
+	consumed, err := s.AuthTokenRepository.ConsumeToken(ctx, authtokenmodel.PurposeEmailVerification, hashToken(token), time.Now().UTC())
+	if apperror.Is(err, apperror.KindNotFound) {
+		return apperror.InvalidArgument("invalid or expired token")
+	}
+	if err != nil {
+		return err
+	}
+
+	activated, err := s.activate(ctx, consumed)
+	if err != nil || !activated {
+		return err
+	}
+	user, err := s.UserRepository.FindUserByID(ctx, consumed.UserID.Hex())
+	if err != nil {
+		return err
+	}
+	s.notifyAccountCreated(ctx, *user)
+	return nil
+}
+
+// ResendVerification emails a new verification link if the email belongs
+// to a pending account. Like ForgotPassword it returns at once and reveals
+// nothing about the address.
+func (s *UserService) ResendVerification(ctx context.Context, email string) error {
+	s.inBackground(ctx, "email verification", func(ctx context.Context) error {
+		user, err := s.UserRepository.GetUserByEmail(ctx, email)
+		if apperror.Is(err, apperror.KindNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if user.Status != usermodel.StatusPending {
+			return nil
+		}
+		return s.sendToken(ctx, *user, authtokenmodel.PurposeEmailVerification)
+	})
+	return nil
+}
+
+// inBackground runs fn detached from the request, which may end first, and
+// logs its error since nobody is left to return it to. WaitBackground waits
+// for it.
+func (s *UserService) inBackground(ctx context.Context, what string, fn func(context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), backgroundTimeout)
+	s.background.Add(1)
+	go func() {
+		defer s.background.Done()
+		defer cancel()
+		if err := fn(ctx); err != nil {
+			logger.FromContext(ctx).Error("failed to send "+what+" email", "error", err)
+		}
+	}()
+}
+
+// WaitBackground waits until the emails that ForgotPassword and
+// ResendVerification left running have been sent, or ctx is done.
+func (s *UserService) WaitBackground(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.background.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// activate moves the user owning a consumed token from pending to active
+// and drops their remaining verification links.
+func (s *UserService) activate(ctx context.Context, token *authtokenmodel.Token) (bool, error) {
+	activated, err := s.UserRepository.ActivatePendingUser(ctx, token.UserID)
+	if err != nil {
+		return false, err
+	}
+	if activated {
+		logger.FromContext(ctx).Info("user email verified", "user_id", token.UserID.Hex())
+	}
+	return activated, s.AuthTokenRepository.DeleteTokens(ctx, token.UserID, authtokenmodel.PurposeEmailVerification)
+}
+
+// sendToken issues a token for purpose and emails the link carrying it,
+// unless the user has already been sent maxTokensPerHour of them. Only the
+// token's hash is stored.
+func (s *UserService) sendToken(ctx context.Context, user usermodel.User, purpose string) error {
+	now := time.Now().UTC()
+	log := logger.FromContext(ctx).With("user_id", user.ID.Hex(), "purpose", purpose)
+
+	recent, err := s.AuthTokenRepository.CountTokensSince(ctx, user.ID, purpose, now.Add(-time.Hour))
+	if err != nil {
+		return err
+	}
+	if recent >= maxTokensPerHour {
+		log.Warn("too many tokens requested; not sending another")
+		return nil
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	ttl, path := passwordResetTTL, "/reset-password"
+	if purpose == authtokenmodel.PurposeEmailVerification {
+		ttl, path = emailVerificationTTL, "/verify-email"
+	}
+	err = s.AuthTokenRepository.CreateToken(ctx, authtokenmodel.Token{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		Hash:      hashToken(token),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	link := strings.TrimSuffix(s.AppURL, "/") + path + "?token=" + url.QueryEscape(token)
+	if purpose == authtokenmodel.PurposeEmailVerification {
+		return s.NotificationService.NotifyEmailVerification(ctx, user, link, ttl)
+	}
+	return s.NotificationService.NotifyPasswordResetRequested(ctx, user, link, ttl)
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
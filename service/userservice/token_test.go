@@ -0,0 +1,428 @@
+package usersvc
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/model/authtokenmodel"
+	"BackendCoursyclopedia/model/usermodel"
+	"BackendCoursyclopedia/repository/authtokenrepository"
+	userrepo "BackendCoursyclopedia/repository/userrepository"
+	"BackendCoursyclopedia/service/notificationservice"
+	"context"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type fakeUsers struct {
+	userrepo.IUserRepository
+	mu    sync.Mutex
+	users map[primitive.ObjectID]*usermodel.User
+}
+
+func newFakeUsers(users ...usermodel.User) *fakeUsers {
+	f := &fakeUsers{users: map[primitive.ObjectID]*usermodel.User{}}
+	for i := range users {
+		f.users[users[i].ID] = &users[i]
+	}
+	return f
+}
+
+func (f *fakeUsers) GetUserByEmail(_ context.Context, email string) (*usermodel.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, u := range f.users {
+		if u.Email == email {
+			user := *u
+			return &user, nil
+		}
+	}
+	return nil, apperror.NotFound("user not found")
+}
+
+func (f *fakeUsers) FindUserByID(_ context.Context, userID string) (*usermodel.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id, _ := primitive.ObjectIDFromHex(userID)
+	u, ok := f.users[id]
+	if !ok {
+		return nil, apperror.NotFound("user not found")
+	}
+	user := *u
+	return &user, nil
+}
+
+func (f *fakeUsers) UpdatePassword(_ context.Context, userID primitive.ObjectID, hashedPassword string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.users[userID].Password = hashedPassword
+	f.users[userID].TokenVersion++
+	return nil
+}
+
+func (f *fakeUsers) ActivatePendingUser(_ context.Context, userID primitive.ObjectID) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	u := f.users[userID]
+	if u.Status != usermodel.StatusPending {
+		return false, nil
+	}
+	u.Status = usermodel.StatusActive
+	return true, nil
+}
+
+func (f *fakeUsers) status(id primitive.ObjectID) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.users[id].Status
+}
+
+// fakeTokens consumes tokens the way the Mongo repository does: once, and
+// only before they expire.
+type fakeTokens struct {
+	authtokenrepository.IAuthTokenRepository
+	mu     sync.Mutex
+	tokens []authtokenmodel.Token
+}
+
+func (f *fakeTokens) CreateToken(_ context.Context, token authtokenmodel.Token) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tokens = append(f.tokens, token)
+	return nil
+}
+
+func (f *fakeTokens) ConsumeToken(_ context.Context, purpose, hash string, now time.Time) (*authtokenmodel.Token, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, t := range f.tokens {
+		if t.Purpose == purpose && t.Hash == hash && t.ExpiresAt.After(now) {
+			f.tokens = append(f.tokens[:i], f.tokens[i+1:]...)
+			return &t, nil
+		}
+	}
+	return nil, apperror.NotFound("token not found")
+}
+
+func (f *fakeTokens) CountTokensSince(_ context.Context, userID primitive.ObjectID, purpose string, since time.Time) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var n int64
+	for _, t := range f.tokens {
+		if t.UserID == userID && t.Purpose == purpose && !t.CreatedAt.Before(since) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f *fakeTokens) DeleteTokens(_ context.Context, userID primitive.ObjectID, purpose string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	kept := f.tokens[:0]
+	for _, t := range f.tokens {
+		if t.UserID != userID || t.Purpose != purpose {
+			kept = append(kept, t)
+		}
+	}
+	f.tokens = kept
+	return nil
+}
+
+// expire moves every token's expiry into the past.
+func (f *fakeTokens) expire() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := range f.tokens {
+		f.tokens[i].ExpiresAt = time.Now().Add(-time.Minute)
+	}
+}
+
+// fakeMailer records the emails queued, and the tokens in their links.
+type fakeMailer struct {
+	notificationservice.INotificationService
+	mu     sync.Mutex
+	emails []string
+	tokens []string
+}
+
+func (f *fakeMailer) record(kind, link string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.emails = append(f.emails, kind)
+	if link != "" {
+		u, _ := url.Parse(link)
+		f.tokens = append(f.tokens, u.Query().Get("token"))
+	}
+}
+
+func (f *fakeMailer) NotifyAccountCreated(context.Context, usermodel.User) error {
+	f.record("account_created", "")
+	return nil
+}
+
+func (f *fakeMailer) NotifyPasswordReset(context.Context, usermodel.User) error {
+	f.record("password_reset", "")
+	return nil
+}
+
+func (f *fakeMailer) NotifyPasswordResetRequested(_ context.Context, _ usermodel.User, link string, _ time.Duration) error {
+	f.record("password_reset_requested", link)
+	return nil
+}
+
+func (f *fakeMailer) NotifyEmailVerification(_ context.Context, _ usermodel.User, link string, _ time.Duration) error {
+	f.record("email_verification", link)
+	return nil
+}
+
+func (f *fakeMailer) lastToken(t *testing.T) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.tokens) == 0 {
+		t.Fatal("no link was emailed")
+	}
+	return f.tokens[len(f.tokens)-1]
+}
+
+func newTokenTestService(users ...usermodel.User) (*UserService, *fakeUsers, *fakeTokens, *fakeMailer) {
+	userRepo, tokenRepo, mailer := newFakeUsers(users...), &fakeTokens{}, &fakeMailer{}
+	return &UserService{
+		UserRepository:      userRepo,
+		AuthTokenRepository: tokenRepo,
+		NotificationService: mailer,
+		AppURL:              "https://app.example.com",
+	}, userRepo, tokenRepo, mailer
+}
+
+func TestVerifyEmail(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		use        func(s *UserService, tokens *fakeTokens, token string) error
+		wantErr    apperror.Kind
+		wantStatus string
+		wantEmails []string
+	}{
+		{
+			name:   "activates a pending user",
+			status: usermodel.StatusPending,
+			use: func(s *UserService, _ *fakeTokens, token string) error {
+				return s.VerifyEmail(context.Background(), token)
+			},
+			wantStatus: usermodel.StatusActive,
+			wantEmails: []string{"email_verification", "account_created"},
+		},
+		{
+			name:   "works once",
+			status: usermodel.StatusPending,
+			use: func(s *UserService, _ *fakeTokens, token string) error {
+				if err := s.VerifyEmail(context.Background(), token); err != nil {
+					return err
+				}
+				return s.VerifyEmail(context.Background(), token)
+			},
+			wantErr:    apperror.KindInvalidArgument,
+			wantStatus: usermodel.StatusActive,
+			wantEmails: []string{"email_verification", "account_created"},
+		},
+		{
+			name:   "expired",
+			status: usermodel.StatusPending,
+			use: func(s *UserService, tokens *fakeTokens, token string) error {
+				tokens.expire()
+				return s.VerifyEmail(context.Background(), token)
+			},
+			wantErr:    apperror.KindInvalidArgument,
+			wantStatus: usermodel.StatusPending,
+			wantEmails: []string{"email_verification"},
+		},
+		{
+			name:   "not a reset token",
+			status: usermodel.StatusPending,
+			use: func(s *UserService, _ *fakeTokens, token string) error {
+				return s.ResetPasswordWithToken(context.Background(), token, "newpassword123")
+			},
+			wantErr:    apperror.KindInvalidArgument,
+			wantStatus: usermodel.StatusPending,
+			wantEmails: []string{"email_verification"},
+		},
+		{
+			name:   "already active",
+			status: usermodel.StatusActive,
+			use: func(s *UserService, _ *fakeTokens, token string) error {
+				return s.VerifyEmail(context.Background(), token)
+			},
+			wantStatus: usermodel.StatusActive,
+			wantEmails: []string{"email_verification"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := usermodel.User{ID: primitive.NewObjectID(), Email: "student@example.com", Status: tt.status}
+			service, users, tokens, mailer := newTokenTestService(user)
+
+			if err := service.sendToken(context.Background(), user, authtokenmodel.PurposeEmailVerification); err != nil {
+				t.Fatalf("sendToken() error = %v", err)
+			}
+			err := tt.use(service, tokens, mailer.lastToken(t))
+
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("error = %v, want nil", err)
+			}
+			if tt.wantErr != "" && !apperror.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %s", err, tt.wantErr)
+			}
+			if got := users.status(user.ID); got != tt.wantStatus {
+				t.Errorf("status = %q, want %q", got, tt.wantStatus)
+			}
+			if !equalStrings(mailer.emails, tt.wantEmails) {
+				t.Errorf("emails = %v, want %v", mailer.emails, tt.wantEmails)
+			}
+		})
+	}
+}
+
+func TestResetPasswordWithToken(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		wantStatus string
+		wantEmails []string
+	}{
+		{
+			name:       "active user",
+			status:     usermodel.StatusActive,
+			wantStatus: usermodel.StatusActive,
+			wantEmails: []string{"password_reset_requested", "password_reset_requested", "password_reset"},
+		},
+		{
+			name:       "the link also verifies a pending user",
+			status:     usermodel.StatusPending,
+			wantStatus: usermodel.StatusActive,
+			wantEmails: []string{"password_reset_requested", "password_reset_requested", "account_created", "password_reset"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := usermodel.User{ID: primitive.NewObjectID(), Email: "student@example.com", Status: tt.status, Password: "old"}
+			service, users, _, mailer := newTokenTestService(user)
+
+			for i := 0; i < 2; i++ {
+				if err := service.sendToken(context.Background(), user, authtokenmodel.PurposePasswordReset); err != nil {
+					t.Fatalf("sendToken() error = %v", err)
+				}
+			}
+			first, second := mailer.tokens[0], mailer.tokens[1]
+
+			if err := service.ResetPasswordWithToken(context.Background(), second, "newpassword123"); err != nil {
+				t.Fatalf("ResetPasswordWithToken() error = %v", err)
+			}
+			stored, _ := users.FindUserByID(context.Background(), user.ID.Hex())
+			if !CheckPasswordHash("newpassword123", stored.Password) {
+				t.Error("password was not changed")
+			}
+			if stored.TokenVersion != 1 {
+				t.Errorf("token version = %d, want 1: the reset must sign out every session", stored.TokenVersion)
+			}
+			if stored.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", stored.Status, tt.wantStatus)
+			}
+			if !equalStrings(mailer.emails, tt.wantEmails) {
+				t.Errorf("emails = %v, want %v", mailer.emails, tt.wantEmails)
+			}
+
+			// A successful reset invalidates the other links sent to the user.
+			err := service.ResetPasswordWithToken(context.Background(), first, "anotherpassword123")
+			if !apperror.Is(err, apperror.KindInvalidArgument) {
+				t.Errorf("earlier link error = %v, want invalid-argument", err)
+			}
+		})
+	}
+}
+
+func TestSendTokenPerAccountCap(t *testing.T) {
+	user := usermodel.User{ID: primitive.NewObjectID(), Email: "student@example.com", Status: usermodel.StatusActive}
+	other := usermodel.User{ID: primitive.NewObjectID(), Email: "other@example.com", Status: usermodel.StatusActive}
+	service, _, tokens, mailer := newTokenTestService(user, other)
+
+	for i := 0; i < maxTokensPerHour+2; i++ {
+		if err := service.sendToken(context.Background(), user, authtokenmodel.PurposePasswordReset); err != nil {
+			t.Fatalf("sendToken() error = %v", err)
+		}
+	}
+	if len(mailer.emails) != maxTokensPerHour || len(tokens.tokens) != maxTokensPerHour {
+		t.Errorf("sent %d emails and stored %d tokens, want %d of each", len(mailer.emails), len(tokens.tokens), maxTokensPerHour)
+	}
+
+	// The cap is per account and per kind.
+	if err := service.sendToken(context.Background(), user, authtokenmodel.PurposeEmailVerification); err != nil {
+		t.Fatalf("sendToken() error = %v", err)
+	}
+	if err := service.sendToken(context.Background(), other, authtokenmodel.PurposePasswordReset); err != nil {
+		t.Fatalf("sendToken() error = %v", err)
+	}
+	if len(mailer.emails) != maxTokensPerHour+2 {
+		t.Errorf("sent %d emails, want %d", len(mailer.emails), maxTokensPerHour+2)
+	}
+
+	for _, token := range tokens.tokens {
+		for _, raw := range mailer.tokens {
+			if token.Hash == raw {
+				t.Fatal("a raw token was stored instead of its hash")
+			}
+		}
+	}
+}
+
+func TestForgotPassword(t *testing.T) {
+	tests := []struct {
+		name       string
+		email      string
+		wantEmails []string
+	}{
+		{name: "registered", email: "student@example.com", wantEmails: []string{"password_reset_requested"}},
+		{name: "unknown", email: "nobody@example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := usermodel.User{ID: primitive.NewObjectID(), Email: "student@example.com", Status: usermodel.StatusActive}
+			service, _, _, mailer := newTokenTestService(user)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if err := service.ForgotPassword(ctx, tt.email); err != nil {
+				t.Fatalf("ForgotPassword() error = %v", err)
+			}
+			// The request ending does not stop the email.
+			cancel()
+
+			waitCtx, waitCancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer waitCancel()
+			if err := service.WaitBackground(waitCtx); err != nil {
+				t.Fatalf("WaitBackground() error = %v", err)
+			}
+			mailer.mu.Lock()
+			defer mailer.mu.Unlock()
+			if !equalStrings(mailer.emails, tt.wantEmails) {
+				t.Errorf("emails = %v, want %v", mailer.emails, tt.wantEmails)
+			}
+		})
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
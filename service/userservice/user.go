@@ -1,17 +1,25 @@
 package usersvc
 
 import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/logger"
+	"BackendCoursyclopedia/metrics"
+	"BackendCoursyclopedia/model/authtokenmodel"
 	"BackendCoursyclopedia/model/usermodel"
+	"BackendCoursyclopedia/repository/authtokenrepository"
 	userrepo "BackendCoursyclopedia/repository/userrepository"
+	"BackendCoursyclopedia/service/notificationservice"
 	"context"
-	"errors"
 	"fmt"
+	"sync"
 
 	"time"
 
 	"os"
 
 	"github.com/golang-jwt/jwt/v4"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -20,20 +28,43 @@ type IUserService interface {
 	GetUserByID(ctx context.Context, userID string) (*usermodel.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*usermodel.User, error)
 	CreateNewUser(ctx context.Context, user usermodel.User) (*usermodel.User, error)
-	DeleteSpecificUser(ctx context.Context, userID string) error
-	UpdateSpecificByID(ctx context.Context, userID string, updateUser usermodel.User) (*usermodel.User, error)
+	DeleteSpecificUser(ctx context.Context, userID, actorID string) error
+	UpdateSpecificByID(ctx context.Context, actor usermodel.User, userID string, request usermodel.UserUpdateRequest) (*usermodel.User, error)
 	DropAllUsers(ctx context.Context) error
 	Login(ctx context.Context, email, password string) (*usermodel.User, string, error)
 	GoogleLogin(ctx context.Context, email, firebaseId string) (*usermodel.User, string, error)
+	ResetPassword(ctx context.Context, email, newPassword string) error
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPasswordWithToken(ctx context.Context, token, newPassword string) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, email string) error
+	SuspendUser(ctx context.Context, userID, adminID, reason string) (*usermodel.User, error)
+	ReactivateUser(ctx context.Context, userID, adminID, reason string) (*usermodel.User, error)
+	WaitBackground(ctx context.Context) error
 }
 
 type UserService struct {
-	UserRepository userrepo.IUserRepository
+	UserRepository      userrepo.IUserRepository
+	AuthTokenRepository authtokenrepository.IAuthTokenRepository
+	NotificationService notificationservice.INotificationService
+	// AppURL is where the web app is served; the links in password reset
+	// and verification emails point at its pages.
+	AppURL string
+
+	// background tracks the emails sent after their request has returned.
+	background sync.WaitGroup
 }
 
-func NewUserService(userRepo userrepo.IUserRepository) IUserService {
+func NewUserService(userRepo userrepo.IUserRepository, authTokenRepo authtokenrepository.IAuthTokenRepository, notificationService notificationservice.INotificationService) IUserService {
+	appURL := os.Getenv("APP_BASE_URL")
+	if appURL == "" {
+		appURL = "http://localhost:3000"
+	}
 	return &UserService{
-		UserRepository: userRepo,
+		UserRepository:      userRepo,
+		AuthTokenRepository: authTokenRepo,
+		NotificationService: notificationService,
+		AppURL:              appURL,
 	}
 }
 
@@ -67,6 +98,9 @@ func (s *UserService) GetUserByEmail(ctx context.Context, email string) (*usermo
 // 	return s.UserRepository.CreateUser(ctx, user)
 // }
 
+// CreateNewUser stores a new user. A user created without a status is
+// pending and is emailed a link to verify their address; the welcome email
+// follows once they are active.
 func (s *UserService) CreateNewUser(ctx context.Context, user usermodel.User) (*usermodel.User, error) {
 	// Hash the password before saving it to the database
 	hashedPassword, err := HashPassword(user.Password)
@@ -74,26 +108,112 @@ func (s *UserService) CreateNewUser(ctx context.Context, user usermodel.User) (*
 		return nil, err
 	}
 	user.Password = hashedPassword // Store the hashed password
+	if user.Status == "" {
+		user.Status = usermodel.StatusPending
+	}
 
-	return s.UserRepository.CreateUser(ctx, user)
+	created, err := s.UserRepository.CreateUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if created.Status != usermodel.StatusPending {
+		s.notifyAccountCreated(ctx, *created)
+	} else if err := s.sendToken(ctx, *created, authtokenmodel.PurposeEmailVerification); err != nil {
+		// The user can ask for another link.
+		logger.FromContext(ctx).Error("failed to send verification email", "user_id", created.ID.Hex(), "error", err)
+	}
+	return created, nil
 }
 
-func (s *UserService) DeleteSpecificUser(ctx context.Context, userID string) error {
-	return s.UserRepository.DeleteUserByID(ctx, userID)
+// DeleteSpecificUser closes an account. The user is kept with the deleted
+// status, for its history, and the tokens they hold are revoked.
+func (s *UserService) DeleteSpecificUser(ctx context.Context, userID, actorID string) error {
+	_, err := s.setStatus(ctx, userID, actorID, "account deleted",
+		[]string{usermodel.StatusActive, usermodel.StatusPending, usermodel.StatusSuspended, ""}, usermodel.StatusDeleted)
+	return err
 }
 
-func (s *UserService) UpdateSpecificByID(ctx context.Context, userID string, updateUser usermodel.User) (*usermodel.User, error) {
-	// Check if the password field is set in updateUser
-	if updateUser.Password != "" {
-		// Hash the new password before updating
-		hashedPassword, err := HashPassword(updateUser.Password)
+// UpdateSpecificByID changes the profile fields in request. Users may
+// update only their own account; admins may update any. A new password is
+// stored through UpdatePassword, like every other password change.
+func (s *UserService) UpdateSpecificByID(ctx context.Context, actor usermodel.User, userID string, request usermodel.UserUpdateRequest) (*usermodel.User, error) {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, apperror.InvalidID("user", err)
+	}
+	if actor.ID != id && actor.Role.Slug != "admin" {
+		return nil, apperror.Forbidden("you can only update your own account")
+	}
+
+	fields := bson.M{}
+	if request.PhoneNumber != "" {
+		fields["phoneNumber"] = request.PhoneNumber
+	}
+	if request.Profile.FirstName != "" {
+		fields["profile.firstName"] = request.Profile.FirstName
+	}
+	if request.Profile.LastName != "" {
+		fields["profile.lastName"] = request.Profile.LastName
+	}
+	if len(fields) > 0 {
+		if _, err := s.UserRepository.UpdateUserByID(ctx, id, fields); err != nil {
+			return nil, err
+		}
+	}
+
+	if request.Password != "" {
+		hashedPassword, err := HashPassword(request.Password)
 		if err != nil {
 			return nil, err
 		}
-		updateUser.Password = hashedPassword
+		if err := s.UserRepository.UpdatePassword(ctx, id, hashedPassword); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := s.UserRepository.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Password != "" {
+		s.notifyPasswordReset(ctx, *updated)
+	}
+	return updated, nil
+}
+
+// ResetPassword replaces the password of the user with the given email.
+func (s *UserService) ResetPassword(ctx context.Context, email, newPassword string) error {
+	user, err := s.UserRepository.GetUserByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	if err := s.UserRepository.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
+		return err
+	}
+	s.notifyPasswordReset(ctx, *user)
+	return nil
+}
+
+// notifyAccountCreated welcomes an active user. The account stands even if
+// the email cannot be queued.
+func (s *UserService) notifyAccountCreated(ctx context.Context, user usermodel.User) {
+	if err := s.NotificationService.NotifyAccountCreated(ctx, user); err != nil {
+		logger.FromContext(ctx).Error("failed to queue account created email", "user_id", user.ID.Hex(), "error", err)
 	}
+}
 
-	return s.UserRepository.UpdateUserByID(ctx, userID, updateUser)
+// notifyPasswordReset tells the user their password changed. The change
+// stands even if the email cannot be queued.
+func (s *UserService) notifyPasswordReset(ctx context.Context, user usermodel.User) {
+	if err := s.NotificationService.NotifyPasswordReset(ctx, user); err != nil {
+		logger.FromContext(ctx).Error("failed to queue password reset email", "user_id", user.ID.Hex(), "error", err)
+	}
 }
 
 func (s *UserService) DropAllUsers(ctx context.Context) error {
@@ -101,11 +221,18 @@ func (s *UserService) DropAllUsers(ctx context.Context) error {
 }
 
 func generateJWT(user *usermodel.User) (string, error) {
-	expirationTime := time.Now().Add(2 * time.Hour)
-
-	claims := jwt.RegisteredClaims{
-		Subject:   user.ID.Hex(),
-		ExpiresAt: jwt.NewNumericDate(expirationTime),
+	now := time.Now()
+	expirationTime := now.Add(2 * time.Hour)
+
+	// JWTMiddleware refuses the token once the user's TokenVersion has
+	// moved past the one it carries.
+	claims := usermodel.TokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user.ID.Hex(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expirationTime),
+		},
+		TokenVersion: user.TokenVersion,
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -118,17 +245,25 @@ func generateJWT(user *usermodel.User) (string, error) {
 	return tokenString, nil
 }
 
-func (s *UserService) Login(ctx context.Context, email, password string) (*usermodel.User, string, error) {
-	user, err := s.UserRepository.GetUserByEmail(ctx, email)
+func (s *UserService) Login(ctx context.Context, email, password string) (user *usermodel.User, token string, err error) {
+	defer func() { metrics.ObserveLogin(metrics.LoginMethodPassword, err) }()
+
+	user, err = s.UserRepository.GetUserByEmail(ctx, email)
+	if apperror.Is(err, apperror.KindNotFound) {
+		return nil, "", apperror.Unauthenticated("invalid credentials")
+	}
 	if err != nil {
-		return nil, "", errors.New("invalid credentials")
+		return nil, "", err
 	}
 
 	if !CheckPasswordHash(password, user.Password) {
-		return nil, "", errors.New("invalid credentials")
+		return nil, "", apperror.Unauthenticated("invalid credentials")
+	}
+	if err = checkCanLogin(user); err != nil {
+		return nil, "", err
 	}
 
-	token, err := generateJWT(user)
+	token, err = generateJWT(user)
 	if err != nil {
 		return nil, "", err
 	}
@@ -155,8 +290,19 @@ func (s *UserService) Login(ctx context.Context, email, password string) (*userm
 // 	return user, token, nil
 // }
 
-func (s *UserService) GoogleLogin(ctx context.Context, email, firebaseId string) (*usermodel.User, string, error) {
-	user, err := s.UserRepository.GetUserByEmail(ctx, email)
+func (s *UserService) GoogleLogin(ctx context.Context, email, firebaseId string) (user *usermodel.User, token string, err error) {
+	defer func() { metrics.ObserveLogin(metrics.LoginMethodGoogle, err) }()
+
+	// Users who signed up with a password have no Firebase ID, so an empty
+	// one would match them.
+	if firebaseId == "" {
+		return nil, "", apperror.Unauthenticated("invalid Firebase ID")
+	}
+
+	user, err = s.UserRepository.GetUserByEmail(ctx, email)
+	if err != nil && !apperror.Is(err, apperror.KindNotFound) {
+		return nil, "", err
+	}
 	if err != nil {
 		newUser := usermodel.User{
 			Email: email,
@@ -167,7 +313,7 @@ func (s *UserService) GoogleLogin(ctx context.Context, email, firebaseId string)
 			}{
 				FirebaseId: firebaseId,
 			},
-			Status: "active",
+			Status: usermodel.StatusActive,
 			Role: usermodel.Role{
 				Name:        "user",
 				Slug:        "user",
@@ -177,20 +323,37 @@ func (s *UserService) GoogleLogin(ctx context.Context, email, firebaseId string)
 		}
 		user, err = s.CreateNewUser(ctx, newUser)
 		if err != nil {
-			return nil, "", fmt.Errorf("failed to create new user: %v", err)
+			return nil, "", fmt.Errorf("failed to create new user: %w", err)
 		}
 	} else {
 		// If user found, check if the provided Firebase ID matches the stored Firebase ID
 		if user.Profile.FirebaseId != firebaseId {
-			return nil, "", errors.New("invalid Firebase ID")
+			return nil, "", apperror.Unauthenticated("invalid Firebase ID")
+		}
+		if err = checkCanLogin(user); err != nil {
+			return nil, "", err
 		}
 	}
 
 	// Generate JWT token
-	token, err := generateJWT(user)
+	token, err = generateJWT(user)
 	if err != nil {
 		return nil, "", err
 	}
 
 	return user, token, nil
 }
+
+// checkCanLogin allows only active users to sign in. Deleted users get the
+// same answer as unknown ones.
+func checkCanLogin(user *usermodel.User) error {
+	switch {
+	case user.IsActive():
+		return nil
+	case user.Status == usermodel.StatusPending:
+		return apperror.Forbidden("email address not verified")
+	case user.Status == usermodel.StatusSuspended:
+		return apperror.Forbidden("account suspended")
+	}
+	return apperror.Unauthenticated("invalid credentials")
+}
@@ -0,0 +1,109 @@
+package usersvc
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/model/usermodel"
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (f *fakeUsers) UpdateUserByID(_ context.Context, userID primitive.ObjectID, fields bson.M) (*usermodel.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	u, ok := f.users[userID]
+	if !ok {
+		return nil, apperror.NotFound("user not found")
+	}
+	for field, value := range fields {
+		switch field {
+		case "phoneNumber":
+			u.PhoneNumber = value.(string)
+		case "profile.firstName":
+			u.Profile.FirstName = value.(string)
+		case "profile.lastName":
+			u.Profile.LastName = value.(string)
+		}
+	}
+	user := *u
+	return &user, nil
+}
+
+func TestUpdateSpecificByID(t *testing.T) {
+	owner := usermodel.User{ID: primitive.NewObjectID(), Email: "student@example.com", Password: "old", PhoneNumber: "0800000000"}
+	other := usermodel.User{ID: primitive.NewObjectID(), Email: "other@example.com"}
+	admin := usermodel.User{ID: primitive.NewObjectID(), Email: "admin@example.com", Role: usermodel.Role{Slug: "admin"}}
+
+	tests := []struct {
+		name         string
+		actor        usermodel.User
+		request      usermodel.UserUpdateRequest
+		wantErr      apperror.Kind
+		wantPhone    string
+		wantPassword bool
+		wantVersion  int
+		wantEmails   []string
+	}{
+		{
+			name:      "own account",
+			actor:     owner,
+			request:   usermodel.UserUpdateRequest{PhoneNumber: "0811111111"},
+			wantPhone: "0811111111",
+		},
+		{
+			name:      "admin",
+			actor:     admin,
+			request:   usermodel.UserUpdateRequest{PhoneNumber: "0811111111"},
+			wantPhone: "0811111111",
+		},
+		{
+			name:      "someone else's account",
+			actor:     other,
+			request:   usermodel.UserUpdateRequest{PhoneNumber: "0811111111"},
+			wantErr:   apperror.KindForbidden,
+			wantPhone: "0800000000",
+		},
+		{
+			name:         "password change signs out every session",
+			actor:        owner,
+			request:      usermodel.UserUpdateRequest{Password: "newpassword123"},
+			wantPhone:    "0800000000",
+			wantPassword: true,
+			wantVersion:  1,
+			wantEmails:   []string{"password_reset"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, users, _, mailer := newTokenTestService(owner, other, admin)
+
+			updated, err := service.UpdateSpecificByID(context.Background(), tt.actor, owner.ID.Hex(), tt.request)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("error = %v, want nil", err)
+			}
+			if tt.wantErr != "" && !apperror.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %s", err, tt.wantErr)
+			}
+			if err == nil && updated.Email != owner.Email {
+				t.Errorf("returned email = %q, want the stored %q", updated.Email, owner.Email)
+			}
+
+			stored, _ := users.FindUserByID(context.Background(), owner.ID.Hex())
+			if stored.PhoneNumber != tt.wantPhone {
+				t.Errorf("phone = %q, want %q", stored.PhoneNumber, tt.wantPhone)
+			}
+			if got := CheckPasswordHash("newpassword123", stored.Password); got != tt.wantPassword {
+				t.Errorf("password changed = %v, want %v", got, tt.wantPassword)
+			}
+			if stored.TokenVersion != tt.wantVersion {
+				t.Errorf("token version = %d, want %d", stored.TokenVersion, tt.wantVersion)
+			}
+			if !equalStrings(mailer.emails, tt.wantEmails) {
+				t.Errorf("emails = %v, want %v", mailer.emails, tt.wantEmails)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package tracing
+
+import (
+	"BackendCoursyclopedia/buildinfo"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "BackendCoursyclopedia"
+
+// Tracer returns the application tracer. It is safe to call before Init; spans
+// are dropped until a provider is installed.
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// Init installs the global tracer provider and W3C trace-context propagator.
+//
+// OTEL_TRACES_EXPORTER selects the exporter:
+//   - otlp: OTLP over HTTP, configured with the standard OTEL_EXPORTER_OTLP_* variables
+//   - stdout: pretty-printed spans on stdout
+//   - file: spans appended as JSON to OTEL_TRACES_FILE (default traces.json)
+//   - none or unset: spans are not exported
+//
+// The returned function flushes pending spans and must be called on shutdown.
+func Init(ctx context.Context) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	exporterName := strings.ToLower(os.Getenv("OTEL_TRACES_EXPORTER"))
+	if exporterName == "" || exporterName == "none" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var (
+		exporter sdktrace.SpanExporter
+		closer   io.Closer
+		err      error
+	)
+	switch exporterName {
+	case "otlp":
+		exporter, err = otlptracehttp.New(ctx)
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case "file":
+		path := os.Getenv("OTEL_TRACES_FILE")
+		if path == "" {
+			path = "traces.json"
+		}
+		var f *os.File
+		f, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err == nil {
+			closer = f
+			exporter, err = stdouttrace.New(stdouttrace.WithWriter(f))
+		}
+	default:
+		return nil, fmt.Errorf("unknown OTEL_TRACES_EXPORTER %q", exporterName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	serviceName := os.Getenv("OTEL_SERVICE_NAME")
+	if serviceName == "" {
+		serviceName = "coursyclopedia-backend"
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(buildinfo.Commit),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closer != nil {
+			if cerr := closer.Close(); err == nil {
+				err = cerr
+			}
+		}
+		return err
+	}, nil
+}
@@ -0,0 +1,112 @@
+package validation
+
+import (
+	"BackendCoursyclopedia/apperror"
+	"BackendCoursyclopedia/model/subjectmodel"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// subjectCodePattern matches codes such as "CSX3003" or "BG1301".
+var subjectCodePattern = regexp.MustCompile(`^[A-Z]{2,4}[0-9]{3,4}$`)
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	// Report fields by their JSON name so clients can map errors back to input.
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+
+	_ = v.RegisterValidation("subjectcode", func(fl validator.FieldLevel) bool {
+		return subjectCodePattern.MatchString(fl.Field().String())
+	})
+	_ = v.RegisterValidation("subjectstatus", func(fl validator.FieldLevel) bool {
+		return subjectmodel.IsValidStatus(fl.Field().String())
+	})
+
+	return v
+}
+
+// Struct validates s against its `validate` tags and returns an
+// apperror.KindValidation error listing every invalid field.
+func Struct(s interface{}) error {
+	err := validate.Struct(s)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return err
+	}
+
+	fields := make([]apperror.FieldError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		fields = append(fields, apperror.FieldError{
+			Field:   fieldPath(fe),
+			Rule:    fe.Tag(),
+			Message: message(fe),
+		})
+	}
+	return apperror.Validation(fields)
+}
+
+// fieldPath drops the top-level struct name from the namespace, so
+// "CreateSubjectRequest.professors[0]" becomes "professors[0]".
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.Index(ns, "."); i >= 0 {
+		return ns[i+1:]
+	}
+	return ns
+}
+
+func message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", "required_without":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "min":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("must be at least %s characters", fe.Param())
+		}
+		return fmt.Sprintf("must be at least %s", fe.Param())
+	case "max":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("must be at most %s characters", fe.Param())
+		}
+		if fe.Kind() == reflect.Slice {
+			return fmt.Sprintf("must contain at most %s items", fe.Param())
+		}
+		return fmt.Sprintf("must be at most %s", fe.Param())
+	case "mongodb":
+		return "must be a valid ID"
+	case "subjectcode":
+		return "must be 2-4 uppercase letters followed by 3-4 digits, e.g. CSX3003"
+	case "subjectstatus":
+		return "must be one of " + strings.Join(subjectmodel.Statuses, ", ")
+	case "datetime":
+		return "must match the format " + fe.Param()
+	case "bcp47_language_tag":
+		return "must be a language tag such as en or th-TH"
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	}
+	return "is invalid"
+}